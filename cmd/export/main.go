@@ -0,0 +1,122 @@
+// Command export downloads a chat's history through the ExportChat RPC.
+//
+//	go run ./cmd/export -chat 7 -format html -token "$CHAT_TOKEN"
+//
+// The file is named after the server's suggestion unless -out is given; "-out -" writes to stdout.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8000", "gRPC server address")
+	token := flag.String("token", os.Getenv("CHAT_TOKEN"), "auth token, defaults to $CHAT_TOKEN")
+	apiKey := flag.String("api-key", os.Getenv("CHAT_API_KEY"), "bot API key with the chats:manage scope, defaults to $CHAT_API_KEY")
+	chatID := flag.Int64("chat", 0, "chat ID to export")
+	format := flag.String("format", "json", "json, html or text")
+	out := flag.String("out", "", "output file, - for stdout")
+	flag.Parse()
+
+	if *chatID == 0 || (*token == "" && *apiKey == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", *apiKey)
+	} else {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authentication", *token)
+	}
+
+	err := run(ctx, *addr, *chatID, *format, *out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "export failed:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, addr string, chatID int64, format, out string) (err error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := chatv1.NewChatServiceClient(conn).ExportChat(ctx, &chatv1.ExportChatRequest{
+		ChatId: chatID,
+		Format: format,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The first chunk carries the file name, so the output is opened only once it arrives.
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	name := out
+	if name == "" {
+		name = first.FileName
+	}
+
+	var w io.Writer = os.Stdout
+	if name != "-" {
+		file, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+
+		// A failed export must not leave a truncated file that looks complete.
+		defer func() {
+			closeErr := file.Close()
+			if err == nil {
+				err = closeErr
+			}
+
+			if err != nil {
+				_ = os.Remove(name)
+			}
+		}()
+
+		w = file
+	}
+
+	for resp := first; ; {
+		_, err = w.Write(resp.Data)
+		if err != nil {
+			return err
+		}
+
+		resp, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if name != "-" {
+		fmt.Fprintln(os.Stderr, "exported chat to", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Command import copies a chat history exported from another chat tool into the database.
+//
+//	go run ./cmd/import -format slack -path export.zip -owner admin -map users.csv
+//	go run ./cmd/import -format csv -path history.csv -chat support -owner admin
+//
+// It connects with the same POSTGRES_* variables as the server. Chats are created owned by -owner, foreign
+// users are mapped through -map ("external ID or name,login" lines), optionally to accounts with the same
+// login (-match-logins), and otherwise to new placeholder accounts nobody can sign in to. Re-running the
+// same import skips everything already imported, so an interrupted run can simply be started again.
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/monobearotaku/online-chat-api/internal/config"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	auth_repo "github.com/monobearotaku/online-chat-api/internal/repository/auth"
+	chat_repo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	chatimport_repo "github.com/monobearotaku/online-chat-api/internal/repository/chatimport"
+	chatimport_service "github.com/monobearotaku/online-chat-api/internal/service/chatimport"
+)
+
+func main() {
+	format := flag.String("format", "", "slack or csv")
+	path := flag.String("path", "", "Slack export directory or zip, or CSV file")
+	source := flag.String("source", "", "namespace for external IDs, defaults to the format; use one per workspace")
+	owner := flag.String("owner", "", "login of the account that owns the created chats")
+	mapFile := flag.String("map", "", "CSV file of external user ID or name, login")
+	matchLogins := flag.Bool("match-logins", false, "map foreign users to accounts with the same login")
+	channels := flag.String("channels", "", "comma-separated Slack channels to import, defaults to all")
+	chatName := flag.String("chat", "", "chat name for a CSV import")
+	prefix := flag.String("prefix", "", "prefix for the names of created chats")
+	flag.Parse()
+
+	parsedFormat, err := chatimport.ParseFormat(*format)
+	if err != nil || *path == "" || *owner == "" || (parsedFormat == chatimport.FormatCSV && *chatName == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := chatimport.Options{
+		Source:      *source,
+		Owner:       domain.Login(*owner),
+		MatchLogins: *matchLogins,
+		ChatPrefix:  *prefix,
+	}
+
+	if opts.Source == "" {
+		opts.Source = string(parsedFormat)
+	}
+
+	if *mapFile != "" {
+		opts.Accounts, err = readAccounts(*mapFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "import failed: reading user map:", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	src, closeSource, err := openSource(parsedFormat, *path, *channels, *chatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import failed:", err)
+		os.Exit(1)
+	}
+	defer closeSource()
+
+	db, closeDb := postgres.NewDbConnection(ctx, config.ParseConfig())
+	defer closeDb()
+
+	service := chatimport_service.NewChatImportService(
+		chatimport_repo.NewChatImportRepo(db),
+		chat_repo.NewChatRepo(db),
+		auth_repo.NewAuthRepo(db),
+		db,
+	)
+
+	report, err := service.Import(ctx, src, opts)
+
+	fmt.Fprintf(os.Stderr, "chats: %d (%d created), placeholder users: %d, messages: %d imported, %d already present\n",
+		report.Chats, report.ChatsCreated, report.Placeholders, report.Messages, report.Skipped)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import failed:", err)
+		os.Exit(1)
+	}
+}
+
+func openSource(format chatimport.Format, path, channels, chatName string) (chatimport_service.Source, func(), error) {
+	if format == chatimport.FormatCSV {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+
+		src, err := chatimport_service.NewCSVSource(file, chatName)
+
+		return src, func() {}, err
+	}
+
+	var (
+		fsys      fs.FS
+		closeFunc = func() {}
+	)
+
+	if strings.HasSuffix(path, ".zip") {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fsys = zr
+		closeFunc = func() { zr.Close() }
+	} else {
+		fsys = os.DirFS(path)
+	}
+
+	var names []string
+	if channels != "" {
+		names = strings.Split(channels, ",")
+	}
+
+	src, err := chatimport_service.NewSlackSource(fsys, names)
+	if err != nil {
+		closeFunc()
+		return nil, nil, err
+	}
+
+	return src, closeFunc, nil
+}
+
+func readAccounts(path string) (map[string]domain.Login, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[string]domain.Login, len(records))
+	for _, record := range records {
+		accounts[record[0]] = domain.Login(record[1])
+	}
+
+	return accounts, nil
+}
@@ -19,11 +19,75 @@ type Tracer struct {
 	Url string
 }
 
+type Network struct {
+	TrustedProxies string
+}
+
+type Account struct {
+	DeletedMessagesPolicy string
+}
+
+type Password struct {
+	HashAlgorithm     string
+	BcryptCost        string
+	Argon2Memory      string
+	Argon2Iterations  string
+	Argon2Parallelism string
+}
+
+type Oidc struct {
+	Provider     string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+type Webhooks struct {
+	AllowPrivateNetworks string
+}
+
+type RateLimit struct {
+	UserBurst     string
+	UserPerSecond string
+	ChatBurst     string
+	ChatPerSecond string
+}
+
+type Filter struct {
+	Blocklist       string
+	BlocklistAction string
+	LinkAllow       string
+	LinkDeny        string
+	LinkAction      string
+	MaxLength       string
+	MaxRepeats      string
+}
+
+type Retention struct {
+	KeepDays     string
+	KeepMessages string
+	ArchiveDir   string
+}
+
+type DataExport struct {
+	Dir string
+}
+
 type Config struct {
-	Db      Db
-	Kafka   Kafka
-	Tracer  Tracer
-	AppName string
+	Db         Db
+	Kafka      Kafka
+	Tracer     Tracer
+	Account    Account
+	Network    Network
+	Password   Password
+	Oidc       Oidc
+	Webhooks   Webhooks
+	RateLimit  RateLimit
+	Filter     Filter
+	Retention  Retention
+	DataExport DataExport
+	AppName    string
 }
 
 func ParseConfig() Config {
@@ -42,5 +106,51 @@ func ParseConfig() Config {
 		Tracer: Tracer{
 			Url: os.Getenv("TRACER_URL"),
 		},
+		Account: Account{
+			DeletedMessagesPolicy: os.Getenv("ACCOUNT_DELETED_MESSAGES_POLICY"),
+		},
+		Network: Network{
+			TrustedProxies: os.Getenv("TRUSTED_PROXIES"),
+		},
+		Password: Password{
+			HashAlgorithm:     os.Getenv("PASSWORD_HASH_ALGORITHM"),
+			BcryptCost:        os.Getenv("PASSWORD_BCRYPT_COST"),
+			Argon2Memory:      os.Getenv("PASSWORD_ARGON2_MEMORY"),
+			Argon2Iterations:  os.Getenv("PASSWORD_ARGON2_ITERATIONS"),
+			Argon2Parallelism: os.Getenv("PASSWORD_ARGON2_PARALLELISM"),
+		},
+		Oidc: Oidc{
+			Provider:     os.Getenv("OIDC_PROVIDER"),
+			Issuer:       os.Getenv("OIDC_ISSUER"),
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		},
+		Webhooks: Webhooks{
+			AllowPrivateNetworks: os.Getenv("WEBHOOKS_ALLOW_PRIVATE_NETWORKS"),
+		},
+		RateLimit: RateLimit{
+			UserBurst:     os.Getenv("RATE_LIMIT_USER_BURST"),
+			UserPerSecond: os.Getenv("RATE_LIMIT_USER_PER_SECOND"),
+			ChatBurst:     os.Getenv("RATE_LIMIT_CHAT_BURST"),
+			ChatPerSecond: os.Getenv("RATE_LIMIT_CHAT_PER_SECOND"),
+		},
+		Filter: Filter{
+			Blocklist:       os.Getenv("FILTER_BLOCKLIST"),
+			BlocklistAction: os.Getenv("FILTER_BLOCKLIST_ACTION"),
+			LinkAllow:       os.Getenv("FILTER_LINK_ALLOW"),
+			LinkDeny:        os.Getenv("FILTER_LINK_DENY"),
+			LinkAction:      os.Getenv("FILTER_LINK_ACTION"),
+			MaxLength:       os.Getenv("FILTER_MAX_LENGTH"),
+			MaxRepeats:      os.Getenv("FILTER_MAX_REPEATS"),
+		},
+		Retention: Retention{
+			KeepDays:     os.Getenv("RETENTION_KEEP_DAYS"),
+			KeepMessages: os.Getenv("RETENTION_KEEP_MESSAGES"),
+			ArchiveDir:   os.Getenv("RETENTION_ARCHIVE_DIR"),
+		},
+		DataExport: DataExport{
+			Dir: os.Getenv("DATA_EXPORT_DIR"),
+		},
 	}
 }
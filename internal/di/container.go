@@ -5,25 +5,73 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/realip"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/monobearotaku/online-chat-api/internal/config"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
 	"github.com/monobearotaku/online-chat-api/internal/kafka/producer"
+	"github.com/monobearotaku/online-chat-api/internal/pkg/oidc"
 	auth_v1 "github.com/monobearotaku/online-chat-api/internal/ports/api/auth/v1"
+	bot_v1 "github.com/monobearotaku/online-chat-api/internal/ports/api/bot/v1"
 	chat_v1 "github.com/monobearotaku/online-chat-api/internal/ports/api/chat/v1"
+	"github.com/monobearotaku/online-chat-api/internal/ports/api/interceptors"
+	user_v1 "github.com/monobearotaku/online-chat-api/internal/ports/api/user/v1"
+	webhook_v1 "github.com/monobearotaku/online-chat-api/internal/ports/api/webhook/v1"
+	incoming_http "github.com/monobearotaku/online-chat-api/internal/ports/http/incoming"
+	oidc_http "github.com/monobearotaku/online-chat-api/internal/ports/http/oidc"
 	consumer "github.com/monobearotaku/online-chat-api/internal/ports/kafka/consumers"
+	"github.com/monobearotaku/online-chat-api/internal/ports/workers"
 	"github.com/monobearotaku/online-chat-api/internal/postgres"
 	auth_repo "github.com/monobearotaku/online-chat-api/internal/repository/auth"
+	bot_repo "github.com/monobearotaku/online-chat-api/internal/repository/bot"
 	chat_repo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	command_repo "github.com/monobearotaku/online-chat-api/internal/repository/command"
+	dataexport_repo "github.com/monobearotaku/online-chat-api/internal/repository/dataexport"
+	identity_repo "github.com/monobearotaku/online-chat-api/internal/repository/identity"
+	incoming_repo "github.com/monobearotaku/online-chat-api/internal/repository/incoming"
+	lockout_repo "github.com/monobearotaku/online-chat-api/internal/repository/lockout"
+	moderation_repo "github.com/monobearotaku/online-chat-api/internal/repository/moderation"
+	poll_repo "github.com/monobearotaku/online-chat-api/internal/repository/poll"
+	ratelimit_repo "github.com/monobearotaku/online-chat-api/internal/repository/ratelimit"
+	report_repo "github.com/monobearotaku/online-chat-api/internal/repository/report"
+	schedule_repo "github.com/monobearotaku/online-chat-api/internal/repository/schedule"
+	totp_repo "github.com/monobearotaku/online-chat-api/internal/repository/totp"
+	user_repo "github.com/monobearotaku/online-chat-api/internal/repository/user"
+	webhook_repo "github.com/monobearotaku/online-chat-api/internal/repository/webhook"
 	"github.com/monobearotaku/online-chat-api/internal/service/auth"
+	bot_service "github.com/monobearotaku/online-chat-api/internal/service/bot"
 	"github.com/monobearotaku/online-chat-api/internal/service/chat"
+	dataexport_service "github.com/monobearotaku/online-chat-api/internal/service/dataexport"
+	export_service "github.com/monobearotaku/online-chat-api/internal/service/export"
+	filter_service "github.com/monobearotaku/online-chat-api/internal/service/filter"
+	"github.com/monobearotaku/online-chat-api/internal/service/hasher"
+	incoming_service "github.com/monobearotaku/online-chat-api/internal/service/incoming"
+	lockout_service "github.com/monobearotaku/online-chat-api/internal/service/lockout"
+	ratelimit_service "github.com/monobearotaku/online-chat-api/internal/service/ratelimit"
+	retention_service "github.com/monobearotaku/online-chat-api/internal/service/retention"
 	"github.com/monobearotaku/online-chat-api/internal/service/tokenizer"
+	user_service "github.com/monobearotaku/online-chat-api/internal/service/user"
+	webhook_service "github.com/monobearotaku/online-chat-api/internal/service/webhook"
+	authv1 "github.com/monobearotaku/online-chat-api/proto/auth/v1"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	userv1 "github.com/monobearotaku/online-chat-api/proto/user/v1"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/otel"
@@ -35,13 +83,25 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 )
 
 type DiContainer struct {
-	chatV1Server *chat_v1.ChatV1
-	authV1Server *auth_v1.AuthV1
-
-	kafkaConcumer *consumer.Consumer
+	chatV1Server    *chat_v1.ChatV1
+	authV1Server    *auth_v1.AuthV1
+	userV1Server    *user_v1.UserV1
+	botV1Server     *bot_v1.BotV1
+	webhookV1Server *webhook_v1.WebhookV1
+
+	kafkaConcumer     *consumer.Consumer
+	webhookConsumer   *consumer.WebhookConsumer
+	webhookDispatcher *workers.WebhookDispatcher
+	messageReaper     *workers.MessageReaper
+	pollCloser        *workers.PollCloser
+	scheduledSender   *workers.ScheduledSender
+	retentionEnforcer *workers.RetentionEnforcer
+	dataExporter      *workers.DataExporter
 
 	grpcListener net.Listener
 	httpListener net.Listener
@@ -79,13 +139,123 @@ func NewDiContainer(ctx context.Context) *DiContainer {
 
 	authRepo := auth_repo.NewAuthRepo(db)
 	chatRepo := chat_repo.NewChatRepo(db)
+	userRepo := user_repo.NewUserRepo(db)
+	lockoutRepo := lockout_repo.NewLockoutRepo(db)
+	totpRepo := totp_repo.NewTotpRepo(db)
+	identityRepo := identity_repo.NewIdentityRepo(db)
+	botRepo := bot_repo.NewBotRepo(db)
+	webhookRepo := webhook_repo.NewWebhookRepo(db)
+	incomingRepo := incoming_repo.NewIncomingRepo(db)
+	commandRepo := command_repo.NewCommandRepo(db)
+	moderationRepo := moderation_repo.NewModerationRepo(db)
+	rateLimitRepo := ratelimit_repo.NewRateLimitRepo(db)
+	reportRepo := report_repo.NewReportRepo(db)
+	scheduleRepo := schedule_repo.NewScheduleRepo(db)
+	pollRepo := poll_repo.NewPollRepo(db)
+	dataExportRepo := dataexport_repo.NewDataExportRepo(db)
 
 	tokenizer := tokenizer.NewTokenizer()
 
-	authService := auth.NewAuthService(authRepo, tokenizer, db)
-	chatService := chat.NewChatService(chatRepo, authRepo, tokenizer, kafkaProducer, db)
+	lockoutService := lockout_service.NewLockoutService(lockoutRepo, map[lockout.Scope]lockout.Policy{
+		lockout.ScopeLogin:   lockout.LoginPolicy,
+		lockout.ScopeAddress: lockout.AddressPolicy,
+	})
+
+	userBucket, err := ratelimit.ParseBucket(config.RateLimit.UserBurst, config.RateLimit.UserPerSecond, ratelimit.UserBucket)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse user rate limit, using defaults: %v", err))
+	}
+
+	chatBucket, err := ratelimit.ParseBucket(config.RateLimit.ChatBurst, config.RateLimit.ChatPerSecond, ratelimit.ChatBucket)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse chat rate limit, using defaults: %v", err))
+	}
+
+	rateLimitService := ratelimit_service.NewRateLimitService(rateLimitRepo, map[ratelimit.Scope]ratelimit.Bucket{
+		ratelimit.ScopeUser: userBucket,
+		ratelimit.ScopeChat: chatBucket,
+	})
+
+	hasherParams, err := hasher.ParseParams(
+		config.Password.HashAlgorithm,
+		config.Password.BcryptCost,
+		config.Password.Argon2Memory,
+		config.Password.Argon2Iterations,
+		config.Password.Argon2Parallelism,
+	)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse password hasher params, using defaults: %v", err))
+	}
+
+	passwordHasher := hasher.NewPasswordHasher(hasherParams)
+
+	authService := auth.NewAuthService(authRepo, chatRepo, tokenizer, lockoutService, passwordHasher, totpRepo, identityRepo, db, user.ParseMessagesPolicy(config.Account.DeletedMessagesPolicy), logger)
+	chatService := chat.NewChatService(chatRepo, authRepo, commandRepo, moderationRepo, reportRepo, scheduleRepo, pollRepo, rateLimitService, newFilters(logger, config.Filter), tokenizer, kafkaProducer, db, logger, chat_v1.ToProtoPoll)
+	userService := user_service.NewUserService(userRepo)
+	botService := bot_service.NewBotService(botRepo, authRepo, db)
+
+	allowPrivateNetworks, _ := strconv.ParseBool(config.Webhooks.AllowPrivateNetworks)
+	webhookService := webhook_service.NewWebhookService(webhookRepo, chatRepo, webhook_service.NewHTTPClient(allowPrivateNetworks), webhook.DefaultPolicy, db)
+	exportService := export_service.NewExportService(chatRepo)
+	dataExportService := dataexport_service.NewDataExportService(dataExportRepo, newDataExportDir(config.DataExport))
+	incomingService := incoming_service.NewIncomingService(incomingRepo, botRepo, chatRepo, chatService, db)
 
 	kafkaConcumer := consumer.NewConsumer(config, chatService, logger)
+	webhookConsumer := consumer.NewWebhookConsumer(config, webhookService, logger)
+	webhookDispatcher := workers.NewWebhookDispatcher(webhookService, logger)
+	messageReaper := workers.NewMessageReaper(chatService, logger)
+	pollCloser := workers.NewPollCloser(chatService, logger)
+	scheduledSender := workers.NewScheduledSender(chatService, logger)
+	retentionEnforcer := workers.NewRetentionEnforcer(newRetention(logger, config.Retention, chatRepo, db), logger)
+	dataExporter := workers.NewDataExporter(dataExportService, logger)
+
+	errorTranslator := interceptors.NewErrorTranslator(logger)
+
+	trustedProxies := parsePrefixes(logger, config.Network.TrustedProxies)
+	realipHeaders := []string{realip.XRealIp}
+
+	authenticator := interceptors.NewAuthenticator(tokenizer, authService, botService, map[string]interceptors.Access{
+		authv1.AuthService_SignIn_FullMethodName:                                     interceptors.Public,
+		authv1.AuthService_SignUp_FullMethodName:                                     interceptors.Public,
+		authv1.AuthService_VerifyTotp_FullMethodName:                                 interceptors.Public,
+		chatv1.ChatService_ConnectToChat_FullMethodName:                              interceptors.Session,
+		grpc_reflection_v1.ServerReflection_ServerReflectionInfo_FullMethodName:      interceptors.Public,
+		grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: interceptors.Public,
+	}, map[string]apikey.Scope{
+		chatv1.ChatService_JoinChat_FullMethodName:               apikey.ScopeMessagesWrite,
+		chatv1.ChatService_PostMessage_FullMethodName:            apikey.ScopeMessagesWrite,
+		chatv1.ChatService_RegisterCommand_FullMethodName:        apikey.ScopeMessagesWrite,
+		chatv1.ChatService_UnregisterCommand_FullMethodName:      apikey.ScopeMessagesWrite,
+		chatv1.ChatService_GetCommands_FullMethodName:            apikey.ScopeMessagesWrite,
+		chatv1.ChatService_CreateChat_FullMethodName:             apikey.ScopeChatsManage,
+		chatv1.ChatService_AddUserToChat_FullMethodName:          apikey.ScopeChatsManage,
+		chatv1.ChatService_KickMember_FullMethodName:             apikey.ScopeChatsManage,
+		chatv1.ChatService_MuteMember_FullMethodName:             apikey.ScopeChatsManage,
+		chatv1.ChatService_UnmuteMember_FullMethodName:           apikey.ScopeChatsManage,
+		chatv1.ChatService_BanUser_FullMethodName:                apikey.ScopeChatsManage,
+		chatv1.ChatService_UnbanUser_FullMethodName:              apikey.ScopeChatsManage,
+		chatv1.ChatService_GetBans_FullMethodName:                apikey.ScopeChatsManage,
+		chatv1.ChatService_GetModerationLog_FullMethodName:       apikey.ScopeChatsManage,
+		chatv1.ChatService_SetSlowMode_FullMethodName:            apikey.ScopeChatsManage,
+		chatv1.ChatService_SetMessageTTL_FullMethodName:          apikey.ScopeChatsManage,
+		chatv1.ChatService_ExportChat_FullMethodName:             apikey.ScopeChatsManage,
+		chatv1.ChatService_SetExportPermission_FullMethodName:    apikey.ScopeChatsManage,
+		chatv1.ChatService_SetRetentionPolicy_FullMethodName:     apikey.ScopeChatsManage,
+		chatv1.ChatService_ReportMessage_FullMethodName:          apikey.ScopeMessagesWrite,
+		chatv1.ChatService_ScheduleMessage_FullMethodName:        apikey.ScopeMessagesWrite,
+		chatv1.ChatService_GetScheduledMessages_FullMethodName:   apikey.ScopeMessagesWrite,
+		chatv1.ChatService_CancelScheduledMessage_FullMethodName: apikey.ScopeMessagesWrite,
+		chatv1.ChatService_CreatePoll_FullMethodName:             apikey.ScopeMessagesWrite,
+		chatv1.ChatService_GetPoll_FullMethodName:                apikey.ScopeMessagesWrite,
+		chatv1.ChatService_VotePoll_FullMethodName:               apikey.ScopeMessagesWrite,
+		chatv1.ChatService_ClosePoll_FullMethodName:              apikey.ScopeMessagesWrite,
+		chatv1.ChatService_GetReports_FullMethodName:             apikey.ScopeChatsManage,
+		chatv1.ChatService_GetReportContext_FullMethodName:       apikey.ScopeChatsManage,
+		chatv1.ChatService_ResolveReport_FullMethodName:          apikey.ScopeChatsManage,
+		userv1.UserService_GetUser_FullMethodName:                apikey.ScopeUsersRead,
+		userv1.UserService_GetUsers_FullMethodName:               apikey.ScopeUsersRead,
+		userv1.UserService_SearchUsers_FullMethodName:            apikey.ScopeUsersRead,
+	})
 
 	kaep := keepalive.EnforcementPolicy{
 		MinTime:             5 * time.Minute,
@@ -110,6 +280,9 @@ func NewDiContainer(ctx context.Context) *DiContainer {
 					logging.FinishCall,
 				),
 			),
+			errorTranslator.UnaryServerInterceptor(),
+			realip.UnaryServerInterceptor(trustedProxies, realipHeaders),
+			authenticator.UnaryServerInterceptor(),
 		),
 		grpc.ChainStreamInterceptor(
 			grpc_prometheus.StreamServerInterceptor,
@@ -122,6 +295,9 @@ func NewDiContainer(ctx context.Context) *DiContainer {
 					logging.FinishCall,
 				),
 			),
+			errorTranslator.StreamServerInterceptor(),
+			realip.StreamServerInterceptor(trustedProxies, realipHeaders),
+			authenticator.StreamServerInterceptor(),
 		),
 
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
@@ -154,26 +330,45 @@ func NewDiContainer(ctx context.Context) *DiContainer {
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
-	mux.HandleFunc("/health", func (w http.ResponseWriter, r *http.Request)  {
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("ok"))
 	})
 
+	incoming_http.NewHandler(mux, incomingService, logger)
+
+	if config.Oidc.Issuer != "" {
+		registerOidc(ctx, mux, config.Oidc, authService, logger)
+	}
+
 	authV1 := auth_v1.NewAuthV1(dialer, authService)
-	chatV1 := chat_v1.NewChatV1(dialer, chatService, tokenizer)
+	chatV1 := chat_v1.NewChatV1(dialer, chatService, exportService)
+	userV1 := user_v1.NewUserV1(dialer, userService, dataExportService)
+	botV1 := bot_v1.NewBotV1(dialer, botService)
+	webhookV1 := webhook_v1.NewWebhookV1(dialer, webhookService, incomingService)
 
 	return &DiContainer{
-		chatV1Server: chatV1,
-		authV1Server: authV1,
-		grpcListener: grpcListener,
-		httpListener: httpListener,
-		server:       dialer,
+		chatV1Server:    chatV1,
+		authV1Server:    authV1,
+		userV1Server:    userV1,
+		botV1Server:     botV1,
+		webhookV1Server: webhookV1,
+		grpcListener:    grpcListener,
+		httpListener:    httpListener,
+		server:          dialer,
 		mux: &http.Server{
 			Handler: mux,
 		},
-		logger:         logger,
-		closeFunctions: closeFunctions,
-		kafkaConcumer:  kafkaConcumer,
+		logger:            logger,
+		closeFunctions:    closeFunctions,
+		kafkaConcumer:     kafkaConcumer,
+		webhookConsumer:   webhookConsumer,
+		webhookDispatcher: webhookDispatcher,
+		messageReaper:     messageReaper,
+		pollCloser:        pollCloser,
+		scheduledSender:   scheduledSender,
+		retentionEnforcer: retentionEnforcer,
+		dataExporter:      dataExporter,
 	}
 }
 
@@ -188,6 +383,41 @@ func (di *DiContainer) Run(ctx context.Context) {
 		di.kafkaConcumer.Consume(ctx)
 	}()
 
+	go func() {
+		level.Info(di.logger).Log("message", "webhook consumer started")
+		di.webhookConsumer.Consume(ctx)
+	}()
+
+	go func() {
+		level.Info(di.logger).Log("message", "webhook dispatcher started")
+		di.webhookDispatcher.Run(ctx)
+	}()
+
+	go func() {
+		level.Info(di.logger).Log("message", "message reaper started")
+		di.messageReaper.Run(ctx)
+	}()
+
+	go func() {
+		level.Info(di.logger).Log("message", "poll closer started")
+		di.pollCloser.Run(ctx)
+	}()
+
+	go func() {
+		level.Info(di.logger).Log("message", "scheduled message sender started")
+		di.scheduledSender.Run(ctx)
+	}()
+
+	go func() {
+		level.Info(di.logger).Log("message", "retention enforcer started")
+		di.retentionEnforcer.Run(ctx)
+	}()
+
+	go func() {
+		level.Info(di.logger).Log("message", "data exporter started")
+		di.dataExporter.Run(ctx)
+	}()
+
 	go func() {
 		level.Info(di.logger).Log("message", fmt.Sprintf("metrics started on port: %s", di.grpcListener.Addr().String()))
 		di.mux.Serve(di.httpListener)
@@ -203,6 +433,109 @@ func (di *DiContainer) Stop() {
 	_ = di.httpListener.Close()
 }
 
+func registerOidc(ctx context.Context, mux *http.ServeMux, cfg config.Oidc, authService auth.Service, logger log.Logger) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	provider, err := oidc.Discover(ctx, client, oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  cfg.RedirectURL,
+	})
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to discover oidc provider %s: %v", cfg.Issuer, err))
+		return
+	}
+
+	name := cfg.Provider
+	if name == "" {
+		name = cfg.Issuer
+	}
+
+	oidc_http.NewHandler(mux, name, provider, authService, strings.HasPrefix(cfg.RedirectURL, "https://"), logger)
+}
+
+// newFilters builds the moderation chain from configuration; a bad entry is logged and its filter falls back to defaults.
+func newFilters(logger log.Logger, cfg config.Filter) filter_service.Service {
+	blocklistAction, err := filter.ParseAction(cfg.BlocklistAction, filter.ActionMask)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse blocklist action: %v", err))
+	}
+
+	linkAction, err := filter.ParseAction(cfg.LinkAction, filter.ActionMask)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse link action: %v", err))
+	}
+
+	var maxLength int
+	if cfg.MaxLength != "" {
+		maxLength, err = strconv.Atoi(cfg.MaxLength)
+		if err != nil {
+			level.Error(logger).Log("error", fmt.Errorf("failed to parse max length %q, the filter is disabled: %v", cfg.MaxLength, err))
+			maxLength = 0
+		}
+	}
+
+	maxRepeats := filter.DefaultMaxRepeats
+	if cfg.MaxRepeats != "" {
+		maxRepeats, err = strconv.Atoi(cfg.MaxRepeats)
+		if err != nil {
+			level.Error(logger).Log("error", fmt.Errorf("failed to parse max repeats %q: %v", cfg.MaxRepeats, err))
+			maxRepeats = filter.DefaultMaxRepeats
+		}
+	}
+
+	blocklist, err := filter_service.NewBlocklistFilter(filter.ParseList(cfg.Blocklist), blocklistAction)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse blocklist, it is disabled: %v", err))
+		blocklist, _ = filter_service.NewBlocklistFilter(nil, blocklistAction)
+	}
+
+	return filter_service.NewFilterService(
+		logger,
+		filter_service.NewLengthFilter(maxLength),
+		filter_service.NewRepetitionFilter(maxRepeats),
+		blocklist,
+		filter_service.NewLinkFilter(filter.ParseList(cfg.LinkAllow), filter.ParseList(cfg.LinkDeny), linkAction),
+	)
+}
+
+// newRetention disables the global policy when it cannot be parsed rather than deleting more than intended.
+func newRetention(logger log.Logger, cfg config.Retention, chatRepo chat_repo.Repo, db postgres.TxBeginner) retention_service.Service {
+	global, err := retention.ParsePolicy(cfg.KeepDays, cfg.KeepMessages)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("failed to parse retention policy, only chat policies apply: %v", err))
+	}
+
+	var archiver retention_service.Archiver
+	if cfg.ArchiveDir != "" {
+		archiver = retention_service.NewFileArchiver(cfg.ArchiveDir)
+	}
+
+	return retention_service.NewRetentionService(chatRepo, archiver, global, db)
+}
+
+func parsePrefixes(logger log.Logger, value string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			level.Error(logger).Log("error", fmt.Errorf("failed to parse trusted proxy %q: %v", item, err))
+			continue
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes
+}
+
 func generateLogFields(ctx context.Context) logging.Fields {
 	if span := trace.SpanContextFromContext(ctx); span.IsSampled() {
 		return logging.Fields{"traceID", span.TraceID().String()}
@@ -226,3 +559,11 @@ func interceptorLogger(l log.Logger) logging.Logger {
 		}
 	})
 }
+
+func newDataExportDir(cfg config.DataExport) string {
+	if cfg.Dir != "" {
+		return cfg.Dir
+	}
+
+	return filepath.Join(os.TempDir(), "data-exports")
+}
@@ -0,0 +1,117 @@
+package apikey
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	keyPrefix     = "ocb"
+	maxNameLength = 64
+	MaxActiveKeys = 20
+)
+
+var (
+	ErrNotFound     = domain.NewError(domain.KindNotFound, "API_KEY_NOT_FOUND", "API key not found")
+	ErrInvalidKey   = domain.NewError(domain.KindUnauthenticated, "INVALID_API_KEY", "API key is invalid or revoked")
+	ErrScopeDenied  = domain.NewError(domain.KindPermissionDenied, "API_KEY_SCOPE_DENIED", "API key does not grant access to this method")
+	ErrInvalidScope = domain.NewError(domain.KindInvalidArgument, "INVALID_API_KEY_SCOPE", "Unknown API key scope").ForField("scopes")
+	ErrNoScopes     = domain.NewError(domain.KindInvalidArgument, "API_KEY_SCOPES_REQUIRED", "API key needs at least one scope").ForField("scopes")
+	ErrTooManyKeys  = domain.NewError(domain.KindResourceExhausted, "TOO_MANY_API_KEYS", "Bot has too many active API keys")
+	ErrNameTooLong  = domain.NewError(domain.KindInvalidArgument, "API_KEY_NAME_TOO_LONG", "API key name must be at most 64 characters").ForField("name")
+)
+
+type Scope string
+
+const (
+	// ScopeMessagesWrite lets a bot open chat sessions and post messages.
+	ScopeMessagesWrite Scope = "messages:write"
+	// ScopeChatsManage lets a bot create chats and add users to chats it owns.
+	ScopeChatsManage Scope = "chats:manage"
+	// ScopeUsersRead lets a bot look up user profiles.
+	ScopeUsersRead Scope = "users:read"
+)
+
+func ParseScopes(values []string) ([]Scope, error) {
+	if len(values) == 0 {
+		return nil, ErrNoScopes
+	}
+
+	scopes := make([]Scope, 0, len(values))
+	seen := make(map[Scope]struct{}, len(values))
+
+	for _, value := range values {
+		scope := Scope(value)
+
+		switch scope {
+		case ScopeMessagesWrite, ScopeChatsManage, ScopeUsersRead:
+		default:
+			return nil, ErrInvalidScope
+		}
+
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+type APIKey struct {
+	ID         int64
+	BotID      int64
+	Prefix     string
+	Hash       string
+	Name       string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	RevokedAt  time.Time
+}
+
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, item := range k.Scopes {
+		if item == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ValidateName(name string) error {
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return ErrNameTooLong
+	}
+
+	return nil
+}
+
+// Format renders the key handed to the client as ocb_<prefix>_<secret>; only the prefix is stored in clear.
+func Format(prefix, secret string) string {
+	return keyPrefix + "_" + prefix + "_" + secret
+}
+
+func Parse(key string) (prefix string, secret string, ok bool) {
+	rest, found := strings.CutPrefix(key, keyPrefix+"_")
+	if !found {
+		return "", "", false
+	}
+
+	prefix, secret, found = strings.Cut(rest, "_")
+	if !found || prefix == "" || secret == "" {
+		return "", "", false
+	}
+
+	return prefix, secret, true
+}
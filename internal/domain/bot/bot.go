@@ -0,0 +1,16 @@
+package bot
+
+import (
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+var (
+	ErrNotFound = domain.NewError(domain.KindNotFound, "BOT_NOT_FOUND", "Bot not found")
+	ErrNotOwner = domain.NewError(domain.KindPermissionDenied, "BOT_NOT_OWNER", "Only the bot owner can manage it")
+)
+
+type Bot struct {
+	ID      int64
+	Login   domain.Login
+	OwnerID int64
+}
@@ -1,25 +1,90 @@
 package chat
 
-import "errors"
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+)
+
+const (
+	MaxTopicLength = 250
+	MaxSlowMode    = 6 * time.Hour
+)
 
 var (
-	ErrChatNotFound      = errors.New("Chat not found")
-	ErrChatAlreadyExists = errors.New("Chat already exists")
-	ErrChatHaveNoUser    = errors.New("User not in chat")
-	ErrChatInvalidName   = errors.New("Invalid chat name")
-	ErrUserNotOwner      = errors.New("User is not an owner")
+	ErrChatNotFound      = domain.NewError(domain.KindNotFound, "CHAT_NOT_FOUND", "Chat not found")
+	ErrChatAlreadyExists = domain.NewError(domain.KindAlreadyExists, "CHAT_ALREADY_EXISTS", "Chat already exists")
+	ErrChatHaveNoUser    = domain.NewError(domain.KindPermissionDenied, "USER_NOT_IN_CHAT", "User not in chat")
+	ErrChatInvalidName   = domain.NewError(domain.KindInvalidArgument, "INVALID_CHAT_NAME", "Invalid chat name").ForField("chatName")
+	ErrUserNotOwner      = domain.NewError(domain.KindPermissionDenied, "USER_NOT_OWNER", "User is not an owner")
+	ErrTopicTooLong      = domain.NewError(domain.KindInvalidArgument, "TOPIC_TOO_LONG", "Topic must be at most 250 characters").ForField("topic")
+	ErrCannotTargetOwner = domain.NewError(domain.KindFailedPrecondition, "CANNOT_TARGET_OWNER", "Chat owners cannot be kicked or muted")
+	ErrUserMuted         = domain.NewError(domain.KindPermissionDenied, "USER_MUTED", "You are muted in this chat")
+	ErrSlowMode          = domain.NewError(domain.KindResourceExhausted, "SLOW_MODE", "Slow mode is on, wait before sending again")
+	ErrExportDenied      = domain.NewError(domain.KindPermissionDenied, "EXPORT_NOT_ALLOWED", "Only owners and members with export permission can export the chat")
+	ErrInvalidSlowMode   = domain.NewError(domain.KindInvalidArgument, "INVALID_SLOW_MODE", "Slow mode must be between 1s and 6h, or 0 to turn it off").ForField("interval")
 )
 
 type Chat struct {
-	ID   int64
-	Name string
+	ID       int64
+	Name     string
+	Topic    string
+	SlowMode time.Duration
+	// MessageTTL makes every message disappear after it; zero keeps messages.
+	MessageTTL time.Duration
+	// Retention overrides the global retention policy where its fields are non-zero.
+	Retention retention.Policy
+}
+
+func ValidateTopic(topic string) error {
+	if utf8.RuneCountInString(topic) > MaxTopicLength {
+		return ErrTopicTooLong
+	}
+
+	return nil
+}
+
+// ValidateSlowMode accepts whole seconds up to MaxSlowMode; zero turns slow mode off.
+func ValidateSlowMode(interval time.Duration) error {
+	if interval < 0 || interval > MaxSlowMode || interval%time.Second != 0 {
+		return ErrInvalidSlowMode
+	}
+
+	return nil
 }
 
 type ChatUser struct {
-	UserID int64
+	UserID     int64
+	Role       Role
+	MutedUntil time.Time
+	// CanExport is granted by an owner; owners can always export.
+	CanExport bool
+}
+
+func (cu ChatUser) CanExportChat() bool {
+	return cu.Role == Owner || cu.CanExport
+}
+
+func (cu ChatUser) Muted(now time.Time) bool {
+	return cu.MutedUntil.After(now)
+}
+
+type Membership struct {
+	ChatID int64
 	Role   Role
 }
 
+// MemberInfo is a chat member with the profile fields shown in exports.
+type MemberInfo struct {
+	UserID   int64
+	Login    string
+	Bot      bool
+	Role     Role
+	JoinedAt time.Time
+}
+
 type ChatUsers struct {
 	ID    int64
 	Users []ChatUser
@@ -35,6 +100,16 @@ func (cu ChatUsers) Contains(userID int64) bool {
 	return false
 }
 
+func (cu ChatUsers) Get(userID int64) (ChatUser, bool) {
+	for _, item := range cu.Users {
+		if item.UserID == userID {
+			return item, true
+		}
+	}
+
+	return ChatUser{}, false
+}
+
 func (cu ChatUsers) IsOwner(userID int64) bool {
 	for _, item := range cu.Users {
 		if item.UserID == userID && item.Role == Owner {
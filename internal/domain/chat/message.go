@@ -1,8 +1,64 @@
 package chat
 
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	MaxMessageLength = 4000
+	MaxMessageTTL    = 30 * 24 * time.Hour
+)
+
+var (
+	ErrEmptyMessage    = domain.NewError(domain.KindInvalidArgument, "EMPTY_MESSAGE", "Message is empty").ForField("text")
+	ErrMessageTooLong  = domain.NewError(domain.KindInvalidArgument, "MESSAGE_TOO_LONG", "Message must be at most 4000 characters").ForField("text")
+	ErrMessageNotFound = domain.NewError(domain.KindNotFound, "MESSAGE_NOT_FOUND", "Message not found")
+	ErrInvalidTTL      = domain.NewError(domain.KindInvalidArgument, "INVALID_MESSAGE_TTL", "Time to live must be whole seconds up to 30 days, or 0 to keep messages").ForField("ttl")
+)
+
+type MessageKind string
+
+const (
+	KindText MessageKind = "text"
+	// KindAction is a "/me" message, rendered as the sender performing the text.
+	KindAction MessageKind = "action"
+	// KindPoll carries a poll's question; the poll itself is stored under the message ID.
+	KindPoll MessageKind = "poll"
+)
+
 type Message struct {
-	UserID int64
-	ChatID int64
-	Msg    string
-	Login  string
+	ID        int64
+	UserID    int64
+	ChatID    int64
+	Msg       string
+	Login     string
+	Bot       bool
+	Kind      MessageKind
+	CreatedAt time.Time
+	// ExpiresAt is when the reaper deletes the message; zero keeps it.
+	ExpiresAt time.Time
+}
+
+func (m Message) Validate() error {
+	if m.Msg == "" {
+		return ErrEmptyMessage
+	}
+
+	if utf8.RuneCountInString(m.Msg) > MaxMessageLength {
+		return ErrMessageTooLong
+	}
+
+	return nil
+}
+
+// ValidateTTL accepts zero, meaning no expiry, or whole seconds up to MaxMessageTTL.
+func ValidateTTL(ttl time.Duration) error {
+	if ttl < 0 || ttl > MaxMessageTTL || ttl%time.Second != 0 {
+		return ErrInvalidTTL
+	}
+
+	return nil
 }
@@ -0,0 +1,119 @@
+package chatimport
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+)
+
+const (
+	minLoginLength = 5
+	maxLoginLength = 20
+	// placeholderSuffix pads foreign names too short to be a login.
+	placeholderSuffix = "-imported"
+)
+
+var (
+	ErrInvalidFormat  = domain.NewError(domain.KindInvalidArgument, "INVALID_IMPORT_FORMAT", "Import format must be slack or csv").ForField("format")
+	ErrInvalidRecord  = domain.NewError(domain.KindInvalidArgument, "INVALID_IMPORT_RECORD", "Import file contains an invalid record")
+	ErrOwnerNotFound  = domain.NewError(domain.KindNotFound, "IMPORT_OWNER_NOT_FOUND", "Import owner account not found").ForField("owner")
+	ErrAccountMissing = domain.NewError(domain.KindNotFound, "IMPORT_ACCOUNT_NOT_FOUND", "User mapping names an account that does not exist")
+)
+
+type Format string
+
+const (
+	FormatSlack Format = "slack"
+	FormatCSV   Format = "csv"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(s)); format {
+	case FormatSlack, FormatCSV:
+		return format, nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// User is an account in the foreign tool, identified by its ExternalID within one source.
+type User struct {
+	ExternalID string
+	Name       string
+	Bot        bool
+}
+
+type Chat struct {
+	ExternalID string
+	Name       string
+	Topic      string
+	// Members are external user IDs; authors of imported messages join as well.
+	Members []string
+}
+
+type Message struct {
+	// ExternalID is unique within its chat and makes re-runs skip the message.
+	ExternalID string
+	UserID     string
+	Text       string
+	Kind       chat.MessageKind
+	CreatedAt  time.Time
+}
+
+type Options struct {
+	// Source namespaces external IDs, so two workspaces can be imported side by side.
+	Source string
+	Owner  domain.Login
+	// Accounts maps an external user ID or name to an existing login; it wins over earlier runs.
+	Accounts map[string]domain.Login
+	// MatchLogins maps a foreign user to the account whose login equals its name.
+	MatchLogins bool
+	// ChatPrefix is prepended to the names of created chats.
+	ChatPrefix string
+}
+
+type Report struct {
+	Chats        int
+	ChatsCreated int
+	Placeholders int
+	Messages     int
+	// Skipped counts messages imported by an earlier run.
+	Skipped int
+}
+
+// PlaceholderLogin derives a valid login from a foreign name; attempt picks another candidate after a collision.
+func PlaceholderLogin(name string, attempt int) domain.Login {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('.')
+		}
+	}
+
+	base := strings.Trim(b.String(), ".-_")
+	if base == "" {
+		base = "user"
+	}
+
+	if len(base) < minLoginLength {
+		base += placeholderSuffix
+	}
+
+	suffix := ""
+	if attempt > 0 {
+		suffix = "-" + strconv.Itoa(attempt+1)
+	}
+
+	if len(base)+len(suffix) > maxLoginLength {
+		base = base[:maxLoginLength-len(suffix)]
+	}
+
+	return domain.Login(base + suffix)
+}
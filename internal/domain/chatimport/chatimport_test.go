@@ -0,0 +1,40 @@
+package chatimport
+
+import (
+	"testing"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PlaceholderLogin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		foreign string
+		attempt int
+		want    domain.Login
+	}{
+		{name: "valid name kept", foreign: "alice.smith", want: "alice.smith"},
+		{name: "lowercased and spaces dotted", foreign: "Alice Smith", want: "alice.smith"},
+		{name: "unsupported runes dropped", foreign: "zoë@work!", want: "zowork"},
+		{name: "short name padded", foreign: "bob", want: "bob-imported"},
+		{name: "empty name replaced", foreign: "ü", want: "user-imported"},
+		{name: "long name truncated", foreign: "averyveryverylongslackname", want: "averyveryverylongsla"},
+		{name: "collision suffixed", foreign: "alice", attempt: 1, want: "alice-2"},
+		{name: "collision suffix fits", foreign: "averyveryverylongslackname", attempt: 9, want: "averyveryverylong-10"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := PlaceholderLogin(tt.foreign, tt.attempt)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, got.Validate())
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package command
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	Prefix = "/"
+
+	maxDescriptionLength = 200
+)
+
+var (
+	ErrUnknownCommand      = domain.NewError(domain.KindNotFound, "UNKNOWN_COMMAND", "Unknown command, try /help")
+	ErrInvalidName         = domain.NewError(domain.KindInvalidArgument, "INVALID_COMMAND_NAME", "Command name must be 1-32 lowercase letters, digits, '-' or '_'").ForField("name")
+	ErrDescriptionTooLong  = domain.NewError(domain.KindInvalidArgument, "COMMAND_DESCRIPTION_TOO_LONG", "Command description must be at most 200 characters").ForField("description")
+	ErrAlreadyRegistered   = domain.NewError(domain.KindAlreadyExists, "COMMAND_ALREADY_REGISTERED", "Command is already registered in this chat")
+	ErrNotFound            = domain.NewError(domain.KindNotFound, "COMMAND_NOT_FOUND", "Command is not registered in this chat")
+	ErrOnlyBotsCanRegister = domain.NewError(domain.KindPermissionDenied, "ONLY_BOTS_REGISTER_COMMANDS", "Only bots can register chat commands")
+)
+
+var (
+	namePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+	// durationLike matches a number followed by a unit, so "5x" is a mistyped duration rather than a reason.
+	durationLike = regexp.MustCompile(`^[-+]?[0-9.]+[a-zµμ][0-9a-zµμ.]*$`)
+)
+
+// Invocation is a parsed "/name args" message sent by Caller in a chat.
+type Invocation struct {
+	ChatID      int64
+	CallerID    int64
+	CallerLogin string
+	Name        string
+	Args        string
+}
+
+// Fields splits the arguments on whitespace.
+func (i Invocation) Fields() []string {
+	return strings.Fields(i.Args)
+}
+
+// Reply is what the caller sees; ephemeral replies are sent only to the calling connection.
+type Reply struct {
+	Text      string
+	Ephemeral bool
+}
+
+func Ephemeral(text string) Reply {
+	return Reply{
+		Text:      text,
+		Ephemeral: true,
+	}
+}
+
+// Command is implemented by built-in commands and in-process plug-ins.
+type Command interface {
+	Name() string
+	Description() string
+	Execute(ctx context.Context, inv Invocation) (Reply, error)
+}
+
+// Registration is a command a bot registered in a chat; invocations are forwarded to the bot.
+type Registration struct {
+	ChatID      int64
+	Name        string
+	Description string
+	BotID       int64
+	BotLogin    string
+	CreatedAt   time.Time
+}
+
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return ErrInvalidName
+	}
+
+	return nil
+}
+
+func ValidateDescription(description string) error {
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return ErrDescriptionTooLong
+	}
+
+	return nil
+}
+
+// Parse recognises "/name args"; "//text" escapes a message that should start with a slash.
+func Parse(text string) (name, args string, ok bool) {
+	rest, found := strings.CutPrefix(text, Prefix)
+	if !found || strings.HasPrefix(rest, Prefix) {
+		return "", "", false
+	}
+
+	name, args, _ = strings.Cut(rest, " ")
+
+	return strings.ToLower(name), strings.TrimSpace(args), true
+}
+
+// SplitDuration reads an optional leading duration such as 30m or 2h; the remaining words are the reason.
+// A leading word that looks like a duration but is not a valid positive one reports false.
+func SplitDuration(fields []string) (time.Duration, string, bool) {
+	if len(fields) == 0 {
+		return 0, "", true
+	}
+
+	if !durationLike.MatchString(fields[0]) {
+		return 0, strings.Join(fields, " "), true
+	}
+
+	duration, err := time.ParseDuration(fields[0])
+	if err != nil || duration <= 0 {
+		return 0, "", false
+	}
+
+	return duration, strings.Join(fields[1:], " "), true
+}
+
+// Unescape turns "//text" into "/text".
+func Unescape(text string) string {
+	if strings.HasPrefix(text, Prefix+Prefix) {
+		return text[len(Prefix):]
+	}
+
+	return text
+}
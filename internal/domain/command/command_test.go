@@ -0,0 +1,84 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		name string
+		args string
+		ok   bool
+	}{
+		{text: "/me waves", name: "me", args: "waves", ok: true},
+		{text: "/topic   release  friday ", name: "topic", args: "release  friday", ok: true},
+		{text: "/KICK bob", name: "kick", args: "bob", ok: true},
+		{text: "/help", name: "help", ok: true},
+		{text: "//shrug", ok: false},
+		{text: "hello /me", ok: false},
+		{text: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+
+			name, args, ok := Parse(tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func Test_ValidateName(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateName("deploy"))
+	assert.NoError(t, ValidateName("run_tests-2"))
+	assert.ErrorIs(t, ValidateName(""), ErrInvalidName)
+	assert.ErrorIs(t, ValidateName("Deploy"), ErrInvalidName)
+	assert.ErrorIs(t, ValidateName("a b"), ErrInvalidName)
+}
+
+func Test_SplitDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fields   []string
+		duration time.Duration
+		reason   string
+		ok       bool
+	}{
+		{name: "nothing", ok: true},
+		{name: "duration and reason", fields: []string{"30m", "spam", "links"}, duration: 30 * time.Minute, reason: "spam links", ok: true},
+		{name: "compound duration", fields: []string{"1h30m"}, duration: 90 * time.Minute, ok: true},
+		{name: "reason only", fields: []string{"spam", "links"}, reason: "spam links", ok: true},
+		{name: "reason starting with a number", fields: []string{"2", "warnings"}, reason: "2 warnings", ok: true},
+		{name: "unknown unit", fields: []string{"5x", "spam"}, ok: false},
+		{name: "zero", fields: []string{"0s", "spam"}, ok: false},
+		{name: "negative", fields: []string{"-5m"}, ok: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			duration, reason, ok := SplitDuration(tt.fields)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.duration, duration)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
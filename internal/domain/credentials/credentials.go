@@ -7,7 +7,7 @@ import (
 )
 
 var (
-	ErrWrongCreds = errors.New("Login or Password are incorrect")
+	ErrWrongCreds = domain.NewError(domain.KindUnauthenticated, "WRONG_CREDENTIALS", "Login or Password are incorrect")
 )
 
 type Credentials struct {
@@ -0,0 +1,132 @@
+package dataexport
+
+import (
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	// ArchiveTTL is how long a finished archive can be downloaded before it is deleted.
+	ArchiveTTL = 7 * 24 * time.Hour
+	// StaleAfter lets another worker retry a job whose worker died mid-run.
+	StaleAfter = time.Hour
+)
+
+var (
+	ErrNotFound   = domain.NewError(domain.KindNotFound, "DATA_EXPORT_NOT_FOUND", "Data export not found")
+	ErrInProgress = domain.NewError(domain.KindAlreadyExists, "DATA_EXPORT_IN_PROGRESS", "A data export is already being prepared")
+	ErrNotReady   = domain.NewError(domain.KindFailedPrecondition, "DATA_EXPORT_NOT_READY", "Data export is not ready for download")
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusReady   Status = "ready"
+	StatusFailed  Status = "failed"
+	StatusExpired Status = "expired"
+)
+
+type Job struct {
+	ID     int64
+	UserID int64
+	Status Status
+	// Error is the failure reason of a failed job.
+	Error string
+	// Path is where the worker stored the archive; it is never shown to users.
+	Path      string
+	Size      int64
+	CreatedAt time.Time
+	// StartedAt identifies the worker's claim on a running job; a reclaimed job gets a new one.
+	StartedAt   time.Time
+	CompletedAt time.Time
+	ExpiresAt   time.Time
+}
+
+func (j Job) Downloadable(now time.Time) bool {
+	return j.Status == StatusReady && now.Before(j.ExpiresAt)
+}
+
+// The records below are the archive's public JSON format.
+
+type Profile struct {
+	UserID       int64     `json:"userId"`
+	Login        string    `json:"login"`
+	DisplayName  string    `json:"displayName"`
+	AvatarRef    string    `json:"avatarRef"`
+	Bio          string    `json:"bio"`
+	Bot          bool      `json:"bot"`
+	CreatedAt    time.Time `json:"createdAt"`
+	TokenVersion int64     `json:"tokenVersion"`
+}
+
+type Membership struct {
+	ChatID     int64      `json:"chatId"`
+	ChatName   string     `json:"chatName"`
+	Role       string     `json:"role"`
+	CanExport  bool       `json:"canExport"`
+	MutedUntil *time.Time `json:"mutedUntil,omitempty"`
+	JoinedAt   *time.Time `json:"joinedAt,omitempty"`
+}
+
+type Message struct {
+	ID        int64      `json:"id"`
+	ChatID    int64      `json:"chatId"`
+	Text      string     `json:"text"`
+	Kind      string     `json:"kind"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// PollVote is the closest thing to a reaction the system stores.
+type PollVote struct {
+	PollMessageID int64     `json:"pollMessageId"`
+	ChatID        int64     `json:"chatId"`
+	Question      string    `json:"question"`
+	Option        string    `json:"option"`
+	VotedAt       time.Time `json:"votedAt"`
+}
+
+type Report struct {
+	ID         int64      `json:"id"`
+	ChatID     int64      `json:"chatId"`
+	MessageID  int64      `json:"messageId"`
+	Reason     string     `json:"reason"`
+	Resolution string     `json:"resolution,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type ScheduledMessage struct {
+	ID        int64     `json:"id"`
+	ChatID    int64     `json:"chatId"`
+	Text      string    `json:"text"`
+	SendAt    time.Time `json:"sendAt"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Ban struct {
+	ChatID    int64      `json:"chatId"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type Identity struct {
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+// Security covers sign-in state; auth tokens are stateless, so there are no server-side sessions to list.
+type Security struct {
+	TwoFactorEnabled bool       `json:"twoFactorEnabled"`
+	Identities       []Identity `json:"identities"`
+	SignInFailures   int        `json:"signInFailures"`
+	LastFailureAt    *time.Time `json:"lastFailureAt,omitempty"`
+	LockedUntil      *time.Time `json:"lockedUntil,omitempty"`
+}
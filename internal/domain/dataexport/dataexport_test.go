@@ -0,0 +1,36 @@
+package dataexport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Job_Downloadable(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 4, 13, 18, 5, 41, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		job  Job
+		want bool
+	}{
+		{name: "ready", job: Job{Status: StatusReady, ExpiresAt: now.Add(time.Hour)}, want: true},
+		{name: "ready but past expiry", job: Job{Status: StatusReady, ExpiresAt: now}, want: false},
+		{name: "running", job: Job{Status: StatusRunning}, want: false},
+		{name: "failed", job: Job{Status: StatusFailed}, want: false},
+		{name: "expired", job: Job{Status: StatusExpired, ExpiresAt: now.Add(-time.Hour)}, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.job.Downloadable(now))
+		})
+	}
+}
@@ -1,8 +1,112 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindAlreadyExists
+	KindPermissionDenied
+	KindUnauthenticated
+	KindInvalidArgument
+	KindResourceExhausted
+	KindFailedPrecondition
+)
 
 var (
-	ErrNotFound      = errors.New("user not found")
-	ErrAlreadyExists = errors.New("user already exists")
+	ErrNotFound      = NewError(KindNotFound, "USER_NOT_FOUND", "user not found")
+	ErrAlreadyExists = NewError(KindAlreadyExists, "USER_ALREADY_EXISTS", "user already exists")
 )
+
+type Error struct {
+	kind       Kind
+	reason     string
+	field      string
+	msg        string
+	retryAfter time.Duration
+}
+
+func NewError(kind Kind, reason, msg string) *Error {
+	return &Error{
+		kind:   kind,
+		reason: reason,
+		msg:    msg,
+	}
+}
+
+func (e *Error) ForField(field string) *Error {
+	cp := *e
+	cp.field = field
+
+	return &cp
+}
+
+func (e *Error) WithRetryAfter(retryAfter time.Duration) *Error {
+	cp := *e
+	cp.retryAfter = retryAfter
+
+	return &cp
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.kind == t.kind && e.reason == t.reason
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Kind() Kind {
+	return e.kind
+}
+
+func (e *Error) Reason() string {
+	return e.reason
+}
+
+func (e *Error) Field() string {
+	return e.field
+}
+
+func (e *Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func AsError(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+
+	return nil, false
+}
+
+func AllErrors(err error) []*Error {
+	if domainErr, ok := err.(*Error); ok {
+		return []*Error{domainErr}
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		res := make([]*Error, 0)
+		for _, inner := range e.Unwrap() {
+			res = append(res, AllErrors(inner)...)
+		}
+
+		return res
+	case interface{ Unwrap() error }:
+		return AllErrors(e.Unwrap())
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package event
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/poll"
+)
+
+type Type string
+
+const (
+	MessageCreated Type = "message.created"
+	MessageEdited  Type = "message.edited"
+	MessageDeleted Type = "message.deleted"
+	MemberAdded    Type = "member.added"
+	MemberRemoved  Type = "member.removed"
+	PollUpdated    Type = "poll.updated"
+
+	// Notice and CommandInvoked only drive live chat streams; webhooks cannot subscribe to them.
+	Notice         Type = "chat.notice"
+	CommandInvoked Type = "command.invoked"
+)
+
+// Valid reports whether webhooks can subscribe to the type.
+func (t Type) Valid() bool {
+	switch t {
+	case MessageCreated, MessageEdited, MessageDeleted, MemberAdded, MemberRemoved, PollUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+type Member struct {
+	UserID int64     `json:"userId"`
+	Role   chat.Role `json:"role,omitempty"`
+}
+
+// Command is a bot command invocation, delivered only to the bot's connections.
+type Command struct {
+	Name        string `json:"name"`
+	Args        string `json:"args"`
+	CallerID    int64  `json:"callerId"`
+	CallerLogin string `json:"callerLogin"`
+	BotID       int64  `json:"botId"`
+}
+
+// Event is the envelope published to Kafka for everything that happens in a chat.
+type Event struct {
+	ID         string        `json:"id"`
+	Type       Type          `json:"type"`
+	ChatID     int64         `json:"chatId"`
+	OccurredAt time.Time     `json:"occurredAt"`
+	Message    *chat.Message `json:"message,omitempty"`
+	Member     *Member       `json:"member,omitempty"`
+	Notice     string        `json:"notice,omitempty"`
+	Command    *Command      `json:"command,omitempty"`
+	Poll       *poll.Poll    `json:"poll,omitempty"`
+}
+
+func NewMessageEvent(eventType Type, msg chat.Message) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		ChatID:     msg.ChatID,
+		OccurredAt: time.Now().UTC(),
+		Message:    &msg,
+	}
+}
+
+// NewPollEvent carries a poll's current tally; a new poll instead rides on the message.created of its message.
+func NewPollEvent(p poll.Poll) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       PollUpdated,
+		ChatID:     p.ChatID,
+		OccurredAt: time.Now().UTC(),
+		Poll:       &p,
+	}
+}
+
+func NewMemberEvent(eventType Type, chatID int64, member Member) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		ChatID:     chatID,
+		OccurredAt: time.Now().UTC(),
+		Member:     &member,
+	}
+}
+
+func NewNoticeEvent(chatID int64, notice string) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       Notice,
+		ChatID:     chatID,
+		OccurredAt: time.Now().UTC(),
+		Notice:     notice,
+	}
+}
+
+func NewCommandEvent(chatID int64, cmd Command) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       CommandInvoked,
+		ChatID:     chatID,
+		OccurredAt: time.Now().UTC(),
+		Command:    &cmd,
+	}
+}
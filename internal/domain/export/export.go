@@ -0,0 +1,52 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+var ErrInvalidFormat = domain.NewError(domain.KindInvalidArgument, "INVALID_EXPORT_FORMAT", "Format must be json, html or text").ForField("format")
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	// FormatHTML is a self-contained transcript with inline styles and no external resources.
+	FormatHTML Format = "html"
+	FormatText Format = "text"
+)
+
+// ParseFormat accepts a format name case-insensitively; empty selects JSON.
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatHTML, FormatText:
+		return format, nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+func (f Format) Extension() string {
+	switch f {
+	case FormatHTML:
+		return "html"
+	case FormatText:
+		return "txt"
+	default:
+		return "json"
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatText:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
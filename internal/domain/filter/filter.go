@@ -0,0 +1,83 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+// DefaultMaxRepeats is how many identical messages in a row a member may send.
+const DefaultMaxRepeats = 3
+
+var (
+	ErrRejected = domain.NewError(domain.KindInvalidArgument, "MESSAGE_REJECTED", "Message was rejected by moderation filters").ForField("text")
+)
+
+type Action string
+
+const (
+	ActionAllow  Action = "allow"
+	ActionMask   Action = "mask"
+	ActionReject Action = "reject"
+)
+
+func ParseAction(value string, fallback Action) (Action, error) {
+	switch Action(strings.ToLower(strings.TrimSpace(value))) {
+	case "":
+		return fallback, nil
+	case ActionMask:
+		return ActionMask, nil
+	case ActionReject:
+		return ActionReject, nil
+	}
+
+	return fallback, fmt.Errorf("unknown filter action %q", value)
+}
+
+type Message struct {
+	ChatID int64
+	UserID int64
+	Text   string
+}
+
+// Verdict is a filter's decision; Text is the rewritten message when masking, Reason is for logs only.
+type Verdict struct {
+	Action Action
+	Text   string
+	Reason string
+}
+
+func Allow() Verdict {
+	return Verdict{Action: ActionAllow}
+}
+
+func Mask(text, reason string) Verdict {
+	return Verdict{Action: ActionMask, Text: text, Reason: reason}
+}
+
+func Reject(reason string) Verdict {
+	return Verdict{Action: ActionReject, Reason: reason}
+}
+
+// Filter inspects a message before it is stored. Filters run in order and each sees the text
+// as masked by the ones before it, so a classifier only has to implement this interface.
+type Filter interface {
+	Name() string
+	Check(ctx context.Context, msg Message) (Verdict, error)
+}
+
+// ParseList splits a comma separated configuration value, dropping blanks.
+func ParseList(value string) []string {
+	items := make([]string, 0)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,75 @@
+package identity
+
+import (
+	"strings"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	minLoginLength = 5
+	maxBaseLength  = 15
+	fallbackLogin  = "user"
+)
+
+var (
+	ErrNotFound      = domain.NewError(domain.KindNotFound, "IDENTITY_NOT_FOUND", "External identity is not linked")
+	ErrAlreadyLinked = domain.NewError(domain.KindAlreadyExists, "IDENTITY_ALREADY_LINKED", "External identity is already linked")
+	ErrInvalidState  = domain.NewError(domain.KindUnauthenticated, "OIDC_INVALID_STATE", "Login flow state is missing or does not match")
+	ErrLoginFailed   = domain.NewError(domain.KindUnauthenticated, "OIDC_LOGIN_FAILED", "Identity provider rejected the login")
+)
+
+// External is an identity asserted by an external provider.
+type External struct {
+	Provider          string
+	Subject           string
+	Email             string
+	PreferredUsername string
+}
+
+type Identity struct {
+	Provider string
+	Subject  string
+	UserID   int64
+	Email    string
+}
+
+// Login derives a local login from the external identity, appending suffix to resolve collisions.
+func (e External) Login(suffix string) domain.Login {
+	base := e.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(e.Email, "@")
+	}
+
+	base = sanitize(base)
+	if base == "" {
+		base = fallbackLogin
+	}
+
+	if len(base) < minLoginLength {
+		base = fallbackLogin + "-" + base
+	}
+
+	if len(base) > maxBaseLength {
+		base = base[:maxBaseLength]
+	}
+
+	if suffix != "" {
+		base += "-" + suffix
+	}
+
+	return domain.Login(base)
+}
+
+func sanitize(value string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(value) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.Trim(b.String(), ".-_")
+}
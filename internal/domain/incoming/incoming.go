@@ -0,0 +1,67 @@
+package incoming
+
+import (
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	MaxPerChat       = 10
+	DefaultRateLimit = 30
+	MaxRateLimit     = 600
+	RateWindow       = time.Minute
+)
+
+var (
+	ErrNotFound         = domain.NewError(domain.KindNotFound, "INCOMING_WEBHOOK_NOT_FOUND", "Incoming webhook not found")
+	ErrInvalidToken     = domain.NewError(domain.KindUnauthenticated, "INVALID_INCOMING_WEBHOOK_TOKEN", "Incoming webhook token is invalid or revoked")
+	ErrRateLimited      = domain.NewError(domain.KindResourceExhausted, "INCOMING_WEBHOOK_RATE_LIMITED", "Incoming webhook rate limit exceeded, try again later")
+	ErrTooMany          = domain.NewError(domain.KindResourceExhausted, "TOO_MANY_INCOMING_WEBHOOKS", "Chat has too many incoming webhooks")
+	ErrInvalidRateLimit = domain.NewError(domain.KindInvalidArgument, "INVALID_RATE_LIMIT", "Rate limit must be between 1 and 600 messages per minute").ForField("rateLimit")
+	ErrInvalidPayload   = domain.NewError(domain.KindInvalidArgument, "INVALID_INCOMING_PAYLOAD", "Body must be a JSON object with a text field").ForField("text")
+)
+
+// Incoming is a chat endpoint that posts messages as its own bot identity, named after the integration.
+type Incoming struct {
+	ID        int64
+	ChatID    int64
+	UserID    int64
+	Name      domain.Login
+	CreatedBy int64
+	TokenHash string
+	RateLimit int
+	CreatedAt time.Time
+	RevokedAt time.Time
+}
+
+func (i Incoming) Revoked() bool {
+	return !i.RevokedAt.IsZero()
+}
+
+// ParseRateLimit treats zero as the default limit.
+func ParseRateLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultRateLimit, nil
+	}
+
+	if limit < 0 || limit > MaxRateLimit {
+		return 0, ErrInvalidRateLimit
+	}
+
+	return limit, nil
+}
+
+// Usage is the number of messages posted through a webhook in the current rate window.
+type Usage struct {
+	Count       int
+	WindowStart time.Time
+}
+
+func (u Usage) RetryAfter(now time.Time) time.Duration {
+	if reset := u.WindowStart.Add(RateWindow); reset.After(now) {
+		return reset.Sub(now)
+	}
+
+	return 0
+}
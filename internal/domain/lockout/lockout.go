@@ -0,0 +1,58 @@
+package lockout
+
+import (
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+var (
+	ErrLocked = domain.NewError(domain.KindResourceExhausted, "TOO_MANY_SIGN_IN_ATTEMPTS", "Too many sign in attempts, try again later")
+)
+
+type Scope string
+
+const (
+	ScopeLogin   Scope = "login"
+	ScopeAddress Scope = "address"
+)
+
+func (s Scope) String() string {
+	return string(s)
+}
+
+type Key struct {
+	Scope Scope
+	Value string
+}
+
+func LoginKey(login domain.Login) Key {
+	return Key{
+		Scope: ScopeLogin,
+		Value: login.String(),
+	}
+}
+
+func AddressKey(address string) Key {
+	return Key{
+		Scope: ScopeAddress,
+		Value: address,
+	}
+}
+
+func (k Key) String() string {
+	return k.Scope.String() + ":" + k.Value
+}
+
+type State struct {
+	Failures    int
+	LockedUntil time.Time
+}
+
+func (s State) RetryAfter(now time.Time) time.Duration {
+	if s.LockedUntil.After(now) {
+		return s.LockedUntil.Sub(now)
+	}
+
+	return 0
+}
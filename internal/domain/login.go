@@ -1,13 +1,12 @@
 package domain
 
 import (
-	"errors"
 	"unicode/utf8"
 )
 
 var (
-	ErrLoginTooShort = errors.New("Login too short (must be more than 4 characters)")
-	ErrLoginTooLong  = errors.New("Login too long must be less that 20 characters")
+	ErrLoginTooShort = NewError(KindInvalidArgument, "LOGIN_TOO_SHORT", "Login too short (must be more than 4 characters)").ForField("login")
+	ErrLoginTooLong  = NewError(KindInvalidArgument, "LOGIN_TOO_LONG", "Login too long must be less that 20 characters").ForField("login")
 )
 
 type Login string
@@ -0,0 +1,113 @@
+package moderation
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	maxReasonLength = 500
+
+	DefaultMuteDuration = 10 * time.Minute
+	MaxDuration         = 365 * 24 * time.Hour
+	DefaultLogLimit     = 50
+	MaxLogLimit         = 500
+)
+
+var (
+	ErrBanned          = domain.NewError(domain.KindPermissionDenied, "USER_BANNED", "User is banned from this chat")
+	ErrNotBanned       = domain.NewError(domain.KindNotFound, "USER_NOT_BANNED", "User is not banned from this chat")
+	ErrReasonTooLong   = domain.NewError(domain.KindInvalidArgument, "MODERATION_REASON_TOO_LONG", "Reason must be at most 500 characters").ForField("reason")
+	ErrInvalidDuration = domain.NewError(domain.KindInvalidArgument, "INVALID_MODERATION_DURATION", "Duration must be positive and at most a year").ForField("duration")
+)
+
+type Action string
+
+const (
+	ActionMute   Action = "mute"
+	ActionUnmute Action = "unmute"
+	ActionKick   Action = "kick"
+	ActionBan    Action = "ban"
+	ActionUnban  Action = "unban"
+
+	// Report actions carry the message they are about in Entry.MessageID.
+	ActionReport        Action = "report"
+	ActionDismissReport Action = "dismiss_report"
+	ActionDeleteMessage Action = "delete_message"
+)
+
+// Entry is one line of a chat's moderation log; ExpiresAt is zero for permanent or instant actions
+// and MessageID is zero unless the action was about a message.
+type Entry struct {
+	ID        int64
+	ChatID    int64
+	ActorID   int64
+	TargetID  int64
+	MessageID int64
+	Action    Action
+	Reason    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Ban keeps a user out of a chat until ExpiresAt, or forever when it is zero.
+type Ban struct {
+	ChatID    int64
+	UserID    int64
+	BannedBy  int64
+	Reason    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (b Ban) Active(now time.Time) bool {
+	return b.ExpiresAt.IsZero() || b.ExpiresAt.After(now)
+}
+
+func (b Ban) RetryAfter(now time.Time) time.Duration {
+	if b.ExpiresAt.IsZero() || !b.ExpiresAt.After(now) {
+		return 0
+	}
+
+	return b.ExpiresAt.Sub(now)
+}
+
+func ValidateReason(reason string) error {
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return ErrReasonTooLong
+	}
+
+	return nil
+}
+
+// ValidateDuration accepts zero, which callers treat as their default, and positive durations up to MaxDuration.
+func ValidateDuration(duration time.Duration) error {
+	if duration < 0 || duration > MaxDuration {
+		return ErrInvalidDuration
+	}
+
+	return nil
+}
+
+// Expiry turns a duration into an absolute expiry; zero stays zero.
+func Expiry(now time.Time, duration time.Duration) time.Time {
+	if duration == 0 {
+		return time.Time{}
+	}
+
+	return now.Add(duration)
+}
+
+func ParseLogLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLogLimit
+	}
+
+	if limit > MaxLogLimit {
+		return MaxLogLimit
+	}
+
+	return limit
+}
@@ -0,0 +1,45 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Ban_Active(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		ban        Ban
+		active     bool
+		retryAfter time.Duration
+	}{
+		{name: "permanent", ban: Ban{}, active: true},
+		{name: "running", ban: Ban{ExpiresAt: now.Add(time.Hour)}, active: true, retryAfter: time.Hour},
+		{name: "expired", ban: Ban{ExpiresAt: now.Add(-time.Second)}, active: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.active, tt.ban.Active(now))
+			assert.Equal(t, tt.retryAfter, tt.ban.RetryAfter(now))
+		})
+	}
+}
+
+func Test_ValidateDuration(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateDuration(0))
+	assert.NoError(t, ValidateDuration(time.Hour))
+	assert.ErrorIs(t, ValidateDuration(-time.Second), ErrInvalidDuration)
+	assert.ErrorIs(t, ValidateDuration(MaxDuration+time.Second), ErrInvalidDuration)
+}
@@ -1,13 +1,12 @@
 package domain
 
 import (
-	"errors"
 	"unicode/utf8"
 )
 
 var (
-	ErrPasswordTooShort = errors.New("Password too short (must be more than 7 characters)")
-	ErrPasswordTooLong  = errors.New("Password too long must be less that 30 characters")
+	ErrPasswordTooShort = NewError(KindInvalidArgument, "PASSWORD_TOO_SHORT", "Password too short (must be more than 7 characters)").ForField("password")
+	ErrPasswordTooLong  = NewError(KindInvalidArgument, "PASSWORD_TOO_LONG", "Password too long must be less that 30 characters").ForField("password")
 )
 
 type Password string
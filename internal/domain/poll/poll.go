@@ -0,0 +1,123 @@
+package poll
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	MinOptions        = 2
+	MaxOptions        = 10
+	MaxQuestionLength = 300
+	MaxOptionLength   = 100
+	MaxDuration       = 30 * 24 * time.Hour
+)
+
+var (
+	ErrNotFound        = domain.NewError(domain.KindNotFound, "POLL_NOT_FOUND", "Poll not found")
+	ErrClosed          = domain.NewError(domain.KindFailedPrecondition, "POLL_CLOSED", "Poll is closed")
+	ErrInvalidQuestion = domain.NewError(domain.KindInvalidArgument, "INVALID_POLL_QUESTION", "Question must be 1 to 300 characters").ForField("question")
+	ErrInvalidOptions  = domain.NewError(domain.KindInvalidArgument, "INVALID_POLL_OPTIONS", "A poll needs 2 to 10 distinct options of 1 to 100 characters").ForField("options")
+	ErrInvalidCloseAt  = domain.NewError(domain.KindInvalidArgument, "INVALID_POLL_CLOSE_TIME", "Close time must be in the future and at most 30 days away").ForField("closesAt")
+	ErrInvalidChoice   = domain.NewError(domain.KindInvalidArgument, "INVALID_POLL_CHOICE", "Choose existing options").ForField("options")
+	ErrSingleChoice    = domain.NewError(domain.KindInvalidArgument, "POLL_SINGLE_CHOICE", "This poll allows only one option").ForField("options")
+	ErrNotCreator      = domain.NewError(domain.KindPermissionDenied, "NOT_POLL_CREATOR", "Only the poll creator or a chat owner can close the poll")
+)
+
+type Option struct {
+	Index int
+	Text  string
+	Votes int
+	// Voters is empty for anonymous polls.
+	Voters []int64
+}
+
+// Poll is attached to a message of kind poll whose text is the question; the message ID identifies the poll.
+type Poll struct {
+	MessageID int64
+	ChatID    int64
+	CreatorID int64
+	Question  string
+	Options   []Option
+	Multiple  bool
+	Anonymous bool
+	// ClosesAt is zero for polls that stay open until closed by hand.
+	ClosesAt  time.Time
+	ClosedAt  time.Time
+	Voters    int
+	CreatedAt time.Time
+}
+
+func (p Poll) Closed(now time.Time) bool {
+	return !p.ClosedAt.IsZero() || (!p.ClosesAt.IsZero() && !now.Before(p.ClosesAt))
+}
+
+// Validate checks a new poll.
+func (p Poll) Validate(now time.Time) error {
+	question := strings.TrimSpace(p.Question)
+	if question == "" || utf8.RuneCountInString(question) > MaxQuestionLength {
+		return ErrInvalidQuestion
+	}
+
+	if len(p.Options) < MinOptions || len(p.Options) > MaxOptions {
+		return ErrInvalidOptions
+	}
+
+	seen := make(map[string]struct{}, len(p.Options))
+	for _, option := range p.Options {
+		text := strings.ToLower(strings.TrimSpace(option.Text))
+		if text == "" || utf8.RuneCountInString(text) > MaxOptionLength {
+			return ErrInvalidOptions
+		}
+
+		if _, ok := seen[text]; ok {
+			return ErrInvalidOptions
+		}
+
+		seen[text] = struct{}{}
+	}
+
+	if !p.ClosesAt.IsZero() && (!p.ClosesAt.After(now) || p.ClosesAt.After(now.Add(MaxDuration))) {
+		return ErrInvalidCloseAt
+	}
+
+	return nil
+}
+
+// NormalizeChoice sorts and de-duplicates a vote; an empty choice retracts the vote.
+func (p Poll) NormalizeChoice(choice []int) ([]int, error) {
+	set := make(map[int]struct{}, len(choice))
+	for _, index := range choice {
+		if index < 0 || index >= len(p.Options) {
+			return nil, ErrInvalidChoice
+		}
+
+		set[index] = struct{}{}
+	}
+
+	if !p.Multiple && len(set) > 1 {
+		return nil, ErrSingleChoice
+	}
+
+	res := make([]int, 0, len(set))
+	for index := range set {
+		res = append(res, index)
+	}
+
+	sort.Ints(res)
+
+	return res, nil
+}
+
+func NewOptions(texts []string) []Option {
+	options := make([]Option, 0, len(texts))
+	for i, text := range texts {
+		options = append(options, Option{Index: i, Text: strings.TrimSpace(text)})
+	}
+
+	return options
+}
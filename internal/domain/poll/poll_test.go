@@ -0,0 +1,77 @@
+package poll
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Poll_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		poll    Poll
+		wantErr error
+	}{
+		{name: "valid", poll: Poll{Question: "Lunch?", Options: NewOptions([]string{"Pizza", "Sushi"})}},
+		{name: "closes later", poll: Poll{Question: "Lunch?", Options: NewOptions([]string{"a", "b"}), ClosesAt: now.Add(time.Hour)}},
+		{name: "empty question", poll: Poll{Question: " ", Options: NewOptions([]string{"a", "b"})}, wantErr: ErrInvalidQuestion},
+		{name: "long question", poll: Poll{Question: strings.Repeat("q", MaxQuestionLength+1), Options: NewOptions([]string{"a", "b"})}, wantErr: ErrInvalidQuestion},
+		{name: "one option", poll: Poll{Question: "q", Options: NewOptions([]string{"a"})}, wantErr: ErrInvalidOptions},
+		{name: "duplicate options", poll: Poll{Question: "q", Options: NewOptions([]string{"Yes", "yes "})}, wantErr: ErrInvalidOptions},
+		{name: "closes in the past", poll: Poll{Question: "q", Options: NewOptions([]string{"a", "b"}), ClosesAt: now.Add(-time.Minute)}, wantErr: ErrInvalidCloseAt},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.poll.Validate(now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Poll_NormalizeChoice(t *testing.T) {
+	t.Parallel()
+
+	single := Poll{Options: NewOptions([]string{"a", "b", "c"})}
+	multiple := Poll{Options: NewOptions([]string{"a", "b", "c"}), Multiple: true}
+
+	choice, err := multiple.NormalizeChoice([]int{2, 0, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, choice)
+
+	choice, err = single.NormalizeChoice(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, choice)
+
+	_, err = single.NormalizeChoice([]int{0, 1})
+	assert.ErrorIs(t, err, ErrSingleChoice)
+
+	_, err = multiple.NormalizeChoice([]int{3})
+	assert.ErrorIs(t, err, ErrInvalidChoice)
+}
+
+func Test_Poll_Closed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	assert.False(t, Poll{}.Closed(now))
+	assert.False(t, Poll{ClosesAt: now.Add(time.Minute)}.Closed(now))
+	assert.True(t, Poll{ClosesAt: now}.Closed(now))
+	assert.True(t, Poll{ClosedAt: now.Add(-time.Minute)}.Closed(now))
+}
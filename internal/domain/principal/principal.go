@@ -0,0 +1,35 @@
+package principal
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+var (
+	ErrNoPrincipal = domain.NewError(domain.KindUnauthenticated, "UNAUTHENTICATED", "Request is not authenticated")
+)
+
+type principalKey struct{}
+
+type Principal struct {
+	UserID int64
+	Login  string
+	ChatID int64
+	Bot    bool
+
+	TokenVersion int64
+}
+
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, error) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	if !ok {
+		return Principal{}, ErrNoPrincipal
+	}
+
+	return p, nil
+}
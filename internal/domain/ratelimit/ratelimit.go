@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+var (
+	ErrRateLimited = domain.NewError(domain.KindResourceExhausted, "RATE_LIMITED", "Too many messages, slow down")
+)
+
+type Scope string
+
+const (
+	ScopeUser Scope = "user"
+	ScopeChat Scope = "chat"
+)
+
+func (s Scope) String() string {
+	return string(s)
+}
+
+type Key struct {
+	Scope Scope
+	Value string
+}
+
+func UserKey(userID int64) Key {
+	return Key{
+		Scope: ScopeUser,
+		Value: strconv.FormatInt(userID, 10),
+	}
+}
+
+func ChatKey(chatID int64) Key {
+	return Key{
+		Scope: ScopeChat,
+		Value: strconv.FormatInt(chatID, 10),
+	}
+}
+
+func (k Key) String() string {
+	return k.Scope.String() + ":" + k.Value
+}
+
+// Bucket is a token bucket holding up to Capacity messages, refilled at PerSecond.
+type Bucket struct {
+	Capacity  float64
+	PerSecond float64
+}
+
+var (
+	UserBucket = Bucket{
+		Capacity:  10,
+		PerSecond: 2,
+	}
+
+	ChatBucket = Bucket{
+		Capacity:  50,
+		PerSecond: 20,
+	}
+)
+
+// Refill returns the tokens available after elapsed time, capped at the capacity.
+func (b Bucket) Refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return math.Min(b.Capacity, tokens+elapsed.Seconds()*b.PerSecond)
+}
+
+// RetryAfter returns how long until a bucket holding tokens has one to spend.
+func (b Bucket) RetryAfter(tokens float64) time.Duration {
+	if tokens >= 1 || b.PerSecond <= 0 {
+		return 0
+	}
+
+	return time.Duration((1 - tokens) / b.PerSecond * float64(time.Second))
+}
+
+// ParseBucket reads a bucket from configuration; empty values keep the fallback.
+func ParseBucket(capacity, perSecond string, fallback Bucket) (Bucket, error) {
+	bucket := fallback
+
+	if capacity != "" {
+		value, err := strconv.ParseFloat(capacity, 64)
+		if err != nil || value < 1 {
+			return fallback, fmt.Errorf("invalid bucket capacity %q", capacity)
+		}
+
+		bucket.Capacity = value
+	}
+
+	if perSecond != "" {
+		value, err := strconv.ParseFloat(perSecond, 64)
+		if err != nil || value <= 0 {
+			return fallback, fmt.Errorf("invalid bucket rate %q", perSecond)
+		}
+
+		bucket.PerSecond = value
+	}
+
+	return bucket, nil
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+)
+
+const (
+	maxReasonLength = 500
+
+	DefaultQueueLimit = 50
+	MaxQueueLimit     = 200
+	DefaultContext    = 5
+	MaxContext        = 50
+)
+
+var (
+	ErrNotFound        = domain.NewError(domain.KindNotFound, "REPORT_NOT_FOUND", "No open reports for this message")
+	ErrAlreadyReported = domain.NewError(domain.KindAlreadyExists, "MESSAGE_ALREADY_REPORTED", "You have already reported this message")
+	ErrOwnMessage      = domain.NewError(domain.KindFailedPrecondition, "CANNOT_REPORT_OWN_MESSAGE", "You cannot report your own message")
+	ErrReasonRequired  = domain.NewError(domain.KindInvalidArgument, "REPORT_REASON_REQUIRED", "Reason is required").ForField("reason")
+	ErrReasonTooLong   = domain.NewError(domain.KindInvalidArgument, "REPORT_REASON_TOO_LONG", "Reason must be at most 500 characters").ForField("reason")
+	ErrInvalidAction   = domain.NewError(domain.KindInvalidArgument, "INVALID_REPORT_ACTION", "Action must be one of dismiss, delete, mute, ban").ForField("action")
+)
+
+// Action is how a moderator resolves the reports against a message.
+type Action string
+
+const (
+	ActionDismiss Action = "dismiss"
+	ActionDelete  Action = "delete"
+	ActionMute    Action = "mute"
+	ActionBan     Action = "ban"
+)
+
+func ParseAction(value string) (Action, error) {
+	switch action := Action(strings.ToLower(strings.TrimSpace(value))); action {
+	case ActionDismiss, ActionDelete, ActionMute, ActionBan:
+		return action, nil
+	}
+
+	return "", ErrInvalidAction
+}
+
+type Report struct {
+	ID         int64
+	ChatID     int64
+	MessageID  int64
+	AuthorID   int64
+	ReporterID int64
+	Reason     string
+	CreatedAt  time.Time
+}
+
+// Summary aggregates the open reports against one message; Text is the message as it was when first reported.
+type Summary struct {
+	ChatID          int64
+	MessageID       int64
+	AuthorID        int64
+	Text            string
+	Count           int
+	Reasons         []string
+	FirstReportedAt time.Time
+	LastReportedAt  time.Time
+}
+
+// Resolution closes every open report on a message. Mute and ban use Duration and Reason like the
+// moderation commands; DeleteMessage also removes the message, which ActionDelete always does.
+type Resolution struct {
+	Action        Action
+	DeleteMessage bool
+	Duration      time.Duration
+	Reason        string
+}
+
+func (r Resolution) Validate() error {
+	_, err := ParseAction(string(r.Action))
+	if err != nil {
+		return err
+	}
+
+	err = moderation.ValidateDuration(r.Duration)
+	if err != nil {
+		return err
+	}
+
+	return moderation.ValidateReason(r.Reason)
+}
+
+func (r Resolution) Deletes() bool {
+	return r.Action == ActionDelete || r.DeleteMessage
+}
+
+func ValidateReason(reason string) error {
+	if strings.TrimSpace(reason) == "" {
+		return ErrReasonRequired
+	}
+
+	if utf8.RuneCountInString(reason) > maxReasonLength {
+		return ErrReasonTooLong
+	}
+
+	return nil
+}
+
+func ParseQueueLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultQueueLimit
+	}
+
+	if limit > MaxQueueLimit {
+		return MaxQueueLimit
+	}
+
+	return limit
+}
+
+// ParseContext bounds how many messages are shown on each side of a reported one.
+func ParseContext(around int) int {
+	if around <= 0 {
+		return DefaultContext
+	}
+
+	if around > MaxContext {
+		return MaxContext
+	}
+
+	return around
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Resolution_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		resolution Resolution
+		wantErr    error
+		deletes    bool
+	}{
+		{name: "dismiss", resolution: Resolution{Action: ActionDismiss}},
+		{name: "delete", resolution: Resolution{Action: ActionDelete}, deletes: true},
+		{name: "ban and delete", resolution: Resolution{Action: ActionBan, DeleteMessage: true}, deletes: true},
+		{name: "mute with duration", resolution: Resolution{Action: ActionMute, Duration: time.Hour}},
+		{name: "unknown action", resolution: Resolution{Action: "warn"}, wantErr: ErrInvalidAction},
+		{name: "negative duration", resolution: Resolution{Action: ActionMute, Duration: -time.Second}, wantErr: moderation.ErrInvalidDuration},
+		{name: "long reason", resolution: Resolution{Action: ActionBan, Reason: strings.Repeat("a", 501)}, wantErr: moderation.ErrReasonTooLong},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.resolution.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.deletes, tt.resolution.Deletes())
+		})
+	}
+}
+
+func Test_ValidateReason(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateReason("spam"))
+	assert.ErrorIs(t, ValidateReason("  "), ErrReasonRequired)
+	assert.ErrorIs(t, ValidateReason(strings.Repeat("a", 501)), ErrReasonTooLong)
+}
+
+func Test_ParseLimits(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultQueueLimit, ParseQueueLimit(0))
+	assert.Equal(t, MaxQueueLimit, ParseQueueLimit(MaxQueueLimit+1))
+	assert.Equal(t, DefaultContext, ParseContext(-1))
+	assert.Equal(t, MaxContext, ParseContext(MaxContext+1))
+	assert.Equal(t, 3, ParseContext(3))
+}
@@ -0,0 +1,89 @@
+package retention
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	MaxKeepDays     = 3650
+	MaxKeepMessages = 10_000_000
+)
+
+var ErrInvalidPolicy = domain.NewError(domain.KindInvalidArgument, "INVALID_RETENTION_POLICY", "Retention must keep at most 3650 days and 10000000 messages, or 0 to use the server default")
+
+// Policy bounds how long and how many messages a chat keeps; a zero field does not limit.
+type Policy struct {
+	KeepDays     int
+	KeepMessages int
+}
+
+// Cutoff is where a chat's policy starts deleting: messages created before At or with an ID up to ID.
+// A zero field matches nothing, so a pass deletes only what was outside the policy when it started.
+type Cutoff struct {
+	ChatID int64
+	At     time.Time
+	ID     int64
+}
+
+func (p Policy) Enabled() bool {
+	return p.KeepDays > 0 || p.KeepMessages > 0
+}
+
+func (p Policy) Validate() error {
+	if p.KeepDays < 0 || p.KeepDays > MaxKeepDays || p.KeepMessages < 0 || p.KeepMessages > MaxKeepMessages {
+		return ErrInvalidPolicy
+	}
+
+	return nil
+}
+
+// Or combines a chat policy with the global one field by field, keeping the stricter non-zero limit: a chat
+// can shorten the server's retention but not keep history longer than the server allows.
+func (p Policy) Or(global Policy) Policy {
+	p.KeepDays = stricter(p.KeepDays, global.KeepDays)
+	p.KeepMessages = stricter(p.KeepMessages, global.KeepMessages)
+
+	return p
+}
+
+func stricter(chat, global int) int {
+	if chat == 0 || (global > 0 && global < chat) {
+		return global
+	}
+
+	return chat
+}
+
+// ParsePolicy reads the global policy from configuration; empty values do not limit.
+func ParsePolicy(days, messages string) (Policy, error) {
+	policy := Policy{}
+
+	if days != "" {
+		value, err := strconv.Atoi(days)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid retention days %q", days)
+		}
+
+		policy.KeepDays = value
+	}
+
+	if messages != "" {
+		value, err := strconv.Atoi(messages)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid retention messages %q", messages)
+		}
+
+		policy.KeepMessages = value
+	}
+
+	err := policy.Validate()
+	if err != nil {
+		return Policy{}, fmt.Errorf("invalid retention policy: %w", err)
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,73 @@
+package retention
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Policy_Or(t *testing.T) {
+	t.Parallel()
+
+	global := Policy{KeepDays: 365, KeepMessages: 100000}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   Policy
+	}{
+		{name: "unset uses global", policy: Policy{}, want: global},
+		{name: "chat days override", policy: Policy{KeepDays: 30}, want: Policy{KeepDays: 30, KeepMessages: 100000}},
+		{name: "chat overrides both", policy: Policy{KeepDays: 7, KeepMessages: 500}, want: Policy{KeepDays: 7, KeepMessages: 500}},
+		{name: "chat cannot exceed global", policy: Policy{KeepDays: 730, KeepMessages: 500}, want: Policy{KeepDays: 365, KeepMessages: 500}},
+		{name: "chat messages tighten global", policy: Policy{KeepMessages: 500}, want: Policy{KeepDays: 365, KeepMessages: 500}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.policy.Or(global))
+		})
+	}
+
+	assert.Equal(t, Policy{KeepDays: 30}, Policy{KeepDays: 30}.Or(Policy{}), "no global limit keeps the chat's")
+}
+
+func Test_ParsePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		days     string
+		messages string
+		want     Policy
+		wantErr  bool
+	}{
+		{name: "empty", want: Policy{}},
+		{name: "both", days: "90", messages: "5000", want: Policy{KeepDays: 90, KeepMessages: 5000}},
+		{name: "not a number", days: "ninety", wantErr: true},
+		{name: "negative", messages: "-1", wantErr: true},
+		{name: "too long", days: "3651", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePolicy(tt.days, tt.messages)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want.KeepDays > 0 || tt.want.KeepMessages > 0, got.Enabled())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	MaxPending = 100
+	MaxAhead   = 365 * 24 * time.Hour
+	// minAhead keeps a schedule from racing the worker that polls every second.
+	minAhead = 5 * time.Second
+)
+
+var (
+	ErrNotFound      = domain.NewError(domain.KindNotFound, "SCHEDULED_MESSAGE_NOT_FOUND", "No pending scheduled message with this id")
+	ErrInvalidSendAt = domain.NewError(domain.KindInvalidArgument, "INVALID_SEND_AT", "Send time must be between 5 seconds and a year from now").ForField("sendAt")
+	ErrTooMany       = domain.NewError(domain.KindResourceExhausted, "TOO_MANY_SCHEDULED_MESSAGES", "You can have at most 100 pending scheduled messages")
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSent      Status = "sent"
+	StatusCancelled Status = "cancelled"
+	// StatusFailed is set when the message could not be posted at send time, e.g. the sender left the chat.
+	StatusFailed Status = "failed"
+)
+
+type Message struct {
+	ID        int64
+	ChatID    int64
+	UserID    int64
+	Text      string
+	SendAt    time.Time
+	Status    Status
+	LastError string
+	MessageID int64
+	CreatedAt time.Time
+}
+
+func ValidateSendAt(now, sendAt time.Time) error {
+	if sendAt.Before(now.Add(minAhead)) || sendAt.After(now.Add(MaxAhead)) {
+		return ErrInvalidSendAt
+	}
+
+	return nil
+}
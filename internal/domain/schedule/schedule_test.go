@@ -0,0 +1,41 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateSendAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		sendAt  time.Time
+		wantErr error
+	}{
+		{name: "in an hour", sendAt: now.Add(time.Hour)},
+		{name: "in the past", sendAt: now.Add(-time.Minute), wantErr: ErrInvalidSendAt},
+		{name: "too soon", sendAt: now.Add(time.Second), wantErr: ErrInvalidSendAt},
+		{name: "too far", sendAt: now.Add(MaxAhead + time.Hour), wantErr: ErrInvalidSendAt},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateSendAt(now, tt.sendAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
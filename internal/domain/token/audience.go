@@ -0,0 +1,28 @@
+package token
+
+import "time"
+
+type Audience string
+
+const (
+	AudienceAuth      Audience = "chat-api/auth"
+	AudienceSession   Audience = "chat-api/session"
+	AudienceChallenge Audience = "chat-api/challenge"
+	// AudienceReauth proves a fresh sign in with an external provider, for accounts without a password.
+	AudienceReauth Audience = "chat-api/reauth"
+)
+
+func (a Audience) String() string {
+	return string(a)
+}
+
+func (a Audience) ValidityTime() time.Duration {
+	switch a {
+	case AudienceSession:
+		return 24 * time.Hour
+	case AudienceChallenge, AudienceReauth:
+		return 5 * time.Minute
+	default:
+		return 72 * time.Hour
+	}
+}
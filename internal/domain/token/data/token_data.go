@@ -6,6 +6,13 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	SubjectKey = "sub"
+	LoginKey   = "login"
+	ChatIDKey  = "chatID"
+	VersionKey = "ver"
+)
+
 type TokenData map[string]string
 
 func (td TokenData) ToMap() jwt.MapClaims {
@@ -18,6 +25,10 @@ func (td TokenData) ToMap() jwt.MapClaims {
 	return res
 }
 
+func (td TokenData) Subject() string {
+	return td[SubjectKey]
+}
+
 func TokenDataFromMap(mapClaims jwt.MapClaims) TokenData {
 	res := TokenData{}
 
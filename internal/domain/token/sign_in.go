@@ -0,0 +1,7 @@
+package token
+
+// SignIn holds either an access token or, when a second factor is required, a challenge token.
+type SignIn struct {
+	Token     Token
+	Challenge Token
+}
@@ -1,10 +1,13 @@
 package token
 
-import "errors"
+import "github.com/monobearotaku/online-chat-api/internal/domain"
 
 var (
-	ErrInvalidToken = errors.New("Invalid JWT token")
-	ErrTokenExpired = errors.New("JWT token expired")
+	ErrMissingToken  = domain.NewError(domain.KindUnauthenticated, "MISSING_TOKEN", "JWT token is missing")
+	ErrInvalidToken  = domain.NewError(domain.KindUnauthenticated, "INVALID_TOKEN", "Invalid JWT token")
+	ErrTokenExpired  = domain.NewError(domain.KindUnauthenticated, "TOKEN_EXPIRED", "JWT token expired")
+	ErrWrongAudience = domain.NewError(domain.KindUnauthenticated, "WRONG_TOKEN_AUDIENCE", "JWT token issued for another audience")
+	ErrTokenRevoked  = domain.NewError(domain.KindUnauthenticated, "TOKEN_REVOKED", "JWT token revoked")
 )
 
 type Token string
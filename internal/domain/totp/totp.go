@@ -0,0 +1,19 @@
+package totp
+
+import "github.com/monobearotaku/online-chat-api/internal/domain"
+
+const RecoveryCodesCount = 10
+
+var (
+	ErrNotEnrolled     = domain.NewError(domain.KindFailedPrecondition, "TOTP_NOT_ENROLLED", "Two-factor authentication is not enrolled")
+	ErrAlreadyEnabled  = domain.NewError(domain.KindAlreadyExists, "TOTP_ALREADY_ENABLED", "Two-factor authentication is already enabled")
+	ErrInvalidCode     = domain.NewError(domain.KindInvalidArgument, "INVALID_TOTP_CODE", "Invalid two-factor authentication code").ForField("code")
+	ErrWrongSecondStep = domain.NewError(domain.KindUnauthenticated, "WRONG_TOTP_CODE", "Two-factor authentication code is incorrect")
+)
+
+type Enrollment struct {
+	UserID       int64
+	Secret       string
+	Confirmed    bool
+	LastUsedStep int64
+}
@@ -0,0 +1,17 @@
+package user
+
+type MessagesPolicy string
+
+const (
+	KeepMessages   MessagesPolicy = "keep"
+	DeleteMessages MessagesPolicy = "delete"
+)
+
+func ParseMessagesPolicy(policy string) MessagesPolicy {
+	switch MessagesPolicy(policy) {
+	case DeleteMessages:
+		return DeleteMessages
+	default:
+		return KeepMessages
+	}
+}
@@ -0,0 +1,46 @@
+package user
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+const (
+	maxDisplayNameLength = 64
+	maxAvatarRefLength   = 512
+	maxBioLength         = 500
+)
+
+var (
+	ErrDisplayNameTooLong = domain.NewError(domain.KindInvalidArgument, "DISPLAY_NAME_TOO_LONG", "Display name too long must be less than 64 characters").ForField("displayName")
+	ErrAvatarRefTooLong   = domain.NewError(domain.KindInvalidArgument, "AVATAR_REF_TOO_LONG", "Avatar reference too long must be less than 512 characters").ForField("avatarRef")
+	ErrBioTooLong         = domain.NewError(domain.KindInvalidArgument, "BIO_TOO_LONG", "Bio too long must be less than 500 characters").ForField("bio")
+)
+
+type Profile struct {
+	UserID      int64
+	Login       domain.Login
+	DisplayName string
+	AvatarRef   string
+	Bio         string
+}
+
+func (p Profile) Validate() error {
+	var errs []error
+
+	if utf8.RuneCountInString(p.DisplayName) > maxDisplayNameLength {
+		errs = append(errs, ErrDisplayNameTooLong)
+	}
+
+	if utf8.RuneCountInString(p.AvatarRef) > maxAvatarRefLength {
+		errs = append(errs, ErrAvatarRefTooLong)
+	}
+
+	if utf8.RuneCountInString(p.Bio) > maxBioLength {
+		errs = append(errs, ErrBioTooLong)
+	}
+
+	return errors.Join(errs...)
+}
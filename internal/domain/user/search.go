@@ -0,0 +1,26 @@
+package user
+
+import "github.com/monobearotaku/online-chat-api/internal/domain"
+
+const (
+	MaxBatchSize       = 100
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+)
+
+var (
+	ErrTooManyUsers       = domain.NewError(domain.KindInvalidArgument, "TOO_MANY_USERS", "Too many users requested (must be at most 100)").ForField("userIds")
+	ErrEmptySearchPrefix  = domain.NewError(domain.KindInvalidArgument, "EMPTY_SEARCH_PREFIX", "Login prefix must not be empty").ForField("loginPrefix")
+	ErrInvalidSearchLimit = domain.NewError(domain.KindInvalidArgument, "INVALID_SEARCH_LIMIT", "Search limit must be between 0 and 50").ForField("limit")
+)
+
+func SearchLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultSearchLimit, nil
+	case limit < 0 || limit > MaxSearchLimit:
+		return 0, ErrInvalidSearchLimit
+	default:
+		return limit, nil
+	}
+}
@@ -6,4 +6,6 @@ type User struct {
 	ID           int64
 	Login        domain.Login
 	PasswordHash domain.Password
+	TokenVersion int64
+	IsBot        bool
 }
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"math/rand"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDelivered Status = "delivered"
+	StatusFailed    Status = "failed"
+)
+
+type Delivery struct {
+	ID             int64
+	WebhookID      int64
+	EventID        string
+	EventType      string
+	Payload        []byte
+	Status         Status
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+
+	// URL and Secret are filled in when a delivery is claimed for sending.
+	URL    string
+	Secret string
+}
+
+// Policy controls retries of a single delivery and when a failing webhook gets disabled.
+type Policy struct {
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	DisableAfter int
+}
+
+var DefaultPolicy = Policy{
+	MaxAttempts:  8,
+	BaseDelay:    10 * time.Second,
+	MaxDelay:     time.Hour,
+	DisableAfter: 5,
+}
+
+// Backoff returns the delay before the next attempt after the given number of failed attempts,
+// doubling each time with up to 20% jitter so that retries of one outage spread out.
+func (p Policy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
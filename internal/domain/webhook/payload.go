@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+)
+
+type payloadMessage struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"userId"`
+	Login     string    `json:"login"`
+	Text      string    `json:"text"`
+	Bot       bool      `json:"bot"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type payloadPollOption struct {
+	Index  int     `json:"index"`
+	Text   string  `json:"text"`
+	Votes  int     `json:"votes"`
+	Voters []int64 `json:"voterIds,omitempty"`
+}
+
+type payloadPoll struct {
+	MessageID int64               `json:"messageId"`
+	CreatorID int64               `json:"creatorId"`
+	Question  string              `json:"question"`
+	Options   []payloadPollOption `json:"options"`
+	Multiple  bool                `json:"multipleChoice"`
+	Anonymous bool                `json:"anonymous"`
+	Voters    int                 `json:"voters"`
+	ClosesAt  *time.Time          `json:"closesAt,omitempty"`
+	ClosedAt  *time.Time          `json:"closedAt,omitempty"`
+}
+
+type payload struct {
+	ID         string          `json:"id"`
+	Type       event.Type      `json:"type"`
+	ChatID     int64           `json:"chatId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Message    *payloadMessage `json:"message,omitempty"`
+	Member     *event.Member   `json:"member,omitempty"`
+	Poll       *payloadPoll    `json:"poll,omitempty"`
+}
+
+// Payload renders the public JSON body sent to webhook endpoints for an event.
+func Payload(evt event.Event) ([]byte, error) {
+	body := payload{
+		ID:         evt.ID,
+		Type:       evt.Type,
+		ChatID:     evt.ChatID,
+		OccurredAt: evt.OccurredAt,
+		Member:     evt.Member,
+	}
+
+	if evt.Message != nil {
+		body.Message = &payloadMessage{
+			ID:        evt.Message.ID,
+			UserID:    evt.Message.UserID,
+			Login:     evt.Message.Login,
+			Text:      evt.Message.Msg,
+			Bot:       evt.Message.Bot,
+			CreatedAt: evt.Message.CreatedAt,
+		}
+	}
+
+	if evt.Poll != nil {
+		body.Poll = &payloadPoll{
+			MessageID: evt.Poll.MessageID,
+			CreatorID: evt.Poll.CreatorID,
+			Question:  evt.Poll.Question,
+			Options:   make([]payloadPollOption, 0, len(evt.Poll.Options)),
+			Multiple:  evt.Poll.Multiple,
+			Anonymous: evt.Poll.Anonymous,
+			Voters:    evt.Poll.Voters,
+			ClosesAt:  optionalTime(evt.Poll.ClosesAt),
+			ClosedAt:  optionalTime(evt.Poll.ClosedAt),
+		}
+
+		for _, option := range evt.Poll.Options {
+			body.Poll.Options = append(body.Poll.Options, payloadPollOption(option))
+		}
+	}
+
+	return json.Marshal(body)
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
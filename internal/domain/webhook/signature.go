@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Chat-Signature"
+	EventHeader     = "X-Chat-Event"
+	DeliveryHeader  = "X-Chat-Delivery"
+)
+
+// Sign produces the signature header value "t=<unix>,v1=<hex hmac-sha256>" over "<unix>.<body>";
+// binding the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	return fmt.Sprintf("t=%s,v1=%s", unix, mac(secret, unix, body))
+}
+
+// Verify checks a signature header against the body, rejecting timestamps outside tolerance.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) bool {
+	var unix, signature string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	sec, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if diff := now.Sub(time.Unix(sec, 0)); diff > tolerance || diff < -tolerance {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	actual, _ := hex.DecodeString(mac(secret, unix, body))
+
+	return hmac.Equal(expected, actual)
+}
+
+func mac(secret, unix string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(unix))
+	h.Write([]byte("."))
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
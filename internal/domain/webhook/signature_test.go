@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Verify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1760000000, 0)
+	body := []byte(`{"type":"message.created"}`)
+	header := Sign("whsec_secret", now, body)
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		valid  bool
+	}{
+		{name: "valid", secret: "whsec_secret", header: header, body: body, now: now, valid: true},
+		{name: "within tolerance", secret: "whsec_secret", header: header, body: body, now: now.Add(4 * time.Minute), valid: true},
+		{name: "expired", secret: "whsec_secret", header: header, body: body, now: now.Add(6 * time.Minute), valid: false},
+		{name: "wrong secret", secret: "whsec_other", header: header, body: body, now: now, valid: false},
+		{name: "tampered body", secret: "whsec_secret", header: header, body: []byte(`{}`), now: now, valid: false},
+		{name: "malformed header", secret: "whsec_secret", header: "v1=abc", body: body, now: now, valid: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.valid, Verify(tt.secret, tt.header, tt.body, tt.now, 5*time.Minute))
+		})
+	}
+}
+
+func Test_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		attempts int
+		min      time.Duration
+	}{
+		{attempts: 1, min: 10 * time.Second},
+		{attempts: 2, min: 20 * time.Second},
+		{attempts: 3, min: 40 * time.Second},
+		{attempts: 4, min: time.Minute},
+		{attempts: 10, min: time.Minute},
+	}
+
+	for _, tt := range tests {
+		delay := policy.Backoff(tt.attempts)
+		assert.GreaterOrEqual(t, delay, tt.min)
+		assert.LessOrEqual(t, delay, tt.min+tt.min/5)
+	}
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+)
+
+const (
+	MaxWebhooksPerChat = 10
+	maxURLLength       = 2048
+)
+
+var (
+	ErrNotFound     = domain.NewError(domain.KindNotFound, "WEBHOOK_NOT_FOUND", "Webhook not found")
+	ErrInvalidURL   = domain.NewError(domain.KindInvalidArgument, "INVALID_WEBHOOK_URL", "Webhook URL must be an absolute https URL").ForField("url")
+	ErrInvalidEvent = domain.NewError(domain.KindInvalidArgument, "INVALID_WEBHOOK_EVENT", "Unknown webhook event type").ForField("events")
+	ErrNoEvents     = domain.NewError(domain.KindInvalidArgument, "WEBHOOK_EVENTS_REQUIRED", "Webhook needs at least one event type").ForField("events")
+	ErrTooMany      = domain.NewError(domain.KindResourceExhausted, "TOO_MANY_WEBHOOKS", "Chat has too many webhooks")
+)
+
+type Webhook struct {
+	ID                  int64
+	ChatID              int64
+	URL                 string
+	Secret              string
+	Events              []event.Type
+	CreatedBy           int64
+	ConsecutiveFailures int
+	DisabledAt          time.Time
+	DisabledReason      string
+	CreatedAt           time.Time
+}
+
+func (w Webhook) Disabled() bool {
+	return !w.DisabledAt.IsZero()
+}
+
+func ValidateURL(raw string) error {
+	if len(raw) > maxURLLength {
+		return ErrInvalidURL
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || parsed.User != nil {
+		return ErrInvalidURL
+	}
+
+	return nil
+}
+
+func ParseEvents(values []string) ([]event.Type, error) {
+	if len(values) == 0 {
+		return nil, ErrNoEvents
+	}
+
+	events := make([]event.Type, 0, len(values))
+	seen := make(map[event.Type]struct{}, len(values))
+
+	for _, value := range values {
+		eventType := event.Type(value)
+		if !eventType.Valid() {
+			return nil, ErrInvalidEvent
+		}
+
+		if _, ok := seen[eventType]; ok {
+			continue
+		}
+
+		seen[eventType] = struct{}{}
+		events = append(events, eventType)
+	}
+
+	return events, nil
+}
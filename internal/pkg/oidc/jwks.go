@@ -0,0 +1,121 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+)
+
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// keySet caches the provider signing keys and refetches them when an unknown key id shows up,
+// which is how providers roll their keys.
+type keySet struct {
+	client *http.Client
+	uri    string
+
+	mu   sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+func newKeySet(client *http.Client, uri string) *keySet {
+	return &keySet{
+		client: client,
+		uri:    uri,
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+}
+
+func (k *keySet) get(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if key, ok := k.keys[kid]; ok {
+		return key, nil
+	}
+
+	keys, err := k.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k.keys = keys
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (k *keySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	set := struct {
+		Keys []JSONWebKey `json:"keys"`
+	}{}
+
+	err := getJSON(ctx, k.client, k.uri, &set)
+	if err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+
+	for _, item := range set.Keys {
+		if item.Kty != "RSA" || (item.Use != "" && item.Use != "sig") {
+			continue
+		}
+
+		key, err := rsaKey(item)
+		if err != nil {
+			return nil, fmt.Errorf("parsing key %q: %w", item.Kid, err)
+		}
+
+		keys[item.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func rsaKey(key JSONWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 3 {
+		return nil, errors.New("unsupported exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+func EncodeRSAKey(kid string, key *rsa.PublicKey) JSONWebKey {
+	return JSONWebKey{
+		Kid: kid,
+		Kty: "RSA",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
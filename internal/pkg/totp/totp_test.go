@@ -0,0 +1,72 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 appendix B vectors for SHA1, truncated to six digits.
+func Test_Code(t *testing.T) {
+	t.Parallel()
+
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		name string
+		unix int64
+		code string
+	}{
+		{name: "59", unix: 59, code: "287082"},
+		{name: "1111111109", unix: 1111111109, code: "081804"},
+		{name: "1111111111", unix: 1111111111, code: "050471"},
+		{name: "1234567890", unix: 1234567890, code: "005924"},
+		{name: "2000000000", unix: 2000000000, code: "279037"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, err := Code(secret, Step(time.Unix(tt.unix, 0)))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	t.Parallel()
+
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	previous, _ := Code(secret, Step(now)-1)
+	step, ok := Validate(secret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	old, _ := Code(secret, Step(now)-3)
+	_, ok = Validate(secret, old, now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func Test_ProvisioningURI(t *testing.T) {
+	t.Parallel()
+
+	uri := ProvisioningURI("online-chat-api", "someone", "SECRET")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/online-chat-api:someone?"))
+	assert.Contains(t, uri, "secret=SECRET")
+	assert.Contains(t, uri, "issuer=online-chat-api")
+}
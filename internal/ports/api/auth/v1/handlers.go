@@ -3,26 +3,39 @@ package v1
 import (
 	"context"
 
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/realip"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
 	"github.com/monobearotaku/online-chat-api/internal/domain/credentials"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token"
 	authv1 "github.com/monobearotaku/online-chat-api/proto/auth/v1"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
+func clientAddr(ctx context.Context) string {
+	addr, ok := realip.FromContext(ctx)
+	if !ok || !addr.IsValid() {
+		return ""
+	}
+
+	return addr.String()
+}
+
 func (a *AuthV1) SignIn(ctx context.Context, request *authv1.SignInRequest) (*authv1.SignInResponse, error) {
 	cred := credentials.NewCredentials(request.Login, request.Password)
 
 	if err := cred.Validate(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
-	token, err := a.authService.SignIn(ctx, cred)
+	signIn, err := a.authService.SignIn(ctx, cred, clientAddr(ctx))
 	if err != nil {
 		return nil, err
 	}
 
 	return &authv1.SignInResponse{
-		Token: token.String(),
+		Token:          signIn.Token.String(),
+		ChallengeToken: signIn.Challenge.String(),
 	}, nil
 }
 
@@ -30,7 +43,7 @@ func (a *AuthV1) SignUp(ctx context.Context, request *authv1.SignUpRequest) (*au
 	cred := credentials.NewCredentials(request.Login, request.Password)
 
 	if err := cred.Validate(); err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, err
 	}
 
 	token, err := a.authService.SignUp(ctx, cred)
@@ -42,3 +55,91 @@ func (a *AuthV1) SignUp(ctx context.Context, request *authv1.SignUpRequest) (*au
 		Token: token.String(),
 	}, nil
 }
+
+func (a *AuthV1) ChangePassword(ctx context.Context, request *authv1.ChangePasswordRequest) (*authv1.ChangePasswordResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, err := a.authService.ChangePassword(ctx, usr.UserID, domain.Password(request.OldPassword), domain.Password(request.NewPassword), token.Token(request.ReauthToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.ChangePasswordResponse{
+		Token: newToken.String(),
+	}, nil
+}
+
+func (a *AuthV1) DeleteAccount(ctx context.Context, request *authv1.DeleteAccountRequest) (*authv1.DeleteAccountResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.authService.DeleteAccount(ctx, usr.UserID, domain.Password(request.Password), token.Token(request.ReauthToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.DeleteAccountResponse{}, nil
+}
+
+func (a *AuthV1) EnrollTotp(ctx context.Context, request *authv1.EnrollTotpRequest) (*authv1.EnrollTotpResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uri, secret, err := a.authService.EnrollTotp(ctx, usr.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.EnrollTotpResponse{
+		ProvisioningUri: uri,
+		Secret:          secret,
+	}, nil
+}
+
+func (a *AuthV1) ConfirmTotp(ctx context.Context, request *authv1.ConfirmTotpRequest) (*authv1.ConfirmTotpResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := a.authService.ConfirmTotp(ctx, usr.UserID, request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.ConfirmTotpResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (a *AuthV1) VerifyTotp(ctx context.Context, request *authv1.VerifyTotpRequest) (*authv1.VerifyTotpResponse, error) {
+	newToken, err := a.authService.VerifyTotp(ctx, token.Token(request.ChallengeToken), request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.VerifyTotpResponse{
+		Token: newToken.String(),
+	}, nil
+}
+
+func (a *AuthV1) DisableTotp(ctx context.Context, request *authv1.DisableTotpRequest) (*authv1.DisableTotpResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.authService.DisableTotp(ctx, usr.UserID, domain.Password(request.Password), token.Token(request.ReauthToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1.DisableTotpResponse{}, nil
+}
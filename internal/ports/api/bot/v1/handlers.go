@@ -0,0 +1,140 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/bot"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	botv1 "github.com/monobearotaku/online-chat-api/proto/bot/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoBot(item bot.Bot) *botv1.Bot {
+	return &botv1.Bot{
+		BotId: item.ID,
+		Login: item.Login.String(),
+	}
+}
+
+func toProtoKey(key apikey.APIKey) *botv1.ApiKey {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	return &botv1.ApiKey{
+		KeyId:      key.ID,
+		BotId:      key.BotID,
+		Prefix:     key.Prefix,
+		Name:       key.Name,
+		Scopes:     scopes,
+		CreatedAt:  toTimestamp(key.CreatedAt),
+		LastUsedAt: toTimestamp(key.LastUsedAt),
+		RevokedAt:  toTimestamp(key.RevokedAt),
+	}
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func (b *BotV1) CreateBot(ctx context.Context, req *botv1.CreateBotRequest) (*botv1.CreateBotResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newBot, err := b.botService.CreateBot(ctx, owner.UserID, domain.Login(req.Login))
+	if err != nil {
+		return nil, err
+	}
+
+	return &botv1.CreateBotResponse{
+		Bot: toProtoBot(newBot),
+	}, nil
+}
+
+func (b *BotV1) GetBots(ctx context.Context, req *botv1.GetBotsRequest) (*botv1.GetBotsResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bots, err := b.botService.GetBots(ctx, owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*botv1.Bot, 0, len(bots))
+	for _, item := range bots {
+		res = append(res, toProtoBot(item))
+	}
+
+	return &botv1.GetBotsResponse{
+		Bots: res,
+	}, nil
+}
+
+func (b *BotV1) CreateApiKey(ctx context.Context, req *botv1.CreateApiKeyRequest) (*botv1.CreateApiKeyResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := apikey.ParseScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, rawKey, err := b.botService.CreateAPIKey(ctx, owner.UserID, req.BotId, req.Name, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &botv1.CreateApiKeyResponse{
+		ApiKey: toProtoKey(key),
+		Key:    rawKey,
+	}, nil
+}
+
+func (b *BotV1) GetApiKeys(ctx context.Context, req *botv1.GetApiKeysRequest) (*botv1.GetApiKeysResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := b.botService.GetAPIKeys(ctx, owner.UserID, req.BotId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*botv1.ApiKey, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, toProtoKey(key))
+	}
+
+	return &botv1.GetApiKeysResponse{
+		ApiKeys: res,
+	}, nil
+}
+
+func (b *BotV1) RevokeApiKey(ctx context.Context, req *botv1.RevokeApiKeyRequest) (*botv1.RevokeApiKeyResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.botService.RevokeAPIKey(ctx, owner.UserID, req.BotId, req.KeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &botv1.RevokeApiKeyResponse{}, nil
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"github.com/monobearotaku/online-chat-api/internal/service/bot"
+	botv1 "github.com/monobearotaku/online-chat-api/proto/bot/v1"
+	"google.golang.org/grpc"
+)
+
+type BotV1 struct {
+	botv1.UnimplementedBotServiceServer
+	botService bot.Service
+}
+
+func NewBotV1(dialer grpc.ServiceRegistrar, botService bot.Service) *BotV1 {
+	server := BotV1{
+		botService: botService,
+	}
+
+	botv1.RegisterBotServiceServer(dialer, &server)
+
+	return &server
+}
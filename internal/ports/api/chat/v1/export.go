@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/export"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+)
+
+const exportChunkSize = 64 << 10
+
+func (c *ChatV1) ExportChat(req *chatv1.ExportChatRequest, stream chatv1.ChatService_ExportChatServer) error {
+	ctx := stream.Context()
+
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(req.Format)
+	if err != nil {
+		return err
+	}
+
+	chunks := &chunkSender{
+		stream:      stream,
+		fileName:    fmt.Sprintf("chat-%d.%s", req.ChatId, format.Extension()),
+		contentType: format.ContentType(),
+	}
+
+	w := bufio.NewWriterSize(chunks, exportChunkSize)
+
+	err = c.exportService.Export(ctx, usr.UserID, req.ChatId, format, w)
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// chunkSender sends every write as one response; the buffered writer in front of it sizes the chunks.
+type chunkSender struct {
+	stream      chatv1.ChatService_ExportChatServer
+	fileName    string
+	contentType string
+	sent        bool
+}
+
+func (c *chunkSender) Write(p []byte) (int, error) {
+	resp := &chatv1.ExportChatResponse{
+		Data: p,
+	}
+
+	if !c.sent {
+		resp.FileName = c.fileName
+		resp.ContentType = c.contentType
+		c.sent = true
+	}
+
+	err := c.stream.Send(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
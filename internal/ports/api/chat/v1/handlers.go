@@ -2,119 +2,160 @@ package v1
 
 import (
 	"context"
-	"strconv"
 
-	"google.golang.org/grpc/metadata"
-
-	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/command"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
 	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func (c *ChatV1) extractUserId(ctx context.Context) (int64, error) {
-	md, _ := metadata.FromIncomingContext(ctx)
-	values := md["authentication"]
+func (c *ChatV1) JoinChat(ctx context.Context, req *chatv1.JoinChatRequest) (*chatv1.JoinChatResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(values) < 1 {
-		return 0, token.ErrInvalidToken
+	tkn, err := c.chatService.JoinChat(ctx, usr.UserID, req.ChatId)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := c.tokenizer.ValidateAndExtractData(ctx, token.Token(values[0]))
+	return &chatv1.JoinChatResponse{
+		Session: tkn.String(),
+	}, nil
+}
+
+func (c *ChatV1) ConnectToChat(stream chatv1.ChatService_ConnectToChatServer) error {
+	ctx := stream.Context()
+
+	session, err := principal.FromContext(ctx)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	userID, err := strconv.ParseInt(data["userID"], 10, 64)
+	err = c.chatService.StartMessaging(ctx, session.UserID, session.ChatID, stream)
 	if err != nil {
-		return 0, token.ErrInvalidToken
+		return err
 	}
 
-	return userID, nil
+	return nil
 }
 
-func (c *ChatV1) extrextractChatAndUserIdFromSession(ctx context.Context) (int64, int64, error) {
-	md, _ := metadata.FromIncomingContext(ctx)
-	values := md["session"]
-
-	if len(values) < 1 {
-		return 0, 0, token.ErrInvalidToken
+func (c *ChatV1) CreateChat(ctx context.Context, req *chatv1.CreateChatRequest) (*chatv1.CreateChatResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := c.tokenizer.ValidateAndExtractData(ctx, token.Token(values[0]))
+	newChat, err := c.chatService.CreateChat(ctx, usr.UserID, req.ChatName)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
 
-	chatID, err := strconv.ParseInt(data["chatID"], 10, 64)
+	return &chatv1.CreateChatResponse{
+		ChatId: newChat.ID,
+	}, nil
+}
+
+func (c *ChatV1) AddUserToChat(ctx context.Context, req *chatv1.AddUserToChatRequest) (*chatv1.AddUserToChatResponse, error) {
+	owner, err := principal.FromContext(ctx)
 	if err != nil {
-		return 0, 0, token.ErrInvalidToken
+		return nil, err
 	}
 
-	userID, err := strconv.ParseInt(data["userID"], 10, 64)
+	err = c.chatService.AddUserToChat(ctx, owner.UserID, req.ChatId, req.UserId)
 	if err != nil {
-		return 0, 0, token.ErrInvalidToken
+		return nil, err
 	}
 
-	return chatID, userID, nil
+	return &chatv1.AddUserToChatResponse{}, nil
 }
 
-func (c *ChatV1) JoinChat(ctx context.Context, req *chatv1.JoinChatRequest) (*chatv1.JoinChatResponse, error) {
-	userID, err := c.extractUserId(ctx)
+func (c *ChatV1) PostMessage(ctx context.Context, req *chatv1.PostMessageRequest) (*chatv1.PostMessageResponse, error) {
+	usr, err := principal.FromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	tkn, err := c.chatService.JoinChat(ctx, userID, req.ChatId)
+	msg, err := c.chatService.PostMessage(ctx, usr.UserID, req.ChatId, req.Text, req.Ttl.AsDuration())
 	if err != nil {
 		return nil, err
 	}
 
-	return &chatv1.JoinChatResponse{
-		Session: tkn.String(),
+	return &chatv1.PostMessageResponse{
+		Message: toProtoMessage(msg),
 	}, nil
 }
 
-func (c *ChatV1) ConnectToChat(stream chatv1.ChatService_ConnectToChatServer) error {
-	ctx := stream.Context()
-
-	chatID, userID, err := c.extrextractChatAndUserIdFromSession(ctx)
+func (c *ChatV1) RegisterCommand(ctx context.Context, req *chatv1.RegisterCommandRequest) (*chatv1.RegisterCommandResponse, error) {
+	usr, err := principal.FromContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = c.chatService.StartMessaging(ctx, userID, chatID, stream)
+	if !usr.Bot {
+		return nil, command.ErrOnlyBotsCanRegister
+	}
+
+	_, err = c.chatService.RegisterBotCommand(ctx, usr.UserID, req.ChatId, req.Name, req.Description)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return &chatv1.RegisterCommandResponse{}, nil
 }
 
-func (c *ChatV1) CreateChat(ctx context.Context, req *chatv1.CreateChatRequest) (*chatv1.CreateChatResponse, error) {
-	userID, err := c.extractUserId(ctx)
+func (c *ChatV1) UnregisterCommand(ctx context.Context, req *chatv1.UnregisterCommandRequest) (*chatv1.UnregisterCommandResponse, error) {
+	usr, err := principal.FromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	newChat, err := c.chatService.CreateChat(ctx, userID, req.ChatName)
+	err = c.chatService.UnregisterBotCommand(ctx, usr.UserID, req.ChatId, req.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	return &chatv1.CreateChatResponse{
-		ChatId: newChat.ID,
-	}, nil
+	return &chatv1.UnregisterCommandResponse{}, nil
 }
 
-func (c *ChatV1) AddUserToChat(ctx context.Context, req *chatv1.AddUserToChatRequest) (*chatv1.AddUserToChatResponse, error) {
-	ownerID, err := c.extractUserId(ctx)
+func (c *ChatV1) GetCommands(ctx context.Context, req *chatv1.GetCommandsRequest) (*chatv1.GetCommandsResponse, error) {
+	usr, err := principal.FromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	err = c.chatService.AddUserToChat(ctx, ownerID, req.ChatId, req.UserId)
+	commands, err := c.chatService.GetCommands(ctx, usr.UserID, req.ChatId)
 	if err != nil {
 		return nil, err
 	}
 
-	return &chatv1.AddUserToChatResponse{}, nil
+	res := make([]*chatv1.Command, 0, len(commands))
+	for _, item := range commands {
+		res = append(res, &chatv1.Command{
+			Name:        item.Name,
+			Description: item.Description,
+			BotId:       item.BotID,
+			BotLogin:    item.BotLogin,
+		})
+	}
+
+	return &chatv1.GetCommandsResponse{
+		Commands: res,
+	}, nil
+}
+
+func toProtoMessage(msg chat.Message) *chatv1.ChatMessageResponse {
+	return &chatv1.ChatMessageResponse{
+		Message:   msg.Msg,
+		UserId:    msg.UserID,
+		ChatId:    msg.ChatID,
+		Login:     msg.Login,
+		Bot:       msg.Bot,
+		MessageId: msg.ID,
+		CreatedAt: timestamppb.New(msg.CreatedAt),
+		Kind:      string(msg.Kind),
+		ExpiresAt: toTimestamp(msg.ExpiresAt),
+	}
 }
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (c *ChatV1) KickMember(ctx context.Context, req *chatv1.KickMemberRequest) (*chatv1.KickMemberResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.KickUser(ctx, owner.UserID, req.ChatId, req.UserId, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.KickMemberResponse{}, nil
+}
+
+func (c *ChatV1) MuteMember(ctx context.Context, req *chatv1.MuteMemberRequest) (*chatv1.MuteMemberResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.MuteUser(ctx, owner.UserID, req.ChatId, req.UserId, req.Duration.AsDuration(), req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.MuteMemberResponse{}, nil
+}
+
+func (c *ChatV1) UnmuteMember(ctx context.Context, req *chatv1.UnmuteMemberRequest) (*chatv1.UnmuteMemberResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.UnmuteUser(ctx, owner.UserID, req.ChatId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.UnmuteMemberResponse{}, nil
+}
+
+func (c *ChatV1) BanUser(ctx context.Context, req *chatv1.BanUserRequest) (*chatv1.BanUserResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.BanUser(ctx, owner.UserID, req.ChatId, req.UserId, req.Duration.AsDuration(), req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.BanUserResponse{}, nil
+}
+
+func (c *ChatV1) UnbanUser(ctx context.Context, req *chatv1.UnbanUserRequest) (*chatv1.UnbanUserResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.UnbanUser(ctx, owner.UserID, req.ChatId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.UnbanUserResponse{}, nil
+}
+
+func (c *ChatV1) GetBans(ctx context.Context, req *chatv1.GetBansRequest) (*chatv1.GetBansResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bans, err := c.chatService.GetBans(ctx, owner.UserID, req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*chatv1.Ban, 0, len(bans))
+	for _, ban := range bans {
+		res = append(res, &chatv1.Ban{
+			UserId:    ban.UserID,
+			BannedBy:  ban.BannedBy,
+			Reason:    ban.Reason,
+			ExpiresAt: toTimestamp(ban.ExpiresAt),
+			CreatedAt: timestamppb.New(ban.CreatedAt),
+		})
+	}
+
+	return &chatv1.GetBansResponse{
+		Bans: res,
+	}, nil
+}
+
+func (c *ChatV1) GetModerationLog(ctx context.Context, req *chatv1.GetModerationLogRequest) (*chatv1.GetModerationLogResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.chatService.GetModerationLog(ctx, owner.UserID, req.ChatId, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*chatv1.ModerationEntry, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, &chatv1.ModerationEntry{
+			EntryId:   entry.ID,
+			ActorId:   entry.ActorID,
+			TargetId:  entry.TargetID,
+			Action:    string(entry.Action),
+			Reason:    entry.Reason,
+			ExpiresAt: toTimestamp(entry.ExpiresAt),
+			CreatedAt: timestamppb.New(entry.CreatedAt),
+			MessageId: entry.MessageID,
+		})
+	}
+
+	return &chatv1.GetModerationLogResponse{
+		Entries: res,
+	}, nil
+}
+
+func (c *ChatV1) SetSlowMode(ctx context.Context, req *chatv1.SetSlowModeRequest) (*chatv1.SetSlowModeResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.SetSlowMode(ctx, owner.UserID, req.ChatId, req.Interval.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.SetSlowModeResponse{}, nil
+}
+
+func (c *ChatV1) SetMessageTTL(ctx context.Context, req *chatv1.SetMessageTTLRequest) (*chatv1.SetMessageTTLResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.SetMessageTTL(ctx, owner.UserID, req.ChatId, req.Ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.SetMessageTTLResponse{}, nil
+}
+
+func (c *ChatV1) SetRetentionPolicy(ctx context.Context, req *chatv1.SetRetentionPolicyRequest) (*chatv1.SetRetentionPolicyResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.SetRetentionPolicy(ctx, owner.UserID, req.ChatId, retention.Policy{
+		KeepDays:     int(req.KeepDays),
+		KeepMessages: int(req.KeepMessages),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.SetRetentionPolicyResponse{}, nil
+}
+
+func (c *ChatV1) SetExportPermission(ctx context.Context, req *chatv1.SetExportPermissionRequest) (*chatv1.SetExportPermissionResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.SetExportPermission(ctx, owner.UserID, req.ChatId, req.UserId, req.Allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.SetExportPermissionResponse{}, nil
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (c *ChatV1) CreatePoll(ctx context.Context, req *chatv1.CreatePollRequest) (*chatv1.CreatePollResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newPoll := poll.Poll{
+		Question:  req.Question,
+		Options:   poll.NewOptions(req.Options),
+		Multiple:  req.MultipleChoice,
+		Anonymous: req.Anonymous,
+	}
+
+	if req.ClosesAt != nil {
+		newPoll.ClosesAt = req.ClosesAt.AsTime()
+	}
+
+	created, err := c.chatService.CreatePoll(ctx, usr.UserID, req.ChatId, newPoll)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.CreatePollResponse{
+		Poll: ToProtoPoll(created),
+	}, nil
+}
+
+func (c *ChatV1) GetPoll(ctx context.Context, req *chatv1.GetPollRequest) (*chatv1.GetPollResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, choice, err := c.chatService.GetPoll(ctx, usr.UserID, req.MessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	myVotes := make([]int32, 0, len(choice))
+	for _, index := range choice {
+		myVotes = append(myVotes, int32(index))
+	}
+
+	return &chatv1.GetPollResponse{
+		Poll:    ToProtoPoll(p),
+		MyVotes: myVotes,
+	}, nil
+}
+
+func (c *ChatV1) VotePoll(ctx context.Context, req *chatv1.VotePollRequest) (*chatv1.VotePollResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	choice := make([]int, 0, len(req.Options))
+	for _, index := range req.Options {
+		choice = append(choice, int(index))
+	}
+
+	p, err := c.chatService.VotePoll(ctx, usr.UserID, req.MessageId, choice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.VotePollResponse{
+		Poll: ToProtoPoll(p),
+	}, nil
+}
+
+func (c *ChatV1) ClosePoll(ctx context.Context, req *chatv1.ClosePollRequest) (*chatv1.ClosePollResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.chatService.ClosePoll(ctx, usr.UserID, req.MessageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.ClosePollResponse{
+		Poll: ToProtoPoll(p),
+	}, nil
+}
+
+// ToProtoPoll maps a poll for responses; the chat service uses it for polls pushed to streams as well.
+func ToProtoPoll(p poll.Poll) *chatv1.Poll {
+	options := make([]*chatv1.PollOption, 0, len(p.Options))
+	for _, option := range p.Options {
+		options = append(options, &chatv1.PollOption{
+			Index:    int32(option.Index),
+			Text:     option.Text,
+			Votes:    int32(option.Votes),
+			VoterIds: option.Voters,
+		})
+	}
+
+	return &chatv1.Poll{
+		MessageId:      p.MessageID,
+		ChatId:         p.ChatID,
+		CreatorId:      p.CreatorID,
+		Question:       p.Question,
+		Options:        options,
+		MultipleChoice: p.Multiple,
+		Anonymous:      p.Anonymous,
+		ClosesAt:       toTimestamp(p.ClosesAt),
+		ClosedAt:       toTimestamp(p.ClosedAt),
+		Closed:         p.Closed(time.Now()),
+		Voters:         int32(p.Voters),
+		CreatedAt:      timestamppb.New(p.CreatedAt),
+	}
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/report"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (c *ChatV1) ReportMessage(ctx context.Context, req *chatv1.ReportMessageRequest) (*chatv1.ReportMessageResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rep, err := c.chatService.ReportMessage(ctx, usr.UserID, req.ChatId, req.MessageId, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.ReportMessageResponse{
+		ReportId: rep.ID,
+	}, nil
+}
+
+func (c *ChatV1) GetReports(ctx context.Context, req *chatv1.GetReportsRequest) (*chatv1.GetReportsResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := c.chatService.GetReports(ctx, owner.UserID, req.ChatId, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*chatv1.MessageReport, 0, len(summaries))
+	for _, summary := range summaries {
+		res = append(res, &chatv1.MessageReport{
+			MessageId:       summary.MessageID,
+			AuthorId:        summary.AuthorID,
+			Text:            summary.Text,
+			Reports:         int32(summary.Count),
+			Reasons:         summary.Reasons,
+			FirstReportedAt: timestamppb.New(summary.FirstReportedAt),
+			LastReportedAt:  timestamppb.New(summary.LastReportedAt),
+		})
+	}
+
+	return &chatv1.GetReportsResponse{
+		Reports: res,
+	}, nil
+}
+
+func (c *ChatV1) GetReportContext(ctx context.Context, req *chatv1.GetReportContextRequest) (*chatv1.GetReportContextResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := c.chatService.GetReportContext(ctx, owner.UserID, req.ChatId, req.MessageId, int(req.Around))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*chatv1.ChatMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		res = append(res, toProtoMessage(msg))
+	}
+
+	return &chatv1.GetReportContextResponse{
+		Messages: res,
+	}, nil
+}
+
+func (c *ChatV1) ResolveReport(ctx context.Context, req *chatv1.ResolveReportRequest) (*chatv1.ResolveReportResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	action, err := report.ParseAction(req.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.ResolveReport(ctx, owner.UserID, req.ChatId, req.MessageId, report.Resolution{
+		Action:        action,
+		DeleteMessage: req.DeleteMessage,
+		Duration:      req.Duration.AsDuration(),
+		Reason:        req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.ResolveReportResponse{}, nil
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/schedule"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (c *ChatV1) ScheduleMessage(ctx context.Context, req *chatv1.ScheduleMessageRequest) (*chatv1.ScheduleMessageResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := c.chatService.ScheduleMessage(ctx, usr.UserID, req.ChatId, req.Text, req.SendAt.AsTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.ScheduleMessageResponse{
+		Message: toProtoScheduled(msg),
+	}, nil
+}
+
+func (c *ChatV1) GetScheduledMessages(ctx context.Context, req *chatv1.GetScheduledMessagesRequest) (*chatv1.GetScheduledMessagesResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := c.chatService.GetScheduledMessages(ctx, usr.UserID, req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*chatv1.ScheduledMessage, 0, len(messages))
+	for _, msg := range messages {
+		res = append(res, toProtoScheduled(msg))
+	}
+
+	return &chatv1.GetScheduledMessagesResponse{
+		Messages: res,
+	}, nil
+}
+
+func (c *ChatV1) CancelScheduledMessage(ctx context.Context, req *chatv1.CancelScheduledMessageRequest) (*chatv1.CancelScheduledMessageResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.chatService.CancelScheduledMessage(ctx, usr.UserID, req.ScheduledId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv1.CancelScheduledMessageResponse{}, nil
+}
+
+func toProtoScheduled(msg schedule.Message) *chatv1.ScheduledMessage {
+	return &chatv1.ScheduledMessage{
+		ScheduledId: msg.ID,
+		ChatId:      msg.ChatID,
+		Text:        msg.Text,
+		SendAt:      timestamppb.New(msg.SendAt),
+		CreatedAt:   timestamppb.New(msg.CreatedAt),
+		Status:      string(msg.Status),
+		LastError:   msg.LastError,
+	}
+}
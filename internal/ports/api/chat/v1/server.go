@@ -2,21 +2,21 @@ package v1
 
 import (
 	"github.com/monobearotaku/online-chat-api/internal/service/chat"
-	"github.com/monobearotaku/online-chat-api/internal/service/tokenizer"
+	"github.com/monobearotaku/online-chat-api/internal/service/export"
 	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
 	"google.golang.org/grpc"
 )
 
 type ChatV1 struct {
 	chatv1.UnimplementedChatServiceServer
-	chatService chat.Service
-	tokenizer   tokenizer.Tokenizer
+	chatService   chat.Service
+	exportService export.Service
 }
 
-func NewChatV1(dialer grpc.ServiceRegistrar, chatService chat.Service, tokenizer tokenizer.Tokenizer) *ChatV1 {
+func NewChatV1(dialer grpc.ServiceRegistrar, chatService chat.Service, exportService export.Service) *ChatV1 {
 	server := ChatV1{
-		chatService: chatService,
-		tokenizer:   tokenizer,
+		chatService:   chatService,
+		exportService: exportService,
 	}
 
 	chatv1.RegisterChatServiceServer(dialer, &server)
@@ -0,0 +1,174 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
+	"github.com/monobearotaku/online-chat-api/internal/pkg/slices"
+	"github.com/monobearotaku/online-chat-api/internal/service/tokenizer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authenticationKey = "authentication"
+	sessionKey        = "session"
+	apiKeyKey         = "x-api-key"
+)
+
+// Access describes which credentials a method requires.
+type Access int
+
+const (
+	// Authenticated methods require an auth token in the "authentication" metadata key,
+	// or an API key in "x-api-key" whose scopes cover the method.
+	Authenticated Access = iota
+	// Public methods are served without any credentials.
+	Public
+	// Session methods require a chat session token in the "session" metadata key.
+	Session
+)
+
+type TokenVersionChecker interface {
+	CheckTokenVersion(ctx context.Context, userID int64, version int64) error
+}
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (principal.Principal, []apikey.Scope, error)
+}
+
+type Authenticator struct {
+	tokenizer tokenizer.Tokenizer
+	versions  TokenVersionChecker
+	apiKeys   APIKeyAuthenticator
+	methods   map[string]Access
+	scopes    map[string]apikey.Scope
+}
+
+// NewAuthenticator creates an authenticator; methods missing from the map require an auth token.
+// API keys are only accepted for methods listed in scopes.
+func NewAuthenticator(tokenizer tokenizer.Tokenizer, versions TokenVersionChecker, apiKeys APIKeyAuthenticator, methods map[string]Access, scopes map[string]apikey.Scope) *Authenticator {
+	return &Authenticator{
+		tokenizer: tokenizer,
+		versions:  versions,
+		apiKeys:   apiKeys,
+		methods:   methods,
+		scopes:    scopes,
+	}
+}
+
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return auth.UnaryServerInterceptor(a.authenticate)
+}
+
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return auth.StreamServerInterceptor(a.authenticate)
+}
+
+func (a *Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	method, _ := grpc.Method(ctx)
+
+	switch a.methods[method] {
+	case Public:
+		return ctx, nil
+	case Session:
+		return a.authenticateWith(ctx, sessionKey, token.AudienceSession)
+	default:
+		if key := metadata.ValueFromIncomingContext(ctx, apiKeyKey); len(key) > 0 {
+			return a.authenticateAPIKey(ctx, method, key[0])
+		}
+
+		return a.authenticateWith(ctx, authenticationKey, token.AudienceAuth)
+	}
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, method, key string) (context.Context, error) {
+	p, scopes, err := a.apiKeys.AuthenticateAPIKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, apikey.ErrInvalidKey) {
+			return nil, unauthenticated(err)
+		}
+
+		return nil, err
+	}
+
+	required, ok := a.scopes[method]
+	if !ok || !slices.Contains(scopes, required) {
+		st, _ := domainStatus(apikey.ErrScopeDenied)
+		return nil, st.Err()
+	}
+
+	return principal.NewContext(ctx, p), nil
+}
+
+func (a *Authenticator) authenticateWith(ctx context.Context, key string, audience token.Audience) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md[key]
+
+	if len(values) < 1 {
+		return nil, unauthenticated(token.ErrMissingToken)
+	}
+
+	tokenData, err := a.tokenizer.ValidateAndExtractData(ctx, token.Token(values[0]), audience)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+
+	p, err := principalFromTokenData(tokenData, audience)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+
+	err = a.versions.CheckTokenVersion(ctx, p.UserID, p.TokenVersion)
+	if err != nil {
+		if errors.Is(err, token.ErrTokenRevoked) {
+			return nil, unauthenticated(err)
+		}
+
+		return nil, err
+	}
+
+	return principal.NewContext(ctx, p), nil
+}
+
+func unauthenticated(err error) error {
+	if st, ok := domainStatus(err); ok && st.Code() == codes.Unauthenticated {
+		return st.Err()
+	}
+
+	return status.Error(codes.Unauthenticated, token.ErrInvalidToken.Error())
+}
+
+func principalFromTokenData(tokenData data.TokenData, audience token.Audience) (principal.Principal, error) {
+	userID, err := strconv.ParseInt(tokenData.Subject(), 10, 64)
+	if err != nil {
+		return principal.Principal{}, token.ErrInvalidToken
+	}
+
+	p := principal.Principal{
+		UserID: userID,
+		Login:  tokenData[data.LoginKey],
+	}
+
+	p.TokenVersion, err = strconv.ParseInt(tokenData[data.VersionKey], 10, 64)
+	if err != nil {
+		return principal.Principal{}, token.ErrInvalidToken
+	}
+
+	if audience == token.AudienceSession {
+		p.ChatID, err = strconv.ParseInt(tokenData[data.ChatIDKey], 10, 64)
+		if err != nil {
+			return principal.Principal{}, token.ErrInvalidToken
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,164 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
+	"github.com/monobearotaku/online-chat-api/internal/service/tokenizer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type methodStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (m methodStream) Method() string {
+	return m.method
+}
+
+type versionChecker struct {
+	version int64
+}
+
+func (v versionChecker) CheckTokenVersion(ctx context.Context, userID int64, version int64) error {
+	if version != v.version {
+		return token.ErrTokenRevoked
+	}
+
+	return nil
+}
+
+type apiKeys struct{}
+
+func (apiKeys) AuthenticateAPIKey(ctx context.Context, key string) (principal.Principal, []apikey.Scope, error) {
+	if key != "valid-key" {
+		return principal.Principal{}, nil, apikey.ErrInvalidKey
+	}
+
+	return principal.Principal{UserID: 7, Login: "robot", Bot: true}, []apikey.Scope{apikey.ScopeMessagesWrite}, nil
+}
+
+func Test_Authenticator_UnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tr := tokenizer.NewTokenizer()
+	ctx := context.Background()
+
+	authTkn, _ := tr.CreateToken(ctx, token.AudienceAuth, "1", data.TokenData{data.LoginKey: "login", data.VersionKey: "3"})
+	sessionTkn, _ := tr.CreateToken(ctx, token.AudienceSession, "1", data.TokenData{data.ChatIDKey: "2", data.VersionKey: "3"})
+	revokedTkn, _ := tr.CreateToken(ctx, token.AudienceAuth, "1", data.TokenData{data.LoginKey: "login", data.VersionKey: "2"})
+
+	authenticator := NewAuthenticator(tr, versionChecker{version: 3}, apiKeys{}, map[string]Access{
+		"/public":  Public,
+		"/session": Session,
+	}, map[string]apikey.Scope{
+		"/post":   apikey.ScopeMessagesWrite,
+		"/manage": apikey.ScopeChatsManage,
+	})
+
+	tests := []struct {
+		name      string
+		method    string
+		md        metadata.MD
+		code      codes.Code
+		principal principal.Principal
+	}{
+		{
+			name:   "Public Method Without Token",
+			method: "/public",
+			code:   codes.OK,
+		},
+		{
+			name:   "Private Method Without Token",
+			method: "/private",
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:      "Private Method With Auth Token",
+			method:    "/private",
+			md:        metadata.Pairs(authenticationKey, authTkn.String()),
+			code:      codes.OK,
+			principal: principal.Principal{UserID: 1, Login: "login", TokenVersion: 3},
+		},
+		{
+			name:   "Private Method With Revoked Token",
+			method: "/private",
+			md:     metadata.Pairs(authenticationKey, revokedTkn.String()),
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "Private Method With Session Token",
+			method: "/private",
+			md:     metadata.Pairs(authenticationKey, sessionTkn.String()),
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:      "Session Method With Session Token",
+			method:    "/session",
+			md:        metadata.Pairs(sessionKey, sessionTkn.String()),
+			code:      codes.OK,
+			principal: principal.Principal{UserID: 1, ChatID: 2, TokenVersion: 3},
+		},
+		{
+			name:      "Scoped Method With API Key",
+			method:    "/post",
+			md:        metadata.Pairs(apiKeyKey, "valid-key"),
+			code:      codes.OK,
+			principal: principal.Principal{UserID: 7, Login: "robot", Bot: true},
+		},
+		{
+			name:   "Method Outside Key Scopes",
+			method: "/manage",
+			md:     metadata.Pairs(apiKeyKey, "valid-key"),
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Unscoped Method With API Key",
+			method: "/private",
+			md:     metadata.Pairs(apiKeyKey, "valid-key"),
+			code:   codes.PermissionDenied,
+		},
+		{
+			name:   "Invalid API Key",
+			method: "/post",
+			md:     metadata.Pairs(apiKeyKey, "revoked-key"),
+			code:   codes.Unauthenticated,
+		},
+		{
+			name:   "Session Method With Auth Token",
+			method: "/session",
+			md:     metadata.Pairs(sessionKey, authTkn.String()),
+			code:   codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			callCtx := grpc.NewContextWithServerTransportStream(ctx, methodStream{method: tt.method})
+			callCtx = metadata.NewIncomingContext(callCtx, tt.md)
+
+			var got principal.Principal
+			_, err := authenticator.UnaryServerInterceptor()(callCtx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					got, _ = principal.FromContext(ctx)
+					return nil, nil
+				})
+
+			assert.Equal(t, tt.code, status.Code(err))
+			assert.Equal(t, tt.principal, got)
+		})
+	}
+}
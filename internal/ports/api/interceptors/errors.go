@@ -0,0 +1,160 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const errorDomain = "online-chat-api"
+
+type ErrorTranslator struct {
+	logger log.Logger
+}
+
+func NewErrorTranslator(logger log.Logger) *ErrorTranslator {
+	return &ErrorTranslator{
+		logger: logger,
+	}
+}
+
+func (e *ErrorTranslator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, e.translate(info.FullMethod, err)
+		}
+
+		return resp, nil
+	}
+}
+
+func (e *ErrorTranslator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, stream)
+		if err != nil {
+			return e.translate(info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+func (e *ErrorTranslator) translate(method string, err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		return err
+	}
+
+	if st, ok := domainStatus(err); ok {
+		return st.Err()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, context.Canceled.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	}
+
+	correlationID := uuid.NewString()
+
+	level.Error(e.logger).Log(
+		"error", err,
+		"grpc.method", method,
+		"correlationID", correlationID,
+	)
+
+	return withDetails(
+		status.New(codes.Internal, fmt.Sprintf("internal error, correlation id: %s", correlationID)),
+		&errdetails.ErrorInfo{
+			Reason: "INTERNAL",
+			Domain: errorDomain,
+		},
+		&errdetails.RequestInfo{
+			RequestId: correlationID,
+		},
+	).Err()
+}
+
+func domainStatus(err error) (*status.Status, bool) {
+	domainErr, ok := domain.AsError(err)
+	if !ok {
+		return nil, false
+	}
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason: domainErr.Reason(),
+			Domain: errorDomain,
+		},
+	}
+
+	code := codeFromKind(domainErr.Kind())
+	msg := domainErr.Error()
+
+	if code == codes.InvalidArgument {
+		badRequest := &errdetails.BadRequest{}
+		all := domain.AllErrors(err)
+
+		msgs := make([]error, 0, len(all))
+		for _, item := range all {
+			msgs = append(msgs, item)
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       item.Field(),
+				Description: item.Error(),
+			})
+		}
+
+		msg = errors.Join(msgs...).Error()
+		details = append(details, badRequest)
+	}
+
+	if domainErr.RetryAfter() > 0 {
+		details = append(details, &errdetails.RetryInfo{
+			RetryDelay: durationpb.New(domainErr.RetryAfter()),
+		})
+	}
+
+	return withDetails(status.New(code, msg), details...), true
+}
+
+func codeFromKind(kind domain.Kind) codes.Code {
+	switch kind {
+	case domain.KindNotFound:
+		return codes.NotFound
+	case domain.KindAlreadyExists:
+		return codes.AlreadyExists
+	case domain.KindPermissionDenied:
+		return codes.PermissionDenied
+	case domain.KindUnauthenticated:
+		return codes.Unauthenticated
+	case domain.KindInvalidArgument:
+		return codes.InvalidArgument
+	case domain.KindResourceExhausted:
+		return codes.ResourceExhausted
+	case domain.KindFailedPrecondition:
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
@@ -0,0 +1,97 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/credentials"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_ErrorTranslator_translate(t *testing.T) {
+	t.Parallel()
+
+	translator := NewErrorTranslator(log.NewNopLogger())
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{
+			name: "Not Found",
+			err:  chat.ErrChatNotFound,
+			code: codes.NotFound,
+			msg:  chat.ErrChatNotFound.Error(),
+		},
+		{
+			name: "Wrapped Permission Denied",
+			err:  fmt.Errorf("Chat.Service.AddUserToChat: %w", chat.ErrUserNotOwner),
+			code: codes.PermissionDenied,
+			msg:  chat.ErrUserNotOwner.Error(),
+		},
+		{
+			name: "Invalid Argument",
+			err:  credentials.NewCredentials("abc", "abc").Validate(),
+			code: codes.InvalidArgument,
+			msg:  errors.Join(domain.ErrLoginTooShort, domain.ErrPasswordTooShort).Error(),
+		},
+		{
+			name: "Status Passes Through",
+			err:  status.Error(codes.Unauthenticated, "nope"),
+			code: codes.Unauthenticated,
+			msg:  "nope",
+		},
+		{
+			name: "Canceled",
+			err:  fmt.Errorf("reading stream: %w", context.Canceled),
+			code: codes.Canceled,
+			msg:  context.Canceled.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			st := status.Convert(translator.translate("/method", tt.err))
+
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.msg, st.Message())
+		})
+	}
+}
+
+func Test_ErrorTranslator_translateInternal(t *testing.T) {
+	t.Parallel()
+
+	translator := NewErrorTranslator(log.NewNopLogger())
+
+	st := status.Convert(translator.translate("/method", errors.New("pq: relation \"users\" does not exist")))
+
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.NotContains(t, st.Message(), "users")
+
+	var requestInfo *errdetails.RequestInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RequestInfo); ok {
+			requestInfo = info
+		}
+	}
+
+	if assert.NotNil(t, requestInfo) {
+		assert.True(t, strings.HasSuffix(st.Message(), requestInfo.RequestId))
+	}
+}
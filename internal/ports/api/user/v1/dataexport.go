@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	userv1 "github.com/monobearotaku/online-chat-api/proto/user/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const downloadChunkSize = 64 << 10
+
+func toProtoDataExport(job dataexport.Job) *userv1.DataExport {
+	res := &userv1.DataExport{
+		ExportId:  job.ID,
+		Status:    string(job.Status),
+		Error:     job.Error,
+		SizeBytes: job.Size,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+	}
+
+	if !job.CompletedAt.IsZero() {
+		res.CompletedAt = timestamppb.New(job.CompletedAt)
+	}
+
+	if !job.ExpiresAt.IsZero() {
+		res.ExpiresAt = timestamppb.New(job.ExpiresAt)
+	}
+
+	return res
+}
+
+func (u *UserV1) ExportMyData(ctx context.Context, req *userv1.ExportMyDataRequest) (*userv1.ExportMyDataResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := u.dataExportService.Request(ctx, usr.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.ExportMyDataResponse{
+		Export: toProtoDataExport(job),
+	}, nil
+}
+
+func (u *UserV1) GetMyDataExport(ctx context.Context, req *userv1.GetMyDataExportRequest) (*userv1.GetMyDataExportResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := u.dataExportService.GetJob(ctx, usr.UserID, req.ExportId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.GetMyDataExportResponse{
+		Export: toProtoDataExport(job),
+	}, nil
+}
+
+func (u *UserV1) DownloadMyData(req *userv1.DownloadMyDataRequest, stream userv1.UserService_DownloadMyDataServer) error {
+	ctx := stream.Context()
+
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	chunks := &chunkSender{
+		stream:   stream,
+		fileName: fmt.Sprintf("data-export-%d.zip", req.ExportId),
+	}
+
+	w := bufio.NewWriterSize(chunks, downloadChunkSize)
+
+	err = u.dataExportService.Download(ctx, usr.UserID, req.ExportId, w)
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// chunkSender sends every write as one response; the buffered writer in front of it sizes the chunks.
+type chunkSender struct {
+	stream   userv1.UserService_DownloadMyDataServer
+	fileName string
+	sent     bool
+}
+
+func (c *chunkSender) Write(p []byte) (int, error) {
+	resp := &userv1.DownloadMyDataResponse{
+		Data: p,
+	}
+
+	if !c.sent {
+		resp.FileName = c.fileName
+		c.sent = true
+	}
+
+	err := c.stream.Send(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	userv1 "github.com/monobearotaku/online-chat-api/proto/user/v1"
+)
+
+func toProto(profile user.Profile) *userv1.Profile {
+	return &userv1.Profile{
+		UserId:      profile.UserID,
+		Login:       profile.Login.String(),
+		DisplayName: profile.DisplayName,
+		AvatarRef:   profile.AvatarRef,
+		Bio:         profile.Bio,
+	}
+}
+
+func toProtoList(profiles []user.Profile) []*userv1.Profile {
+	res := make([]*userv1.Profile, 0, len(profiles))
+
+	for _, profile := range profiles {
+		res = append(res, toProto(profile))
+	}
+
+	return res
+}
+
+func (u *UserV1) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.GetUserResponse, error) {
+	profile, err := u.userService.GetUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.GetUserResponse{
+		Profile: toProto(profile),
+	}, nil
+}
+
+func (u *UserV1) GetUsers(ctx context.Context, req *userv1.GetUsersRequest) (*userv1.GetUsersResponse, error) {
+	profiles, err := u.userService.GetUsers(ctx, req.UserIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.GetUsersResponse{
+		Profiles: toProtoList(profiles),
+	}, nil
+}
+
+func (u *UserV1) SearchUsers(ctx context.Context, req *userv1.SearchUsersRequest) (*userv1.SearchUsersResponse, error) {
+	profiles, err := u.userService.SearchUsers(ctx, req.LoginPrefix, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.SearchUsersResponse{
+		Profiles: toProtoList(profiles),
+	}, nil
+}
+
+func (u *UserV1) UpdateProfile(ctx context.Context, req *userv1.UpdateProfileRequest) (*userv1.UpdateProfileResponse, error) {
+	usr, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := u.userService.UpdateProfile(ctx, user.Profile{
+		UserID:      usr.UserID,
+		DisplayName: req.DisplayName,
+		AvatarRef:   req.AvatarRef,
+		Bio:         req.Bio,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &userv1.UpdateProfileResponse{
+		Profile: toProto(profile),
+	}, nil
+}
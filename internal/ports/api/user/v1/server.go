@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"github.com/monobearotaku/online-chat-api/internal/service/dataexport"
+	"github.com/monobearotaku/online-chat-api/internal/service/user"
+	userv1 "github.com/monobearotaku/online-chat-api/proto/user/v1"
+	"google.golang.org/grpc"
+)
+
+type UserV1 struct {
+	userv1.UnimplementedUserServiceServer
+	userService       user.Service
+	dataExportService dataexport.Service
+}
+
+func NewUserV1(dialer grpc.ServiceRegistrar, userService user.Service, dataExportService dataexport.Service) *UserV1 {
+	server := UserV1{
+		userService:       userService,
+		dataExportService: dataExportService,
+	}
+
+	userv1.RegisterUserServiceServer(dialer, &server)
+
+	return &server
+}
@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
+	webhookv1 "github.com/monobearotaku/online-chat-api/proto/webhook/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoWebhook(hook webhook.Webhook) *webhookv1.Webhook {
+	events := make([]string, 0, len(hook.Events))
+	for _, item := range hook.Events {
+		events = append(events, string(item))
+	}
+
+	return &webhookv1.Webhook{
+		WebhookId:           hook.ID,
+		ChatId:              hook.ChatID,
+		Url:                 hook.URL,
+		Events:              events,
+		Disabled:            hook.Disabled(),
+		DisabledReason:      hook.DisabledReason,
+		ConsecutiveFailures: int32(hook.ConsecutiveFailures),
+		CreatedAt:           toTimestamp(hook.CreatedAt),
+	}
+}
+
+func toProtoDelivery(delivery webhook.Delivery) *webhookv1.Delivery {
+	res := &webhookv1.Delivery{
+		DeliveryId:     delivery.ID,
+		EventId:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         string(delivery.Status),
+		Attempts:       int32(delivery.Attempts),
+		LastStatusCode: int32(delivery.LastStatusCode),
+		LastError:      delivery.LastError,
+		CreatedAt:      toTimestamp(delivery.CreatedAt),
+		DeliveredAt:    toTimestamp(delivery.DeliveredAt),
+	}
+
+	if delivery.Status == webhook.StatusPending {
+		res.NextAttemptAt = toTimestamp(delivery.NextAttemptAt)
+	}
+
+	return res
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func (w *WebhookV1) CreateWebhook(ctx context.Context, req *webhookv1.CreateWebhookRequest) (*webhookv1.CreateWebhookResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := webhook.ParseEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := w.webhookService.CreateWebhook(ctx, owner.UserID, req.ChatId, req.Url, events)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.CreateWebhookResponse{
+		Webhook: toProtoWebhook(hook),
+		Secret:  hook.Secret,
+	}, nil
+}
+
+func (w *WebhookV1) GetWebhooks(ctx context.Context, req *webhookv1.GetWebhooksRequest) (*webhookv1.GetWebhooksResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := w.webhookService.GetWebhooks(ctx, owner.UserID, req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*webhookv1.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		res = append(res, toProtoWebhook(hook))
+	}
+
+	return &webhookv1.GetWebhooksResponse{
+		Webhooks: res,
+	}, nil
+}
+
+func (w *WebhookV1) DeleteWebhook(ctx context.Context, req *webhookv1.DeleteWebhookRequest) (*webhookv1.DeleteWebhookResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.webhookService.DeleteWebhook(ctx, owner.UserID, req.ChatId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.DeleteWebhookResponse{}, nil
+}
+
+func (w *WebhookV1) EnableWebhook(ctx context.Context, req *webhookv1.EnableWebhookRequest) (*webhookv1.EnableWebhookResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.webhookService.EnableWebhook(ctx, owner.UserID, req.ChatId, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.EnableWebhookResponse{}, nil
+}
+
+func (w *WebhookV1) GetWebhookDeliveries(ctx context.Context, req *webhookv1.GetWebhookDeliveriesRequest) (*webhookv1.GetWebhookDeliveriesResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := w.webhookService.GetDeliveries(ctx, owner.UserID, req.ChatId, req.WebhookId, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*webhookv1.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, toProtoDelivery(delivery))
+	}
+
+	return &webhookv1.GetWebhookDeliveriesResponse{
+		Deliveries: res,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	incoming_http "github.com/monobearotaku/online-chat-api/internal/ports/http/incoming"
+	webhookv1 "github.com/monobearotaku/online-chat-api/proto/webhook/v1"
+)
+
+func toProtoIncoming(hook incoming.Incoming) *webhookv1.IncomingWebhook {
+	return &webhookv1.IncomingWebhook{
+		IncomingWebhookId: hook.ID,
+		ChatId:            hook.ChatID,
+		Name:              hook.Name.String(),
+		UserId:            hook.UserID,
+		RateLimit:         int32(hook.RateLimit),
+		Revoked:           hook.Revoked(),
+		Path:              incoming_http.PathPrefix + strconv.FormatInt(hook.ID, 10),
+		CreatedAt:         toTimestamp(hook.CreatedAt),
+	}
+}
+
+func (w *WebhookV1) CreateIncomingWebhook(ctx context.Context, req *webhookv1.CreateIncomingWebhookRequest) (*webhookv1.CreateIncomingWebhookResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hook, token, err := w.incomingService.CreateIncoming(ctx, owner.UserID, req.ChatId, domain.Login(req.Name), int(req.RateLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.CreateIncomingWebhookResponse{
+		IncomingWebhook: toProtoIncoming(hook),
+		Token:           token,
+	}, nil
+}
+
+func (w *WebhookV1) GetIncomingWebhooks(ctx context.Context, req *webhookv1.GetIncomingWebhooksRequest) (*webhookv1.GetIncomingWebhooksResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := w.incomingService.GetIncoming(ctx, owner.UserID, req.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*webhookv1.IncomingWebhook, 0, len(hooks))
+	for _, hook := range hooks {
+		res = append(res, toProtoIncoming(hook))
+	}
+
+	return &webhookv1.GetIncomingWebhooksResponse{
+		IncomingWebhooks: res,
+	}, nil
+}
+
+func (w *WebhookV1) RevokeIncomingWebhook(ctx context.Context, req *webhookv1.RevokeIncomingWebhookRequest) (*webhookv1.RevokeIncomingWebhookResponse, error) {
+	owner, err := principal.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.incomingService.RevokeIncoming(ctx, owner.UserID, req.ChatId, req.IncomingWebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.RevokeIncomingWebhookResponse{}, nil
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"github.com/monobearotaku/online-chat-api/internal/service/incoming"
+	"github.com/monobearotaku/online-chat-api/internal/service/webhook"
+	webhookv1 "github.com/monobearotaku/online-chat-api/proto/webhook/v1"
+	"google.golang.org/grpc"
+)
+
+type WebhookV1 struct {
+	webhookv1.UnimplementedWebhookServiceServer
+	webhookService  webhook.Service
+	incomingService incoming.Service
+}
+
+func NewWebhookV1(dialer grpc.ServiceRegistrar, webhookService webhook.Service, incomingService incoming.Service) *WebhookV1 {
+	server := WebhookV1{
+		webhookService:  webhookService,
+		incomingService: incomingService,
+	}
+
+	webhookv1.RegisterWebhookServiceServer(dialer, &server)
+
+	return &server
+}
@@ -0,0 +1,87 @@
+package incoming
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+	"github.com/monobearotaku/online-chat-api/internal/ports/http/respond"
+	incomingService "github.com/monobearotaku/online-chat-api/internal/service/incoming"
+)
+
+const (
+	PathPrefix = "/hooks/incoming/"
+
+	maxBodyBytes = 64 << 10
+)
+
+type payload struct {
+	Text string `json:"text"`
+}
+
+type Handler struct {
+	incomingService incomingService.Service
+	logger          log.Logger
+}
+
+// NewHandler serves POST /hooks/incoming/<id>; the token is sent as a bearer token or, for
+// integrations that can only be given a URL, as a path segment: /hooks/incoming/<id>/<token>.
+// Query strings end up in access logs and proxies, so a token there is not accepted.
+func NewHandler(mux *http.ServeMux, incomingService incomingService.Service, logger log.Logger) *Handler {
+	handler := Handler{
+		incomingService: incomingService,
+		logger:          logger,
+	}
+
+	mux.HandleFunc(PathPrefix, handler.post)
+
+	return &handler
+}
+
+func (h *Handler) post(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, token, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+
+	hookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		respond.Error(w, r, h.logger, incoming.ErrNotFound)
+		return
+	}
+
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = bearer
+	}
+
+	if token == "" {
+		respond.Error(w, r, h.logger, incoming.ErrInvalidToken)
+		return
+	}
+
+	body := payload{}
+
+	err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&body)
+	if err != nil {
+		respond.Error(w, r, h.logger, incoming.ErrInvalidPayload)
+		return
+	}
+
+	msg, err := h.incomingService.Post(r.Context(), hookID, token, body.Text)
+	if err != nil {
+		respond.Error(w, r, h.logger, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]any{
+		"messageId": msg.ID,
+		"chatId":    msg.ChatID,
+		"createdAt": msg.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
@@ -0,0 +1,82 @@
+package incoming
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+	incomingService "github.com/monobearotaku/online-chat-api/internal/service/incoming"
+	"github.com/stretchr/testify/assert"
+)
+
+type poster struct {
+	incomingService.Service
+}
+
+func (p *poster) Post(ctx context.Context, hookID int64, token, text string) (chat.Message, error) {
+	switch {
+	case token != "secret":
+		return chat.Message{}, incoming.ErrInvalidToken
+	case text == "flood":
+		return chat.Message{}, incoming.ErrRateLimited.WithRetryAfter(1500 * time.Millisecond)
+	case text == "":
+		return chat.Message{}, chat.ErrEmptyMessage
+	}
+
+	return chat.Message{ID: 7, ChatID: 3, Msg: text, CreatedAt: time.Now()}, nil
+}
+
+func Test_Handler_Post(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	NewHandler(mux, &poster{}, log.NewNopLogger())
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		auth       string
+		body       string
+		status     int
+		reason     string
+		retryAfter string
+	}{
+		{name: "bearer token", method: http.MethodPost, target: "/hooks/incoming/1", auth: "Bearer secret", body: `{"text":"deploy finished"}`, status: http.StatusOK},
+		{name: "path token", method: http.MethodPost, target: "/hooks/incoming/1/secret", body: `{"text":"deploy finished"}`, status: http.StatusOK},
+		{name: "query token is not accepted", method: http.MethodPost, target: "/hooks/incoming/1?token=secret", body: `{"text":"deploy finished"}`, status: http.StatusUnauthorized, reason: "INVALID_INCOMING_WEBHOOK_TOKEN"},
+		{name: "wrong method", method: http.MethodGet, target: "/hooks/incoming/1/secret", status: http.StatusMethodNotAllowed},
+		{name: "bad id", method: http.MethodPost, target: "/hooks/incoming/abc/secret", body: `{"text":"hi"}`, status: http.StatusNotFound, reason: "INCOMING_WEBHOOK_NOT_FOUND"},
+		{name: "missing token", method: http.MethodPost, target: "/hooks/incoming/1", body: `{"text":"hi"}`, status: http.StatusUnauthorized, reason: "INVALID_INCOMING_WEBHOOK_TOKEN"},
+		{name: "wrong token", method: http.MethodPost, target: "/hooks/incoming/1", auth: "Bearer other", body: `{"text":"hi"}`, status: http.StatusUnauthorized, reason: "INVALID_INCOMING_WEBHOOK_TOKEN"},
+		{name: "malformed body", method: http.MethodPost, target: "/hooks/incoming/1/secret", body: `text`, status: http.StatusBadRequest, reason: "INVALID_INCOMING_PAYLOAD"},
+		{name: "empty text", method: http.MethodPost, target: "/hooks/incoming/1/secret", body: `{}`, status: http.StatusBadRequest, reason: "EMPTY_MESSAGE"},
+		{name: "rate limited", method: http.MethodPost, target: "/hooks/incoming/1/secret", body: `{"text":"flood"}`, status: http.StatusTooManyRequests, reason: "INCOMING_WEBHOOK_RATE_LIMITED", retryAfter: "2"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.reason)
+			assert.Equal(t, tt.retryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/domain/identity"
+	"github.com/monobearotaku/online-chat-api/internal/pkg/oidc"
+	"github.com/monobearotaku/online-chat-api/internal/ports/http/respond"
+	"github.com/monobearotaku/online-chat-api/internal/service/auth"
+)
+
+const (
+	LoginPath    = "/auth/oidc/login"
+	CallbackPath = "/auth/oidc/callback"
+
+	flowCookie = "oidc_flow"
+	flowTTL    = 10 * time.Minute
+
+	purposeSignIn = "signin"
+	purposeReauth = "reauth"
+)
+
+type Handler struct {
+	name         string
+	provider     *oidc.Provider
+	authService  auth.Service
+	secureCookie bool
+	logger       log.Logger
+}
+
+// NewHandler registers the authorization code flow for the provider; name identifies it in linked identities.
+// A flow started with ?reauth=1 returns a reauthentication token for an already linked account instead of
+// signing in, which accounts without a password use to change credentials or delete themselves.
+func NewHandler(mux *http.ServeMux, name string, provider *oidc.Provider, authService auth.Service, secureCookie bool, logger log.Logger) *Handler {
+	handler := Handler{
+		name:         name,
+		provider:     provider,
+		authService:  authService,
+		secureCookie: secureCookie,
+		logger:       logger,
+	}
+
+	mux.HandleFunc(LoginPath, handler.login)
+	mux.HandleFunc(CallbackPath, handler.callback)
+
+	return &handler
+}
+
+func (h *Handler) login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	values := make([]string, 3)
+	for i := range values {
+		value, err := randomValue()
+		if err != nil {
+			h.writeError(w, r, fmt.Errorf("generating flow values: %w", err))
+			return
+		}
+
+		values[i] = value
+	}
+
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	purpose := purposeSignIn
+	if r.URL.Query().Get("reauth") != "" {
+		purpose = purposeReauth
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     flowCookie,
+		Value:    strings.Join([]string{state, nonce, verifier, purpose}, "."),
+		Path:     "/auth/oidc",
+		MaxAge:   int(flowTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, h.provider.AuthCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+func (h *Handler) callback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     flowCookie,
+		Path:     "/auth/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	cookie, err := r.Cookie(flowCookie)
+	if err != nil {
+		h.writeError(w, r, identity.ErrInvalidState)
+		return
+	}
+
+	parts := strings.Split(cookie.Value, ".")
+	query := r.URL.Query()
+
+	if len(parts) != 4 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(query.Get("state"))) != 1 {
+		h.writeError(w, r, identity.ErrInvalidState)
+		return
+	}
+
+	if query.Get("error") != "" || query.Get("code") == "" {
+		h.writeError(w, r, identity.ErrLoginFailed)
+		return
+	}
+
+	claims, err := h.provider.Exchange(r.Context(), query.Get("code"), parts[2], parts[1])
+	if err != nil {
+		level.Warn(h.logger).Log("error", fmt.Errorf("oidc exchange with %s failed: %v", h.name, err))
+		h.writeError(w, r, identity.ErrLoginFailed)
+		return
+	}
+
+	external := identity.External{
+		Provider:          h.name,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		PreferredUsername: claims.PreferredUsername,
+	}
+
+	if parts[3] == purposeReauth {
+		reauth, err := h.authService.ReauthenticateExternal(r.Context(), external)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+
+		respond.JSON(w, http.StatusOK, map[string]string{
+			"reauthToken": reauth.String(),
+		})
+
+		return
+	}
+
+	signIn, err := h.authService.SignInExternal(r.Context(), external)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+
+	// Accounts with a second factor get a challenge token to exchange through VerifyTotp, as in SignIn.
+	if signIn.Challenge != "" {
+		respond.JSON(w, http.StatusOK, map[string]string{
+			"challengeToken": signIn.Challenge.String(),
+		})
+
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, map[string]string{
+		"token": signIn.Token.String(),
+	})
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	respond.Error(w, r, h.logger, err)
+}
+
+func randomValue() (string, error) {
+	raw := make([]byte, 32)
+
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,215 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/monobearotaku/online-chat-api/internal/domain/identity"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/pkg/oidc"
+	"github.com/monobearotaku/online-chat-api/internal/pkg/oidc/oidctest"
+	"github.com/monobearotaku/online-chat-api/internal/service/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type externalSignIn struct {
+	auth.Service
+
+	mu       sync.Mutex
+	external []identity.External
+}
+
+// SignInExternal treats subjects starting with totp- as accounts with a second factor.
+func (e *externalSignIn) SignInExternal(ctx context.Context, external identity.External) (token.SignIn, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.external = append(e.external, external)
+
+	if strings.HasPrefix(external.Subject, "totp-") {
+		return token.SignIn{Challenge: token.Token("challenge-for-" + external.Subject)}, nil
+	}
+
+	return token.SignIn{Token: token.Token("token-for-" + external.Subject)}, nil
+}
+
+func (e *externalSignIn) ReauthenticateExternal(ctx context.Context, external identity.External) (token.Token, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.external = append(e.external, external)
+
+	return token.Token("reauth-for-" + external.Subject), nil
+}
+
+func setup(t *testing.T) (*oidctest.Provider, *httptest.Server, *externalSignIn) {
+	t.Helper()
+
+	provider, err := oidctest.NewProvider()
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+
+	mux := http.NewServeMux()
+	app := httptest.NewServer(mux)
+	t.Cleanup(app.Close)
+
+	discovered, err := oidc.Discover(context.Background(), provider.Client(), oidc.Config{
+		Issuer:       provider.Issuer(),
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		RedirectURL:  app.URL + CallbackPath,
+	})
+	require.NoError(t, err)
+
+	service := &externalSignIn{}
+	NewHandler(mux, "mock", discovered, service, false, log.NewNopLogger())
+
+	return provider, app, service
+}
+
+func browser(t *testing.T) *http.Client {
+	t.Helper()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	return &http.Client{Jar: jar}
+}
+
+func Test_Handler_LoginFlow(t *testing.T) {
+	t.Parallel()
+
+	provider, app, service := setup(t)
+
+	provider.SetUser(oidc.Claims{
+		Subject:           "subject-1",
+		Email:             "jane.doe@example.com",
+		PreferredUsername: "jane.doe",
+	})
+
+	resp, err := browser(t).Get(app.URL + LoginPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := map[string]string{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "token-for-subject-1", body["token"])
+	assert.Equal(t, []identity.External{{
+		Provider:          "mock",
+		Subject:           "subject-1",
+		Email:             "jane.doe@example.com",
+		PreferredUsername: "jane.doe",
+	}}, service.external)
+}
+
+func Test_Handler_LoginFlowSecondFactor(t *testing.T) {
+	t.Parallel()
+
+	provider, app, _ := setup(t)
+
+	provider.SetUser(oidc.Claims{
+		Subject: "totp-subject",
+		Email:   "john.roe@example.com",
+	})
+
+	resp, err := browser(t).Get(app.URL + LoginPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := map[string]string{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, map[string]string{"challengeToken": "challenge-for-totp-subject"}, body)
+}
+
+func Test_Handler_ReauthFlow(t *testing.T) {
+	t.Parallel()
+
+	provider, app, _ := setup(t)
+
+	provider.SetUser(oidc.Claims{
+		Subject: "subject-2",
+		Email:   "jane.doe@example.com",
+	})
+
+	resp, err := browser(t).Get(app.URL + LoginPath + "?reauth=1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := map[string]string{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, map[string]string{"reauthToken": "reauth-for-subject-2"}, body)
+}
+
+func Test_Handler_CallbackRejected(t *testing.T) {
+	t.Parallel()
+
+	_, app, service := setup(t)
+
+	t.Cleanup(func() {
+		assert.Empty(t, service.external)
+	})
+
+	tests := []struct {
+		name   string
+		query  url.Values
+		login  bool
+		reason string
+	}{
+		{
+			name:   "Without Flow Cookie",
+			query:  url.Values{"code": {"code"}, "state": {"state"}},
+			reason: identity.ErrInvalidState.Reason(),
+		},
+		{
+			name:   "State Mismatch",
+			query:  url.Values{"code": {"code"}, "state": {"forged"}},
+			login:  true,
+			reason: identity.ErrInvalidState.Reason(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := browser(t)
+
+			if tt.login {
+				client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				}
+
+				resp, err := client.Get(app.URL + LoginPath)
+				require.NoError(t, err)
+				resp.Body.Close()
+			}
+
+			resp, err := client.Get(app.URL + CallbackPath + "?" + tt.query.Encode())
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body := map[string]string{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			assert.Equal(t, tt.reason, body["reason"])
+		})
+	}
+}
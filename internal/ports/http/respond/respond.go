@@ -0,0 +1,77 @@
+package respond
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+)
+
+func JSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Error writes domain errors as {reason, message}; anything else is logged and hidden behind a correlation id.
+func Error(w http.ResponseWriter, r *http.Request, logger log.Logger, err error) {
+	if domainErr, ok := domain.AsError(err); ok {
+		if domainErr.RetryAfter() > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(domainErr.RetryAfter().Seconds()))))
+		}
+
+		body := map[string]string{
+			"reason":  domainErr.Reason(),
+			"message": domainErr.Error(),
+		}
+
+		if domainErr.Field() != "" {
+			body["field"] = domainErr.Field()
+		}
+
+		JSON(w, StatusFromKind(domainErr.Kind()), body)
+
+		return
+	}
+
+	correlationID := uuid.NewString()
+
+	level.Error(logger).Log(
+		"error", err,
+		"http.path", r.URL.Path,
+		"correlationID", correlationID,
+	)
+
+	JSON(w, http.StatusInternalServerError, map[string]string{
+		"reason":  "INTERNAL",
+		"message": fmt.Sprintf("internal error, correlation id: %s", correlationID),
+	})
+}
+
+func StatusFromKind(kind domain.Kind) int {
+	switch kind {
+	case domain.KindNotFound:
+		return http.StatusNotFound
+	case domain.KindAlreadyExists:
+		return http.StatusConflict
+	case domain.KindPermissionDenied:
+		return http.StatusForbidden
+	case domain.KindUnauthenticated:
+		return http.StatusUnauthorized
+	case domain.KindInvalidArgument:
+		return http.StatusBadRequest
+	case domain.KindResourceExhausted:
+		return http.StatusTooManyRequests
+	case domain.KindFailedPrecondition:
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/monobearotaku/online-chat-api/internal/config"
 	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
 	"github.com/monobearotaku/online-chat-api/internal/pkg/slices"
 	"github.com/monobearotaku/online-chat-api/internal/service/chat"
 	"github.com/segmentio/kafka-go"
@@ -43,16 +44,35 @@ func (c *Consumer) Consume(ctx context.Context) {
 			continue
 		}
 
-		chatMessage := chatDomain.Message{}
-
-		err = json.Unmarshal(msg.Value, &chatMessage)
+		evt, err := DecodeEvent(msg.Value)
 		if err != nil {
 			level.Error(c.logger).Log("error", fmt.Errorf("error unmarshaling message:%w", err))
 			continue
 		}
 
-		go func(key string, msg chatDomain.Message) {
-			c.chatService.SendMessage(ctx, key, msg)
-		}(string(msg.Key), chatMessage)
+		c.chatService.SendEvent(ctx, string(msg.Key), evt)
+	}
+}
+
+// DecodeEvent reads an event envelope, accepting bare chat messages published before events existed.
+func DecodeEvent(value []byte) (event.Event, error) {
+	evt := event.Event{}
+
+	err := json.Unmarshal(value, &evt)
+	if err != nil {
+		return event.Event{}, err
 	}
+
+	if evt.Type != "" {
+		return evt, nil
+	}
+
+	chatMessage := chatDomain.Message{}
+
+	err = json.Unmarshal(value, &chatMessage)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	return event.NewMessageEvent(event.MessageCreated, chatMessage), nil
 }
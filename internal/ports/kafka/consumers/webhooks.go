@@ -0,0 +1,78 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/config"
+	"github.com/monobearotaku/online-chat-api/internal/pkg/slices"
+	"github.com/monobearotaku/online-chat-api/internal/service/webhook"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	webhooksGroupID    = "chat-webhooks"
+	enqueueRetryPeriod = time.Second
+)
+
+// WebhookConsumer turns chat events into webhook deliveries. Unlike Consumer it shares one
+// consumer group across replicas, so every event is enqueued once.
+type WebhookConsumer struct {
+	r              *kafka.Reader
+	webhookService webhook.Service
+	logger         log.Logger
+}
+
+func NewWebhookConsumer(config config.Config, webhookService webhook.Service, logger log.Logger) *WebhookConsumer {
+	return &WebhookConsumer{
+		r: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: slices.FromElenent(config.Kafka.Broker),
+			Topic:   config.Kafka.Topic,
+			GroupID: webhooksGroupID,
+		}),
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+func (c *WebhookConsumer) Consume(ctx context.Context) {
+	for {
+		msg, err := c.r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			level.Error(c.logger).Log("error", fmt.Errorf("error fetching webhook event:%w", err))
+			continue
+		}
+
+		evt, err := DecodeEvent(msg.Value)
+		if err != nil {
+			level.Error(c.logger).Log("error", fmt.Errorf("error unmarshaling webhook event:%w", err))
+		} else {
+			// Keep retrying so a database hiccup does not drop events; the offset is committed only after enqueueing.
+			for {
+				err = c.webhookService.Enqueue(ctx, evt)
+				if err == nil || ctx.Err() != nil {
+					break
+				}
+
+				level.Error(c.logger).Log("error", fmt.Errorf("error enqueueing webhook event %s:%w", evt.ID, err))
+
+				select {
+				case <-ctx.Done():
+				case <-time.After(enqueueRetryPeriod):
+				}
+			}
+		}
+
+		err = c.r.CommitMessages(ctx, msg)
+		if err != nil && ctx.Err() == nil {
+			level.Error(c.logger).Log("error", fmt.Errorf("error committing webhook event:%w", err))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/service/dataexport"
+)
+
+const dataExportPollInterval = 10 * time.Second
+
+// DataExporter builds queued user data archives and deletes expired ones.
+type DataExporter struct {
+	dataExportService dataexport.Service
+	logger            log.Logger
+}
+
+func NewDataExporter(dataExportService dataexport.Service, logger log.Logger) *DataExporter {
+	return &DataExporter{
+		dataExportService: dataExportService,
+		logger:            logger,
+	}
+}
+
+func (d *DataExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(dataExportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// A failed job is marked as such, so keep going with the rest of the queue.
+		for {
+			processed, err := d.dataExportService.ProcessNext(ctx)
+			if err != nil {
+				level.Error(d.logger).Log("error", fmt.Errorf("error building data export:%w", err))
+			}
+
+			if !processed || ctx.Err() != nil {
+				break
+			}
+		}
+
+		for {
+			purged, err := d.dataExportService.PurgeExpired(ctx)
+			if err != nil {
+				level.Error(d.logger).Log("error", fmt.Errorf("error purging data exports:%w", err))
+				break
+			}
+
+			if purged == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/service/chat"
+)
+
+const reaperPollInterval = time.Second
+
+// MessageReaper periodically deletes expired disappearing messages; replicas skip rows another one is deleting.
+type MessageReaper struct {
+	chatService chat.Service
+	logger      log.Logger
+}
+
+func NewMessageReaper(chatService chat.Service, logger log.Logger) *MessageReaper {
+	return &MessageReaper{
+		chatService: chatService,
+		logger:      logger,
+	}
+}
+
+func (r *MessageReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(reaperPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Drain the backlog before waiting for the next tick.
+		for {
+			deleted, err := r.chatService.DeleteExpiredMessages(ctx)
+			if err != nil {
+				level.Error(r.logger).Log("error", fmt.Errorf("error deleting expired messages:%w", err))
+				break
+			}
+
+			if deleted == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/service/chat"
+)
+
+const pollCloserInterval = time.Second
+
+// PollCloser periodically closes polls whose deadline passed so streams receive their final tally.
+type PollCloser struct {
+	chatService chat.Service
+	logger      log.Logger
+}
+
+func NewPollCloser(chatService chat.Service, logger log.Logger) *PollCloser {
+	return &PollCloser{
+		chatService: chatService,
+		logger:      logger,
+	}
+}
+
+func (p *PollCloser) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollCloserInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Drain the backlog before waiting for the next tick.
+		for {
+			closed, err := p.chatService.CloseDuePolls(ctx)
+			if err != nil {
+				level.Error(p.logger).Log("error", fmt.Errorf("error closing due polls:%w", err))
+				break
+			}
+
+			if closed == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
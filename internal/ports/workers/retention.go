@@ -0,0 +1,45 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/service/retention"
+)
+
+const retentionPollInterval = time.Minute
+
+// RetentionEnforcer periodically deletes messages outside their retention policy in small batches;
+// replicas skip rows another one is deleting.
+type RetentionEnforcer struct {
+	retentionService retention.Service
+	logger           log.Logger
+}
+
+func NewRetentionEnforcer(retentionService retention.Service, logger log.Logger) *RetentionEnforcer {
+	return &RetentionEnforcer{
+		retentionService: retentionService,
+		logger:           logger,
+	}
+}
+
+func (r *RetentionEnforcer) Run(ctx context.Context) {
+	ticker := time.NewTicker(retentionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := r.retentionService.Enforce(ctx)
+		if err != nil {
+			level.Error(r.logger).Log("error", fmt.Errorf("error enforcing retention:%w", err))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/service/chat"
+)
+
+const scheduledPollInterval = time.Second
+
+// ScheduledSender periodically posts due scheduled messages; replicas skip rows another one has claimed.
+type ScheduledSender struct {
+	chatService chat.Service
+	logger      log.Logger
+}
+
+func NewScheduledSender(chatService chat.Service, logger log.Logger) *ScheduledSender {
+	return &ScheduledSender{
+		chatService: chatService,
+		logger:      logger,
+	}
+}
+
+func (s *ScheduledSender) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduledPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Drain the backlog before waiting for the next tick.
+		for {
+			processed, err := s.chatService.SendDueScheduled(ctx)
+			if err != nil {
+				level.Error(s.logger).Log("error", fmt.Errorf("error sending scheduled messages:%w", err))
+				break
+			}
+
+			if processed == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
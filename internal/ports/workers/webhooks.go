@@ -0,0 +1,52 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/service/webhook"
+)
+
+const webhookPollInterval = time.Second
+
+// WebhookDispatcher periodically sends due webhook deliveries; replicas coordinate through row leases.
+type WebhookDispatcher struct {
+	webhookService webhook.Service
+	logger         log.Logger
+}
+
+func NewWebhookDispatcher(webhookService webhook.Service, logger log.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Drain the backlog before waiting for the next tick.
+		for {
+			sent, err := d.webhookService.DeliverDue(ctx)
+			if err != nil {
+				level.Error(d.logger).Log("error", fmt.Errorf("error delivering webhooks:%w", err))
+				break
+			}
+
+			if sent == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
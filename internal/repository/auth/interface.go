@@ -14,4 +14,9 @@ type Repo interface {
 	CreateUser(ctx context.Context, cred credentials.Credentials) error
 	GetUser(ctx context.Context, login domain.Login) (user.User, error)
 	GetUserById(ctx context.Context, id int64) (user.User, error)
+	// LockUser holds the user's row until the transaction ends, so checks of per-user limits do not race.
+	LockUser(ctx context.Context, id int64) error
+	UpdatePassword(ctx context.Context, id int64, password domain.Password) error
+	RehashPassword(ctx context.Context, id int64, oldHash, newHash domain.Password) error
+	DeleteUser(ctx context.Context, id int64) error
 }
@@ -51,14 +51,16 @@ func (a *authRepo) GetUser(ctx context.Context, login domain.Login) (user.User,
 		SELECT 
 			id,
 			login,
-			password
+			password,
+			token_version,
+			is_bot
 		FROM users 
-		WHERE login = $1
+		WHERE login = $1 AND deleted_at IS NULL
 	`
 
 	usr := user.User{}
 
-	err := a.db.QueryRow(ctx, query, login).Scan(&usr.ID, &usr.Login, &usr.PasswordHash)
+	err := a.db.QueryRow(ctx, query, login).Scan(&usr.ID, &usr.Login, &usr.PasswordHash, &usr.TokenVersion, &usr.IsBot)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return user.User{}, domain.ErrNotFound
@@ -75,14 +77,16 @@ func (a *authRepo) GetUserById(ctx context.Context, id int64) (user.User, error)
 		SELECT 
 			id,
 			login,
-			password
+			password,
+			token_version,
+			is_bot
 		FROM users 
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	usr := user.User{}
 
-	err := a.db.QueryRow(ctx, query, id).Scan(&usr.ID, &usr.Login, &usr.PasswordHash)
+	err := a.db.QueryRow(ctx, query, id).Scan(&usr.ID, &usr.Login, &usr.PasswordHash, &usr.TokenVersion, &usr.IsBot)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return user.User{}, domain.ErrNotFound
@@ -93,3 +97,138 @@ func (a *authRepo) GetUserById(ctx context.Context, id int64) (user.User, error)
 
 	return usr, nil
 }
+
+// LockUser takes a lock that does not block inserts referencing the user.
+func (a *authRepo) LockUser(ctx context.Context, id int64) error {
+	const query = `
+		SELECT id
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR NO KEY UPDATE
+	`
+
+	err := a.db.QueryRow(ctx, query, id).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (a *authRepo) UpdatePassword(ctx context.Context, id int64, password domain.Password) error {
+	const query = `
+		UPDATE users
+		SET
+			password = $2,
+			token_version = token_version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	res, err := a.db.Exec(ctx, query, id, password)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// RehashPassword swaps the stored hash without revoking tokens, unless the password was changed meanwhile.
+func (a *authRepo) RehashPassword(ctx context.Context, id int64, oldHash, newHash domain.Password) error {
+	const query = `
+		UPDATE users
+		SET password = $3
+		WHERE id = $1 AND password = $2 AND deleted_at IS NULL
+	`
+
+	_, err := a.db.Exec(ctx, query, id, oldHash, newHash)
+
+	return err
+}
+
+// DeleteUser anonymizes the account. The freed login is replaced with one longer than any valid login, so
+// no sign up, bot, provider or placeholder login can already hold it.
+func (a *authRepo) DeleteUser(ctx context.Context, id int64) error {
+	const query = `
+		UPDATE users
+		SET
+			login = 'deleted-user-account-' || id,
+			password = '',
+			display_name = '',
+			avatar_ref = '',
+			bio = '',
+			token_version = token_version + 1,
+			deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	// Bots outlive their owner only as chat history, so their keys stop working too.
+	const revokeKeys = `
+		UPDATE api_keys
+		SET revoked_at = now()
+		WHERE revoked_at IS NULL AND user_id IN (
+			SELECT id FROM users WHERE id = $1 OR owner_id = $1
+		)
+	`
+
+	const revokeIncoming = `
+		UPDATE incoming_webhooks
+		SET revoked_at = now()
+		WHERE revoked_at IS NULL AND created_by = $1
+	`
+
+	// Data export archives hold the user's data, so ready ones are handed to the purge worker at once
+	// and unfinished ones are stopped; a worker still building one then fails to complete it.
+	const stopExports = `
+		UPDATE data_exports
+		SET
+			status = 'failed',
+			error = 'The account was deleted',
+			completed_at = now()
+		WHERE user_id = $1 AND status IN ('pending', 'running')
+	`
+
+	const expireExports = `
+		UPDATE data_exports
+		SET expires_at = now()
+		WHERE user_id = $1 AND status = 'ready'
+	`
+
+	res, err := a.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	_, err = a.db.Exec(ctx, stopExports, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(ctx, expireExports, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(ctx, revokeKeys, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.db.Exec(ctx, revokeIncoming, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
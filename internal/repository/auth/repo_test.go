@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connect opens a connection to a migrated database named by TEST_POSTGRES_DSN; the test is skipped without one.
+func connect(t *testing.T) *pgx.Conn {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	conn, err := pgx.Connect(context.Background(), dsn)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close(context.Background())
+	})
+
+	return conn
+}
+
+func Test_AuthRepo_DeleteUser_LoginTaken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := connect(t)
+
+	var victimID, squatterID int64
+
+	login := fmt.Sprintf("du%d", time.Now().UnixNano()%1e12)
+
+	require.NoError(t, conn.QueryRow(ctx, `INSERT INTO users(login, password) VALUES ($1, '') RETURNING id`, login).Scan(&victimID))
+
+	// Another user signs up with the login an older anonymization gave the victim.
+	squatter := domain.Login(fmt.Sprintf("deleted-%d", victimID))
+	require.NoError(t, squatter.Validate())
+	require.NoError(t, conn.QueryRow(ctx, `INSERT INTO users(login, password) VALUES ($1, '') RETURNING id`, squatter).Scan(&squatterID))
+
+	t.Cleanup(func() {
+		_, _ = conn.Exec(ctx, `DELETE FROM users WHERE id = ANY($1)`, []int64{victimID, squatterID})
+	})
+
+	require.NoError(t, NewAuthRepo(conn).DeleteUser(ctx, victimID))
+
+	var anonymized domain.Login
+
+	require.NoError(t, conn.QueryRow(ctx, `SELECT login FROM users WHERE id = $1`, victimID).Scan(&anonymized))
+	assert.Error(t, anonymized.Validate(), "no valid login can take the anonymized one")
+}
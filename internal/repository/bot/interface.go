@@ -0,0 +1,25 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/bot"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	CreateBot(ctx context.Context, ownerID int64, login domain.Login) (bot.Bot, error)
+	GetBot(ctx context.Context, botID int64) (bot.Bot, error)
+	GetOwnedBots(ctx context.Context, ownerID int64) ([]bot.Bot, error)
+	// LockBot holds the bot's row until the transaction ends, so checks of per-bot limits do not race.
+	LockBot(ctx context.Context, botID int64) error
+	CreateKey(ctx context.Context, key apikey.APIKey) (apikey.APIKey, error)
+	CountActiveKeys(ctx context.Context, botID int64) (int, error)
+	GetKeyByPrefix(ctx context.Context, prefix string) (apikey.APIKey, error)
+	GetKeys(ctx context.Context, botID int64) ([]apikey.APIKey, error)
+	RevokeKey(ctx context.Context, botID int64, keyID int64) error
+	TouchKey(ctx context.Context, keyID int64) error
+}
@@ -0,0 +1,293 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/bot"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type botRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewBotRepo(db postgres.QueryExecer) Repo {
+	return &botRepo{
+		db: db,
+	}
+}
+
+func (b *botRepo) WithTx(tx postgres.Tx) Repo {
+	return &botRepo{
+		db: tx,
+	}
+}
+
+func (b *botRepo) CreateBot(ctx context.Context, ownerID int64, login domain.Login) (bot.Bot, error) {
+	const query = `
+		INSERT INTO users(login, password, is_bot, owner_id)
+		VALUES ($1, '', true, $2)
+		ON CONFLICT (login) DO NOTHING
+		RETURNING id
+	`
+
+	newBot := bot.Bot{
+		Login:   login,
+		OwnerID: ownerID,
+	}
+
+	err := b.db.QueryRow(ctx, query, login, ownerID).Scan(&newBot.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return bot.Bot{}, domain.ErrAlreadyExists
+		}
+
+		return bot.Bot{}, err
+	}
+
+	return newBot, nil
+}
+
+func (b *botRepo) GetBot(ctx context.Context, botID int64) (bot.Bot, error) {
+	const query = `
+		SELECT
+			id,
+			login,
+			owner_id
+		FROM users
+		WHERE id = $1 AND is_bot AND deleted_at IS NULL
+	`
+
+	found := bot.Bot{}
+
+	err := b.db.QueryRow(ctx, query, botID).Scan(&found.ID, &found.Login, &found.OwnerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return bot.Bot{}, bot.ErrNotFound
+		}
+
+		return bot.Bot{}, err
+	}
+
+	return found, nil
+}
+
+func (b *botRepo) GetOwnedBots(ctx context.Context, ownerID int64) ([]bot.Bot, error) {
+	const query = `
+		SELECT
+			id,
+			login,
+			owner_id
+		FROM users
+		WHERE owner_id = $1 AND is_bot AND deleted_at IS NULL
+		ORDER BY id
+	`
+
+	rows, err := b.db.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bots := make([]bot.Bot, 0)
+
+	for rows.Next() {
+		item := bot.Bot{}
+
+		err = rows.Scan(&item.ID, &item.Login, &item.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+
+		bots = append(bots, item)
+	}
+
+	return bots, rows.Err()
+}
+
+func (b *botRepo) LockBot(ctx context.Context, botID int64) error {
+	const query = `
+		SELECT id
+		FROM users
+		WHERE id = $1 AND is_bot AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var id int64
+
+	err := b.db.QueryRow(ctx, query, botID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return bot.ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (b *botRepo) CreateKey(ctx context.Context, key apikey.APIKey) (apikey.APIKey, error) {
+	const query = `
+		INSERT INTO api_keys(user_id, prefix, key_hash, name, scopes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	err := b.db.QueryRow(ctx, query, key.BotID, key.Prefix, key.Hash, key.Name, scopes).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return apikey.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (b *botRepo) CountActiveKeys(ctx context.Context, botID int64) (int, error) {
+	const query = `
+		SELECT count(*)
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	var count int
+
+	err := b.db.QueryRow(ctx, query, botID).Scan(&count)
+
+	return count, err
+}
+
+func (b *botRepo) GetKeyByPrefix(ctx context.Context, prefix string) (apikey.APIKey, error) {
+	const query = `
+		SELECT
+			id,
+			user_id,
+			prefix,
+			key_hash,
+			name,
+			scopes,
+			created_at,
+			last_used_at,
+			revoked_at
+		FROM api_keys
+		WHERE prefix = $1
+	`
+
+	key, err := scanKey(b.db.QueryRow(ctx, query, prefix))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apikey.APIKey{}, apikey.ErrNotFound
+		}
+
+		return apikey.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+func (b *botRepo) GetKeys(ctx context.Context, botID int64) ([]apikey.APIKey, error) {
+	const query = `
+		SELECT
+			id,
+			user_id,
+			prefix,
+			key_hash,
+			name,
+			scopes,
+			created_at,
+			last_used_at,
+			revoked_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := b.db.Query(ctx, query, botID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]apikey.APIKey, 0)
+
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (b *botRepo) RevokeKey(ctx context.Context, botID int64, keyID int64) error {
+	const query = `
+		UPDATE api_keys
+		SET revoked_at = coalesce(revoked_at, now())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	res, err := b.db.Exec(ctx, query, keyID, botID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return apikey.ErrNotFound
+	}
+
+	return nil
+}
+
+// TouchKey records key usage, at most once a minute to keep authentication off the write path.
+func (b *botRepo) TouchKey(ctx context.Context, keyID int64) error {
+	const query = `
+		UPDATE api_keys
+		SET last_used_at = now()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+	`
+
+	_, err := b.db.Exec(ctx, query, keyID)
+
+	return err
+}
+
+func scanKey(row pgx.Row) (apikey.APIKey, error) {
+	key := apikey.APIKey{}
+
+	var (
+		scopes                []string
+		lastUsedAt, revokedAt *time.Time
+	)
+
+	err := row.Scan(&key.ID, &key.BotID, &key.Prefix, &key.Hash, &key.Name, &scopes, &key.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return apikey.APIKey{}, err
+	}
+
+	key.Scopes = make([]apikey.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, apikey.Scope(scope))
+	}
+
+	if lastUsedAt != nil {
+		key.LastUsedAt = *lastUsedAt
+	}
+
+	if revokedAt != nil {
+		key.RevokedAt = *revokedAt
+	}
+
+	return key, nil
+}
@@ -2,17 +2,44 @@ package chat
 
 import (
 	"context"
+	"time"
 
 	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
 	"github.com/monobearotaku/online-chat-api/internal/postgres"
 )
 
 type Repo interface {
 	WithTx(tx postgres.Tx) Repo
 	GetById(ctx context.Context, chatID int64) (chat.Chat, error)
+	// LockChat holds the chat's row until the transaction ends, so checks of per-chat limits do not race.
+	LockChat(ctx context.Context, chatID int64) error
 	GetChatUsers(ctx context.Context, chatID int64) (chat.ChatUsers, error)
+	GetMembers(ctx context.Context, chatID int64) ([]chat.MemberInfo, error)
 	GetByName(ctx context.Context, name string) (chat.Chat, error)
 	CreateChat(ctx context.Context, chat chat.Chat) (chat.Chat, error)
 	AddUserToChat(ctx context.Context, chatID int64, userID int64, role chat.Role) error
-	SaveMessage(ctx context.Context, msg chat.Message) error
+	SaveMessage(ctx context.Context, msg chat.Message, ttl time.Duration) (chat.Message, error)
+	DeleteExpiredMessages(ctx context.Context, limit int) ([]chat.Message, error)
+	GetMessagesAfter(ctx context.Context, chatID int64, afterID int64, limit int) ([]chat.Message, error)
+	GetMessage(ctx context.Context, chatID int64, messageID int64) (chat.Message, error)
+	GetMessagesAround(ctx context.Context, chatID int64, messageID int64, around int) ([]chat.Message, error)
+	DeleteMessage(ctx context.Context, chatID int64, messageID int64) error
+	GetUserChats(ctx context.Context, userID int64) ([]chat.Membership, error)
+	SetUserRole(ctx context.Context, chatID int64, userID int64, role chat.Role) error
+	RemoveUserFromChat(ctx context.Context, chatID int64, userID int64) error
+	SetTopic(ctx context.Context, chatID int64, topic string) error
+	SetMessageTTL(ctx context.Context, chatID int64, ttl time.Duration) error
+	SetRetention(ctx context.Context, chatID int64, policy retention.Policy) error
+	// GetRetentionCutoffs returns the cutoff of every chat with a retention policy, applying global where unset.
+	GetRetentionCutoffs(ctx context.Context, global retention.Policy) ([]retention.Cutoff, error)
+	// ClaimRetained locks messages of a chat up to its cutoff until the transaction ends; it must run in one.
+	ClaimRetained(ctx context.Context, cutoff retention.Cutoff, limit int) ([]chat.Message, error)
+	DeleteMessages(ctx context.Context, messageIDs []int64) error
+	SetSlowMode(ctx context.Context, chatID int64, interval time.Duration) error
+	TouchLastMessage(ctx context.Context, chatID int64, userID int64, now time.Time) (time.Time, bool, error)
+	SetCanExport(ctx context.Context, chatID int64, userID int64, allowed bool) error
+	SetMutedUntil(ctx context.Context, chatID int64, userID int64, until time.Time) error
+	DeleteChat(ctx context.Context, chatID int64) error
+	DeleteUserMessages(ctx context.Context, userID int64) error
 }
@@ -3,12 +3,26 @@ package chat
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
 	"github.com/monobearotaku/online-chat-api/internal/postgres"
 )
 
+const messageColumns = `
+	m.id,
+	m.chat_id,
+	m.user_id,
+	m.message,
+	m.kind,
+	m.created_at,
+	m.expires_at,
+	u.login,
+	u.is_bot
+`
+
 type chatRepo struct {
 	db postgres.QueryExecer
 }
@@ -29,14 +43,31 @@ func (c *chatRepo) GetById(ctx context.Context, chatId int64) (chat.Chat, error)
 	const query = `
 		SELECT
 			id,
-			name
+			name,
+			topic,
+			slow_mode_seconds,
+			message_ttl_seconds,
+			retention_days,
+			retention_messages
 		FROM chats
 		WHERE id = $1
 	`
 
-	cht := chat.Chat{}
-
-	err := c.db.QueryRow(ctx, query, chatId).Scan(&cht.ID, &cht.Name)
+	var (
+		cht        = chat.Chat{}
+		slowMode   int
+		messageTTL int
+	)
+
+	err := c.db.QueryRow(ctx, query, chatId).Scan(
+		&cht.ID,
+		&cht.Name,
+		&cht.Topic,
+		&slowMode,
+		&messageTTL,
+		&cht.Retention.KeepDays,
+		&cht.Retention.KeepMessages,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return chat.Chat{}, chat.ErrChatNotFound
@@ -45,21 +76,64 @@ func (c *chatRepo) GetById(ctx context.Context, chatId int64) (chat.Chat, error)
 		return chat.Chat{}, err
 	}
 
+	cht.SlowMode = time.Duration(slowMode) * time.Second
+	cht.MessageTTL = time.Duration(messageTTL) * time.Second
+
 	return cht, nil
 }
 
+// LockChat takes a lock that does not block inserts referencing the chat, so messages keep flowing.
+func (c *chatRepo) LockChat(ctx context.Context, chatID int64) error {
+	const query = `
+		SELECT id
+		FROM chats
+		WHERE id = $1
+		FOR NO KEY UPDATE
+	`
+
+	var id int64
+
+	err := c.db.QueryRow(ctx, query, chatID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return chat.ErrChatNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 func (c *chatRepo) GetByName(ctx context.Context, name string) (chat.Chat, error) {
 	const query = `
 		SELECT
 			id,
-			name
+			name,
+			topic,
+			slow_mode_seconds,
+			message_ttl_seconds,
+			retention_days,
+			retention_messages
 		FROM chats
 		WHERE name = $1
 	`
 
-	cht := chat.Chat{}
-
-	err := c.db.QueryRow(ctx, query, name).Scan(&cht.ID, &cht.Name)
+	var (
+		cht        = chat.Chat{}
+		slowMode   int
+		messageTTL int
+	)
+
+	err := c.db.QueryRow(ctx, query, name).Scan(
+		&cht.ID,
+		&cht.Name,
+		&cht.Topic,
+		&slowMode,
+		&messageTTL,
+		&cht.Retention.KeepDays,
+		&cht.Retention.KeepMessages,
+	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return chat.Chat{}, chat.ErrChatNotFound
@@ -68,6 +142,9 @@ func (c *chatRepo) GetByName(ctx context.Context, name string) (chat.Chat, error
 		return chat.Chat{}, err
 	}
 
+	cht.SlowMode = time.Duration(slowMode) * time.Second
+	cht.MessageTTL = time.Duration(messageTTL) * time.Second
+
 	return cht, nil
 }
 
@@ -75,9 +152,12 @@ func (c *chatRepo) GetChatUsers(ctx context.Context, chatID int64) (chat.ChatUse
 	const query = `
 		SELECT 
 			user_id,
-			role
+			role,
+			muted_until,
+			can_export
 		FROM users_to_chats
 		WHERE chat_id = $1
+		ORDER BY created_at
 	`
 
 	rows, err := c.db.Query(ctx, query, chatID)
@@ -90,21 +170,77 @@ func (c *chatRepo) GetChatUsers(ctx context.Context, chatID int64) (chat.ChatUse
 	for rows.Next() {
 		var userID int64
 		var role string
+		var mutedUntil *time.Time
+		var canExport bool
 
-		err = rows.Scan(&userID, &role)
+		err = rows.Scan(&userID, &role, &mutedUntil, &canExport)
 		if err != nil {
 			return chat.ChatUsers{}, err
 		}
 
-		users.Users = append(users.Users, chat.ChatUser{
-			UserID: userID,
-			Role:   chat.Role(role),
-		})
+		chatUser := chat.ChatUser{
+			UserID:    userID,
+			Role:      chat.Role(role),
+			CanExport: canExport,
+		}
+
+		if mutedUntil != nil {
+			chatUser.MutedUntil = *mutedUntil
+		}
+
+		users.Users = append(users.Users, chatUser)
 	}
 
 	return users, nil
 }
 
+func (c *chatRepo) GetMembers(ctx context.Context, chatID int64) ([]chat.MemberInfo, error) {
+	const query = `
+		SELECT
+			uc.user_id,
+			u.login,
+			u.is_bot,
+			uc.role,
+			uc.created_at
+		FROM users_to_chats uc
+		JOIN users u ON u.id = uc.user_id
+		WHERE uc.chat_id = $1
+		ORDER BY uc.created_at, uc.user_id
+	`
+
+	rows, err := c.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]chat.MemberInfo, 0)
+
+	for rows.Next() {
+		member := chat.MemberInfo{}
+
+		var (
+			role     string
+			joinedAt *time.Time
+		)
+
+		err = rows.Scan(&member.UserID, &member.Login, &member.Bot, &role, &joinedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		member.Role = chat.Role(role)
+
+		if joinedAt != nil {
+			member.JoinedAt = *joinedAt
+		}
+
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
 func (c *chatRepo) CreateChat(ctx context.Context, cht chat.Chat) (chat.Chat, error) {
 	const query = `
 		INSERT INTO chats(name)
@@ -140,16 +276,579 @@ func (c *chatRepo) AddUserToChat(ctx context.Context, chatID int64, userID int64
 	return nil
 }
 
-func (c *chatRepo) SaveMessage(ctx context.Context, msg chat.Message) error {
+// SaveMessage stores a message that expires after ttl or the chat's message TTL, whichever is shorter;
+// zero for both keeps it.
+func (c *chatRepo) SaveMessage(ctx context.Context, msg chat.Message, ttl time.Duration) (chat.Message, error) {
 	const query = `
-		INSERT INTO messages(chat_id, user_id, message)
-		VALUES ($1, $2, $3)
+		INSERT INTO messages(chat_id, user_id, message, kind, expires_at)
+		SELECT $1, $2, $3, $4, LEAST(
+			now() + make_interval(secs => NULLIF($5, 0)),
+			now() + make_interval(secs => NULLIF(c.message_ttl_seconds, 0))
+		)
+		FROM chats c
+		WHERE c.id = $1
+		RETURNING id, created_at, expires_at
+	`
+
+	if msg.Kind == "" {
+		msg.Kind = chat.KindText
+	}
+
+	var expiresAt *time.Time
+
+	err := c.db.QueryRow(ctx, query, msg.ChatID, msg.UserID, msg.Msg, string(msg.Kind), int(ttl/time.Second)).Scan(&msg.ID, &msg.CreatedAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return chat.Message{}, chat.ErrChatNotFound
+		}
+
+		return chat.Message{}, err
+	}
+
+	if expiresAt != nil {
+		msg.ExpiresAt = *expiresAt
+	}
+
+	return msg, nil
+}
+
+// DeleteExpiredMessages removes up to limit expired messages and returns them; rows another replica
+// is deleting are skipped.
+func (c *chatRepo) DeleteExpiredMessages(ctx context.Context, limit int) ([]chat.Message, error) {
+	const query = `
+		DELETE FROM messages
+		WHERE id IN (
+			SELECT id
+			FROM messages
+			WHERE expires_at <= now()
+			ORDER BY expires_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, chat_id, user_id, expires_at
+	`
+
+	rows, err := c.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]chat.Message, 0)
+
+	for rows.Next() {
+		msg := chat.Message{}
+
+		err = rows.Scan(&msg.ID, &msg.ChatID, &msg.UserID, &msg.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+// GetMessagesAfter pages through a chat's history oldest first, starting after afterID.
+func (c *chatRepo) GetMessagesAfter(ctx context.Context, chatID int64, afterID int64, limit int) ([]chat.Message, error) {
+	const query = `SELECT ` + messageColumns + `
+		FROM messages m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.chat_id = $1 AND m.id > $2
+		ORDER BY m.id
+		LIMIT $3
+	`
+
+	rows, err := c.db.Query(ctx, query, chatID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]chat.Message, 0)
+
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+func (c *chatRepo) GetMessage(ctx context.Context, chatID int64, messageID int64) (chat.Message, error) {
+	const query = `SELECT ` + messageColumns + `
+		FROM messages m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.chat_id = $1 AND m.id = $2
+	`
+
+	msg, err := scanMessage(c.db.QueryRow(ctx, query, chatID, messageID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return chat.Message{}, chat.ErrMessageNotFound
+		}
+
+		return chat.Message{}, err
+	}
+
+	return msg, nil
+}
+
+// GetMessagesAround returns up to around messages on each side of messageID, oldest first.
+// The message itself is included if it still exists.
+func (c *chatRepo) GetMessagesAround(ctx context.Context, chatID int64, messageID int64, around int) ([]chat.Message, error) {
+	const query = `
+		SELECT * FROM (
+			(SELECT ` + messageColumns + `
+			FROM messages m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.chat_id = $1 AND m.id < $2
+			ORDER BY m.id DESC
+			LIMIT $3)
+			UNION ALL
+			(SELECT ` + messageColumns + `
+			FROM messages m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.chat_id = $1 AND m.id >= $2
+			ORDER BY m.id
+			LIMIT $3 + 1)
+		) around
+		ORDER BY id
+	`
+
+	rows, err := c.db.Query(ctx, query, chatID, messageID, around)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]chat.Message, 0)
+
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+func (c *chatRepo) DeleteMessage(ctx context.Context, chatID int64, messageID int64) error {
+	const query = `
+		DELETE FROM messages
+		WHERE chat_id = $1 AND id = $2
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+func (c *chatRepo) GetUserChats(ctx context.Context, userID int64) ([]chat.Membership, error) {
+	const query = `
+		SELECT
+			chat_id,
+			role
+		FROM users_to_chats
+		WHERE user_id = $1
+	`
+
+	rows, err := c.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	memberships := make([]chat.Membership, 0)
+
+	for rows.Next() {
+		var chatID int64
+		var role string
+
+		err = rows.Scan(&chatID, &role)
+		if err != nil {
+			return nil, err
+		}
+
+		memberships = append(memberships, chat.Membership{
+			ChatID: chatID,
+			Role:   chat.Role(role),
+		})
+	}
+
+	return memberships, rows.Err()
+}
+
+func (c *chatRepo) SetUserRole(ctx context.Context, chatID int64, userID int64, role chat.Role) error {
+	const query = `
+		UPDATE users_to_chats
+		SET role = $3
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	_, err := c.db.Exec(ctx, query, chatID, userID, role.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *chatRepo) RemoveUserFromChat(ctx context.Context, chatID int64, userID int64) error {
+	const query = `
+		DELETE FROM users_to_chats
+		WHERE chat_id = $1 AND user_id = $2
 	`
 
-	_, err := c.db.Exec(ctx, query, msg.ChatID, msg.UserID, msg.Msg)
+	_, err := c.db.Exec(ctx, query, chatID, userID)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (c *chatRepo) SetTopic(ctx context.Context, chatID int64, topic string) error {
+	const query = `
+		UPDATE chats
+		SET topic = $2
+		WHERE id = $1
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, topic)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrChatNotFound
+	}
+
+	return nil
+}
+
+func (c *chatRepo) SetMessageTTL(ctx context.Context, chatID int64, ttl time.Duration) error {
+	const query = `
+		UPDATE chats
+		SET message_ttl_seconds = $2
+		WHERE id = $1
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, int(ttl/time.Second))
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrChatNotFound
+	}
+
+	return nil
+}
+
+func (c *chatRepo) SetRetention(ctx context.Context, chatID int64, policy retention.Policy) error {
+	const query = `
+		UPDATE chats
+		SET
+			retention_days = $2,
+			retention_messages = $3
+		WHERE id = $1
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, policy.KeepDays, policy.KeepMessages)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrChatNotFound
+	}
+
+	return nil
+}
+
+// GetRetentionCutoffs computes every chat's cutoff once so a pass can delete in batches without rescanning
+// each chat for its N-th newest message. Chat policies combine with the global one as in retention.Policy.Or.
+func (c *chatRepo) GetRetentionCutoffs(ctx context.Context, global retention.Policy) ([]retention.Cutoff, error) {
+	const query = `
+		WITH policies AS (
+			SELECT
+				id,
+				LEAST(NULLIF(retention_days, 0), NULLIF($1::int, 0)) AS keep_days,
+				LEAST(NULLIF(retention_messages, 0), NULLIF($2::int, 0)) AS keep_messages
+			FROM chats
+		), cutoffs AS (
+			SELECT
+				p.id AS chat_id,
+				now() - make_interval(days => p.keep_days) AS cutoff_at,
+				CASE WHEN p.keep_messages IS NOT NULL THEN (
+					SELECT id
+					FROM messages
+					WHERE chat_id = p.id
+					ORDER BY id DESC
+					OFFSET p.keep_messages
+					LIMIT 1
+				) END AS cutoff_id
+			FROM policies p
+			WHERE p.keep_days IS NOT NULL OR p.keep_messages IS NOT NULL
+		)
+		SELECT chat_id, cutoff_at, COALESCE(cutoff_id, 0)
+		FROM cutoffs
+		WHERE cutoff_at IS NOT NULL OR cutoff_id IS NOT NULL
+		ORDER BY chat_id
+	`
+
+	rows, err := c.db.Query(ctx, query, global.KeepDays, global.KeepMessages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cutoffs := make([]retention.Cutoff, 0)
+
+	for rows.Next() {
+		var (
+			cutoff   retention.Cutoff
+			cutoffAt *time.Time
+		)
+
+		err = rows.Scan(&cutoff.ChatID, &cutoffAt, &cutoff.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if cutoffAt != nil {
+			cutoff.At = *cutoffAt
+		}
+
+		cutoffs = append(cutoffs, cutoff)
+	}
+
+	return cutoffs, rows.Err()
+}
+
+// ClaimRetained locks up to limit messages of the cutoff's chat that fall behind it, oldest first, until the
+// surrounding transaction ends; it must run in one.
+func (c *chatRepo) ClaimRetained(ctx context.Context, cutoff retention.Cutoff, limit int) ([]chat.Message, error) {
+	const query = `
+		SELECT ` + messageColumns + `
+		FROM messages m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.chat_id = $1 AND (m.created_at < $2 OR m.id <= $3)
+		ORDER BY m.id
+		LIMIT $4
+		FOR UPDATE OF m SKIP LOCKED
+	`
+
+	rows, err := c.db.Query(ctx, query, cutoff.ChatID, cutoff.At, cutoff.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]chat.Message, 0)
+
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
+
+func (c *chatRepo) DeleteMessages(ctx context.Context, messageIDs []int64) error {
+	const query = `
+		DELETE FROM messages
+		WHERE id = ANY($1)
+	`
+
+	_, err := c.db.Exec(ctx, query, messageIDs)
+
+	return err
+}
+
+func (c *chatRepo) SetSlowMode(ctx context.Context, chatID int64, interval time.Duration) error {
+	const query = `
+		UPDATE chats
+		SET slow_mode_seconds = $2
+		WHERE id = $1
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, int(interval/time.Second))
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrChatNotFound
+	}
+
+	return nil
+}
+
+// TouchLastMessage records a message from the member unless the chat's slow mode interval has not passed yet;
+// in that case it returns when the member may send again. The check and update are one statement so
+// concurrent replicas cannot both let a message through.
+func (c *chatRepo) TouchLastMessage(ctx context.Context, chatID int64, userID int64, now time.Time) (time.Time, bool, error) {
+	const touch = `
+		UPDATE users_to_chats u
+		SET last_message_at = $3
+		FROM chats c
+		WHERE c.id = u.chat_id AND u.chat_id = $1 AND u.user_id = $2
+			AND (
+				c.slow_mode_seconds = 0
+				OR u.last_message_at IS NULL
+				OR u.last_message_at <= $3 - make_interval(secs => c.slow_mode_seconds)
+			)
+	`
+
+	const next = `
+		SELECT u.last_message_at + make_interval(secs => c.slow_mode_seconds)
+		FROM users_to_chats u
+		JOIN chats c ON c.id = u.chat_id
+		WHERE u.chat_id = $1 AND u.user_id = $2
+	`
+
+	res, err := c.db.Exec(ctx, touch, chatID, userID, now)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if res.RowsAffected() > 0 {
+		return time.Time{}, true, nil
+	}
+
+	var nextAt *time.Time
+
+	err = c.db.QueryRow(ctx, next, chatID, userID).Scan(&nextAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, false, chat.ErrChatHaveNoUser
+		}
+
+		return time.Time{}, false, err
+	}
+
+	if nextAt == nil {
+		return time.Time{}, true, nil
+	}
+
+	return *nextAt, false, nil
+}
+
+// SetCanExport grants or revokes a member's permission to export the chat history.
+func (c *chatRepo) SetCanExport(ctx context.Context, chatID int64, userID int64, allowed bool) error {
+	const query = `
+		UPDATE users_to_chats
+		SET can_export = $3
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, userID, allowed)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrChatHaveNoUser
+	}
+
+	return nil
+}
+
+// SetMutedUntil mutes a member until the given time; the zero time unmutes.
+func (c *chatRepo) SetMutedUntil(ctx context.Context, chatID int64, userID int64, until time.Time) error {
+	const query = `
+		UPDATE users_to_chats
+		SET muted_until = $3
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	var mutedUntil *time.Time
+	if !until.IsZero() {
+		mutedUntil = &until
+	}
+
+	res, err := c.db.Exec(ctx, query, chatID, userID, mutedUntil)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return chat.ErrChatHaveNoUser
+	}
+
+	return nil
+}
+
+func (c *chatRepo) DeleteChat(ctx context.Context, chatID int64) error {
+	queries := []string{
+		`DELETE FROM messages WHERE chat_id = $1`,
+		`DELETE FROM users_to_chats WHERE chat_id = $1`,
+		`DELETE FROM chats WHERE id = $1`,
+	}
+
+	for _, query := range queries {
+		_, err := c.db.Exec(ctx, query, chatID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *chatRepo) DeleteUserMessages(ctx context.Context, userID int64) error {
+	const query = `
+		DELETE FROM messages
+		WHERE user_id = $1
+	`
+
+	_, err := c.db.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func scanMessage(row pgx.Row) (chat.Message, error) {
+	msg := chat.Message{}
+
+	var (
+		kind      string
+		expiresAt *time.Time
+	)
+
+	err := row.Scan(&msg.ID, &msg.ChatID, &msg.UserID, &msg.Msg, &kind, &msg.CreatedAt, &expiresAt, &msg.Login, &msg.Bot)
+	if err != nil {
+		return chat.Message{}, err
+	}
+
+	msg.Kind = chat.MessageKind(kind)
+
+	if expiresAt != nil {
+		msg.ExpiresAt = *expiresAt
+	}
+
+	return msg, nil
+}
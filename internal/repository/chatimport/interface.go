@@ -0,0 +1,22 @@
+package chatimport
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	GetChat(ctx context.Context, source, externalID string) (chatID int64, ok bool, err error)
+	LinkChat(ctx context.Context, source, externalID string, chatID int64) error
+	// GetUser returns the account an earlier run mapped the external user to; deleted accounts are not returned.
+	GetUser(ctx context.Context, source, externalID string) (userID int64, ok bool, err error)
+	LinkUser(ctx context.Context, source, externalID string, userID int64, placeholder bool) error
+	// CreatePlaceholder adds an account nobody can sign in to; ok is false when the login is taken.
+	CreatePlaceholder(ctx context.Context, login domain.Login, bot bool) (userID int64, ok bool, err error)
+	// SaveMessage keeps the message's own timestamp; ok is false when the external ID was imported before.
+	SaveMessage(ctx context.Context, externalID string, msg chat.Message) (ok bool, err error)
+}
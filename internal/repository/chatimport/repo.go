@@ -0,0 +1,144 @@
+package chatimport
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type chatImportRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewChatImportRepo(db postgres.QueryExecer) Repo {
+	return &chatImportRepo{
+		db: db,
+	}
+}
+
+func (c *chatImportRepo) WithTx(tx postgres.Tx) Repo {
+	return &chatImportRepo{
+		db: tx,
+	}
+}
+
+func (c *chatImportRepo) GetChat(ctx context.Context, source, externalID string) (int64, bool, error) {
+	const query = `
+		SELECT chat_id
+		FROM imported_chats
+		WHERE source = $1 AND external_id = $2
+	`
+
+	return c.getID(ctx, query, source, externalID)
+}
+
+func (c *chatImportRepo) LinkChat(ctx context.Context, source, externalID string, chatID int64) error {
+	const query = `
+		INSERT INTO imported_chats(source, external_id, chat_id)
+		VALUES ($1, $2, $3)
+	`
+
+	_, err := c.db.Exec(ctx, query, source, externalID, chatID)
+
+	return err
+}
+
+// GetUser ignores a mapping to an account deleted since, so the user is mapped again instead of adding the
+// deleted account to chats.
+func (c *chatImportRepo) GetUser(ctx context.Context, source, externalID string) (int64, bool, error) {
+	const query = `
+		SELECT i.user_id
+		FROM imported_users i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.source = $1 AND i.external_id = $2 AND u.deleted_at IS NULL
+	`
+
+	return c.getID(ctx, query, source, externalID)
+}
+
+func (c *chatImportRepo) LinkUser(ctx context.Context, source, externalID string, userID int64, placeholder bool) error {
+	const query = `
+		INSERT INTO imported_users(source, external_id, user_id, placeholder)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source, external_id) DO UPDATE
+		SET
+			user_id = EXCLUDED.user_id,
+			placeholder = EXCLUDED.placeholder
+	`
+
+	_, err := c.db.Exec(ctx, query, source, externalID, userID, placeholder)
+
+	return err
+}
+
+func (c *chatImportRepo) CreatePlaceholder(ctx context.Context, login domain.Login, bot bool) (int64, bool, error) {
+	const query = `
+		INSERT INTO users(login, password, is_bot)
+		VALUES ($1, '', $2)
+		ON CONFLICT (login) DO NOTHING
+		RETURNING id
+	`
+
+	var userID int64
+
+	err := c.db.QueryRow(ctx, query, login, bot).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
+
+// SaveMessage relies on the mapping's primary key: a concurrent run importing the same message fails
+// its transaction rather than storing the message twice.
+func (c *chatImportRepo) SaveMessage(ctx context.Context, externalID string, msg chat.Message) (bool, error) {
+	const query = `
+		WITH saved AS (
+			INSERT INTO messages(chat_id, user_id, message, kind, created_at)
+			SELECT $1, $3, $4, $5, $6
+			WHERE NOT EXISTS (
+				SELECT 1
+				FROM imported_messages
+				WHERE chat_id = $1 AND external_id = $2
+			)
+			RETURNING id
+		)
+		INSERT INTO imported_messages(chat_id, external_id, message_id)
+		SELECT $1, $2, id
+		FROM saved
+	`
+
+	if msg.Kind == "" {
+		msg.Kind = chat.KindText
+	}
+
+	res, err := c.db.Exec(ctx, query, msg.ChatID, externalID, msg.UserID, msg.Msg, string(msg.Kind), msg.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() == 1, nil
+}
+
+func (c *chatImportRepo) getID(ctx context.Context, query string, args ...any) (int64, bool, error) {
+	var id int64
+
+	err := c.db.QueryRow(ctx, query, args...).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
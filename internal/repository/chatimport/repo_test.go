@@ -0,0 +1,63 @@
+package chatimport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connect opens a connection to a migrated database named by TEST_POSTGRES_DSN; the test is skipped without one.
+func connect(t *testing.T) *pgx.Conn {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	conn, err := pgx.Connect(context.Background(), dsn)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close(context.Background())
+	})
+
+	return conn
+}
+
+func Test_ChatImportRepo_SaveMessage_SameTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conn := connect(t)
+
+	var userID, chatID int64
+
+	login := fmt.Sprintf("ci%d", time.Now().UnixNano()%1e12)
+
+	require.NoError(t, conn.QueryRow(ctx, `INSERT INTO users(login, password) VALUES ($1, '') RETURNING id`, login).Scan(&userID))
+	require.NoError(t, conn.QueryRow(ctx, `INSERT INTO chats(name) VALUES ($1) RETURNING id`, login).Scan(&chatID))
+
+	t.Cleanup(func() {
+		_, _ = conn.Exec(ctx, `DELETE FROM messages WHERE chat_id = $1`, chatID)
+		_, _ = conn.Exec(ctx, `DELETE FROM chats WHERE id = $1`, chatID)
+		_, _ = conn.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	// Imported history keeps its own timestamps, which repeat within an export and across exports.
+	createdAt := time.Unix(1712966400, 0).UTC()
+	repo := NewChatImportRepo(conn)
+
+	for _, externalID := range []string{"m1", "m2"} {
+		ok, err := repo.SaveMessage(ctx, externalID, chat.Message{ChatID: chatID, UserID: userID, Msg: externalID, CreatedAt: createdAt})
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,16 @@
+package command
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/command"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Register(ctx context.Context, registration command.Registration) (command.Registration, error)
+	Unregister(ctx context.Context, chatID int64, name string, botID int64) error
+	Get(ctx context.Context, chatID int64, name string) (command.Registration, error)
+	GetByChat(ctx context.Context, chatID int64) ([]command.Registration, error)
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/command"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type commandRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewCommandRepo(db postgres.QueryExecer) Repo {
+	return &commandRepo{
+		db: db,
+	}
+}
+
+func (c *commandRepo) WithTx(tx postgres.Tx) Repo {
+	return &commandRepo{
+		db: tx,
+	}
+}
+
+func (c *commandRepo) Register(ctx context.Context, registration command.Registration) (command.Registration, error) {
+	const query = `
+		INSERT INTO chat_commands(chat_id, name, description, bot_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chat_id, name) DO NOTHING
+		RETURNING created_at
+	`
+
+	err := c.db.QueryRow(ctx, query, registration.ChatID, registration.Name, registration.Description, registration.BotID).Scan(&registration.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return command.Registration{}, command.ErrAlreadyRegistered
+		}
+
+		return command.Registration{}, err
+	}
+
+	return registration, nil
+}
+
+func (c *commandRepo) Unregister(ctx context.Context, chatID int64, name string, botID int64) error {
+	const query = `
+		DELETE FROM chat_commands
+		WHERE chat_id = $1 AND name = $2 AND bot_id = $3
+	`
+
+	res, err := c.db.Exec(ctx, query, chatID, name, botID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return command.ErrNotFound
+	}
+
+	return nil
+}
+
+func (c *commandRepo) Get(ctx context.Context, chatID int64, name string) (command.Registration, error) {
+	const query = `
+		SELECT
+			c.chat_id,
+			c.name,
+			c.description,
+			c.bot_id,
+			u.login,
+			c.created_at
+		FROM chat_commands c
+		JOIN users u ON u.id = c.bot_id
+		WHERE c.chat_id = $1 AND c.name = $2 AND u.deleted_at IS NULL
+	`
+
+	rows, err := c.db.Query(ctx, query, chatID, name)
+	if err != nil {
+		return command.Registration{}, err
+	}
+
+	registrations, err := scanRegistrations(rows)
+	if err != nil {
+		return command.Registration{}, err
+	}
+
+	if len(registrations) == 0 {
+		return command.Registration{}, command.ErrNotFound
+	}
+
+	return registrations[0], nil
+}
+
+func (c *commandRepo) GetByChat(ctx context.Context, chatID int64) ([]command.Registration, error) {
+	const query = `
+		SELECT
+			c.chat_id,
+			c.name,
+			c.description,
+			c.bot_id,
+			u.login,
+			c.created_at
+		FROM chat_commands c
+		JOIN users u ON u.id = c.bot_id
+		WHERE c.chat_id = $1 AND u.deleted_at IS NULL
+		ORDER BY c.name
+	`
+
+	rows, err := c.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRegistrations(rows)
+}
+
+func scanRegistrations(rows pgx.Rows) ([]command.Registration, error) {
+	defer rows.Close()
+
+	registrations := make([]command.Registration, 0)
+
+	for rows.Next() {
+		registration := command.Registration{}
+
+		err := rows.Scan(
+			&registration.ChatID,
+			&registration.Name,
+			&registration.Description,
+			&registration.BotID,
+			&registration.BotLogin,
+			&registration.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		registrations = append(registrations, registration)
+	}
+
+	return registrations, rows.Err()
+}
@@ -0,0 +1,32 @@
+package dataexport
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	CreateJob(ctx context.Context, userID int64) (dataexport.Job, error)
+	GetJob(ctx context.Context, userID, jobID int64) (dataexport.Job, error)
+	// ClaimJob marks the oldest pending or stale running job as running; ok is false when there is none.
+	ClaimJob(ctx context.Context) (job dataexport.Job, ok bool, err error)
+	// CompleteJob and FailJob only change a job still held by the claim that returned it; ok is false
+	// when the job went stale and another worker claimed it again.
+	CompleteJob(ctx context.Context, job dataexport.Job, path string, size int64) (ok bool, err error)
+	FailJob(ctx context.Context, job dataexport.Job, reason string) (ok bool, err error)
+	// GetExpired returns up to limit ready jobs past their expiry, whose archives are still to be deleted.
+	GetExpired(ctx context.Context, limit int) ([]dataexport.Job, error)
+	ExpireJobs(ctx context.Context, jobIDs []int64) error
+
+	GetProfile(ctx context.Context, userID int64) (dataexport.Profile, error)
+	GetMemberships(ctx context.Context, userID int64) ([]dataexport.Membership, error)
+	GetMessagesAfter(ctx context.Context, userID, afterID int64, limit int) ([]dataexport.Message, error)
+	GetPollVotes(ctx context.Context, userID int64) ([]dataexport.PollVote, error)
+	GetReports(ctx context.Context, userID int64) ([]dataexport.Report, error)
+	GetScheduledMessages(ctx context.Context, userID int64) ([]dataexport.ScheduledMessage, error)
+	GetBans(ctx context.Context, userID int64) ([]dataexport.Ban, error)
+	GetSecurity(ctx context.Context, userID int64, lockoutKey string) (dataexport.Security, error)
+}
@@ -0,0 +1,482 @@
+package dataexport
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+const jobColumns = `
+	id,
+	user_id,
+	status,
+	error,
+	path,
+	size_bytes,
+	created_at,
+	started_at,
+	completed_at,
+	expires_at
+`
+
+type dataExportRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewDataExportRepo(db postgres.QueryExecer) Repo {
+	return &dataExportRepo{
+		db: db,
+	}
+}
+
+func (d *dataExportRepo) WithTx(tx postgres.Tx) Repo {
+	return &dataExportRepo{
+		db: tx,
+	}
+}
+
+func (d *dataExportRepo) CreateJob(ctx context.Context, userID int64) (dataexport.Job, error) {
+	const query = `
+		INSERT INTO data_exports(user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) WHERE status IN ('pending', 'running') DO NOTHING
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(d.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dataexport.Job{}, dataexport.ErrInProgress
+		}
+
+		return dataexport.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (d *dataExportRepo) GetJob(ctx context.Context, userID, jobID int64) (dataexport.Job, error) {
+	const query = `SELECT ` + jobColumns + `
+		FROM data_exports
+		WHERE id = $1 AND user_id = $2
+	`
+
+	job, err := scanJob(d.db.QueryRow(ctx, query, jobID, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dataexport.Job{}, dataexport.ErrNotFound
+		}
+
+		return dataexport.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (d *dataExportRepo) ClaimJob(ctx context.Context) (dataexport.Job, bool, error) {
+	const query = `
+		UPDATE data_exports
+		SET
+			status = 'running',
+			started_at = now()
+		WHERE id = (
+			SELECT id
+			FROM data_exports
+			WHERE status = 'pending' OR (status = 'running' AND started_at < now() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(d.db.QueryRow(ctx, query, dataexport.StaleAfter.Seconds()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dataexport.Job{}, false, nil
+		}
+
+		return dataexport.Job{}, false, err
+	}
+
+	return job, true, nil
+}
+
+func (d *dataExportRepo) CompleteJob(ctx context.Context, job dataexport.Job, path string, size int64) (bool, error) {
+	const query = `
+		UPDATE data_exports
+		SET
+			status = 'ready',
+			path = $3,
+			size_bytes = $4,
+			completed_at = now(),
+			expires_at = now() + make_interval(secs => $5)
+		WHERE id = $1 AND status = 'running' AND started_at = $2
+	`
+
+	res, err := d.db.Exec(ctx, query, job.ID, job.StartedAt, path, size, dataexport.ArchiveTTL.Seconds())
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func (d *dataExportRepo) FailJob(ctx context.Context, job dataexport.Job, reason string) (bool, error) {
+	const query = `
+		UPDATE data_exports
+		SET
+			status = 'failed',
+			error = $3,
+			completed_at = now()
+		WHERE id = $1 AND status = 'running' AND started_at = $2
+	`
+
+	res, err := d.db.Exec(ctx, query, job.ID, job.StartedAt, reason)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func (d *dataExportRepo) GetExpired(ctx context.Context, limit int) ([]dataexport.Job, error) {
+	const query = `SELECT ` + jobColumns + `
+		FROM data_exports
+		WHERE status = 'ready' AND expires_at <= now()
+		ORDER BY expires_at
+		LIMIT $1
+	`
+
+	rows, err := d.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.Job, error) {
+		return scanJob(row)
+	})
+}
+
+func (d *dataExportRepo) ExpireJobs(ctx context.Context, jobIDs []int64) error {
+	const query = `
+		UPDATE data_exports
+		SET status = 'expired'
+		WHERE id = ANY($1) AND status = 'ready'
+	`
+
+	_, err := d.db.Exec(ctx, query, jobIDs)
+
+	return err
+}
+
+func (d *dataExportRepo) GetProfile(ctx context.Context, userID int64) (dataexport.Profile, error) {
+	const query = `
+		SELECT
+			id,
+			login,
+			display_name,
+			avatar_ref,
+			bio,
+			is_bot,
+			created_at,
+			token_version
+		FROM users
+		WHERE id = $1
+	`
+
+	profile := dataexport.Profile{}
+
+	var createdAt *time.Time
+
+	err := d.db.QueryRow(ctx, query, userID).Scan(
+		&profile.UserID,
+		&profile.Login,
+		&profile.DisplayName,
+		&profile.AvatarRef,
+		&profile.Bio,
+		&profile.Bot,
+		&createdAt,
+		&profile.TokenVersion,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dataexport.Profile{}, domain.ErrNotFound
+		}
+
+		return dataexport.Profile{}, err
+	}
+
+	if createdAt != nil {
+		profile.CreatedAt = *createdAt
+	}
+
+	return profile, nil
+}
+
+func (d *dataExportRepo) GetMemberships(ctx context.Context, userID int64) ([]dataexport.Membership, error) {
+	const query = `
+		SELECT
+			uc.chat_id,
+			c.name,
+			uc.role,
+			uc.can_export,
+			uc.muted_until,
+			uc.created_at
+		FROM users_to_chats uc
+		JOIN chats c ON c.id = uc.chat_id
+		WHERE uc.user_id = $1
+		ORDER BY uc.created_at, uc.chat_id
+	`
+
+	rows, err := d.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.Membership, error) {
+		m := dataexport.Membership{}
+		err := row.Scan(&m.ChatID, &m.ChatName, &m.Role, &m.CanExport, &m.MutedUntil, &m.JoinedAt)
+
+		return m, err
+	})
+}
+
+func (d *dataExportRepo) GetMessagesAfter(ctx context.Context, userID, afterID int64, limit int) ([]dataexport.Message, error) {
+	const query = `
+		SELECT
+			id,
+			chat_id,
+			message,
+			kind,
+			created_at,
+			expires_at
+		FROM messages
+		WHERE user_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`
+
+	rows, err := d.db.Query(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.Message, error) {
+		m := dataexport.Message{}
+		err := row.Scan(&m.ID, &m.ChatID, &m.Text, &m.Kind, &m.CreatedAt, &m.ExpiresAt)
+
+		return m, err
+	})
+}
+
+func (d *dataExportRepo) GetPollVotes(ctx context.Context, userID int64) ([]dataexport.PollVote, error) {
+	const query = `
+		SELECT
+			p.message_id,
+			p.chat_id,
+			p.question,
+			o.text,
+			v.created_at
+		FROM poll_votes v
+		JOIN polls p ON p.message_id = v.poll_id
+		JOIN poll_options o ON o.poll_id = v.poll_id AND o.idx = v.idx
+		WHERE v.user_id = $1
+		ORDER BY v.created_at, v.poll_id, v.idx
+	`
+
+	rows, err := d.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.PollVote, error) {
+		v := dataexport.PollVote{}
+		err := row.Scan(&v.PollMessageID, &v.ChatID, &v.Question, &v.Option, &v.VotedAt)
+
+		return v, err
+	})
+}
+
+// GetReports lists reports the user filed; reports against the user belong to the reporters and moderators.
+func (d *dataExportRepo) GetReports(ctx context.Context, userID int64) ([]dataexport.Report, error) {
+	const query = `
+		SELECT
+			id,
+			chat_id,
+			message_id,
+			reason,
+			resolution,
+			resolved_at,
+			created_at
+		FROM message_reports
+		WHERE reporter_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := d.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.Report, error) {
+		r := dataexport.Report{}
+		err := row.Scan(&r.ID, &r.ChatID, &r.MessageID, &r.Reason, &r.Resolution, &r.ResolvedAt, &r.CreatedAt)
+
+		return r, err
+	})
+}
+
+func (d *dataExportRepo) GetScheduledMessages(ctx context.Context, userID int64) ([]dataexport.ScheduledMessage, error) {
+	const query = `
+		SELECT
+			id,
+			chat_id,
+			text,
+			send_at,
+			status,
+			created_at
+		FROM scheduled_messages
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := d.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.ScheduledMessage, error) {
+		s := dataexport.ScheduledMessage{}
+		err := row.Scan(&s.ID, &s.ChatID, &s.Text, &s.SendAt, &s.Status, &s.CreatedAt)
+
+		return s, err
+	})
+}
+
+func (d *dataExportRepo) GetBans(ctx context.Context, userID int64) ([]dataexport.Ban, error) {
+	const query = `
+		SELECT
+			chat_id,
+			reason,
+			expires_at,
+			created_at
+		FROM chat_bans
+		WHERE user_id = $1
+		ORDER BY created_at, chat_id
+	`
+
+	rows, err := d.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return collect(rows, func(row pgx.Rows) (dataexport.Ban, error) {
+		b := dataexport.Ban{}
+		err := row.Scan(&b.ChatID, &b.Reason, &b.ExpiresAt, &b.CreatedAt)
+
+		return b, err
+	})
+}
+
+func (d *dataExportRepo) GetSecurity(ctx context.Context, userID int64, lockoutKey string) (dataexport.Security, error) {
+	const stateQuery = `
+		SELECT
+			EXISTS (SELECT 1 FROM user_totp WHERE user_id = $1 AND confirmed_at IS NOT NULL),
+			COALESCE(a.failures, 0),
+			a.last_failure_at,
+			a.locked_until
+		FROM (SELECT 1) one
+		LEFT JOIN sign_in_attempts a ON a.key = $2
+	`
+
+	const identitiesQuery = `
+		SELECT
+			provider,
+			subject,
+			email,
+			created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	security := dataexport.Security{}
+
+	err := d.db.QueryRow(ctx, stateQuery, userID, lockoutKey).Scan(
+		&security.TwoFactorEnabled,
+		&security.SignInFailures,
+		&security.LastFailureAt,
+		&security.LockedUntil,
+	)
+	if err != nil {
+		return dataexport.Security{}, err
+	}
+
+	rows, err := d.db.Query(ctx, identitiesQuery, userID)
+	if err != nil {
+		return dataexport.Security{}, err
+	}
+
+	security.Identities, err = collect(rows, func(row pgx.Rows) (dataexport.Identity, error) {
+		i := dataexport.Identity{}
+		err := row.Scan(&i.Provider, &i.Subject, &i.Email, &i.LinkedAt)
+
+		return i, err
+	})
+	if err != nil {
+		return dataexport.Security{}, err
+	}
+
+	return security, nil
+}
+
+func scanJob(row pgx.Row) (dataexport.Job, error) {
+	job := dataexport.Job{}
+
+	var (
+		status                            string
+		startedAt, completedAt, expiresAt *time.Time
+	)
+
+	err := row.Scan(&job.ID, &job.UserID, &status, &job.Error, &job.Path, &job.Size, &job.CreatedAt, &startedAt, &completedAt, &expiresAt)
+	if err != nil {
+		return dataexport.Job{}, err
+	}
+
+	job.Status = dataexport.Status(status)
+
+	if startedAt != nil {
+		job.StartedAt = *startedAt
+	}
+
+	if completedAt != nil {
+		job.CompletedAt = *completedAt
+	}
+
+	if expiresAt != nil {
+		job.ExpiresAt = *expiresAt
+	}
+
+	return job, nil
+}
+
+func collect[T any](rows pgx.Rows, scan func(pgx.Rows) (T, error)) ([]T, error) {
+	defer rows.Close()
+
+	items := make([]T, 0)
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
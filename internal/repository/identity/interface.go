@@ -0,0 +1,15 @@
+package identity
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/identity"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Get(ctx context.Context, provider, subject string) (identity.Identity, error)
+	Create(ctx context.Context, linked identity.Identity) error
+	DeleteByUser(ctx context.Context, userID int64) error
+}
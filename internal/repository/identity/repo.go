@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/identity"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type identityRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewIdentityRepo(db postgres.QueryExecer) Repo {
+	return &identityRepo{
+		db: db,
+	}
+}
+
+func (i *identityRepo) WithTx(tx postgres.Tx) Repo {
+	return &identityRepo{
+		db: tx,
+	}
+}
+
+func (i *identityRepo) Get(ctx context.Context, provider, subject string) (identity.Identity, error) {
+	const query = `
+		SELECT
+			provider,
+			subject,
+			user_id,
+			email
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	linked := identity.Identity{}
+
+	err := i.db.QueryRow(ctx, query, provider, subject).Scan(&linked.Provider, &linked.Subject, &linked.UserID, &linked.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return identity.Identity{}, identity.ErrNotFound
+		}
+
+		return identity.Identity{}, err
+	}
+
+	return linked, nil
+}
+
+func (i *identityRepo) Create(ctx context.Context, linked identity.Identity) error {
+	const query = `
+		INSERT INTO user_identities(provider, subject, user_id, email)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, subject) DO NOTHING
+	`
+
+	res, err := i.db.Exec(ctx, query, linked.Provider, linked.Subject, linked.UserID, linked.Email)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return identity.ErrAlreadyLinked
+	}
+
+	return nil
+}
+
+func (i *identityRepo) DeleteByUser(ctx context.Context, userID int64) error {
+	const query = `
+		DELETE FROM user_identities
+		WHERE user_id = $1
+	`
+
+	_, err := i.db.Exec(ctx, query, userID)
+
+	return err
+}
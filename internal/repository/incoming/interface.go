@@ -0,0 +1,20 @@
+package incoming
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Create(ctx context.Context, hook incoming.Incoming) (incoming.Incoming, error)
+	CountActive(ctx context.Context, chatID int64) (int, error)
+	Get(ctx context.Context, hookID int64) (incoming.Incoming, error)
+	GetByChat(ctx context.Context, chatID int64) ([]incoming.Incoming, error)
+	// Revoke returns the ID of the revoked webhook's bot user.
+	Revoke(ctx context.Context, chatID, hookID int64) (int64, error)
+	Consume(ctx context.Context, hookID int64, now time.Time, windowStart time.Time) (incoming.Usage, error)
+}
@@ -0,0 +1,195 @@
+package incoming
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+const incomingColumns = `
+	i.id,
+	i.chat_id,
+	i.user_id,
+	u.login,
+	i.created_by,
+	i.token_hash,
+	i.rate_limit,
+	i.created_at,
+	i.revoked_at
+`
+
+type incomingRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewIncomingRepo(db postgres.QueryExecer) Repo {
+	return &incomingRepo{
+		db: db,
+	}
+}
+
+func (i *incomingRepo) WithTx(tx postgres.Tx) Repo {
+	return &incomingRepo{
+		db: tx,
+	}
+}
+
+func (i *incomingRepo) Create(ctx context.Context, hook incoming.Incoming) (incoming.Incoming, error) {
+	const query = `
+		INSERT INTO incoming_webhooks(chat_id, user_id, created_by, token_hash, rate_limit)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := i.db.QueryRow(ctx, query, hook.ChatID, hook.UserID, hook.CreatedBy, hook.TokenHash, hook.RateLimit).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		return incoming.Incoming{}, err
+	}
+
+	return hook, nil
+}
+
+func (i *incomingRepo) CountActive(ctx context.Context, chatID int64) (int, error) {
+	const query = `
+		SELECT count(*)
+		FROM incoming_webhooks
+		WHERE chat_id = $1 AND revoked_at IS NULL
+	`
+
+	var count int
+
+	err := i.db.QueryRow(ctx, query, chatID).Scan(&count)
+
+	return count, err
+}
+
+func (i *incomingRepo) Get(ctx context.Context, hookID int64) (incoming.Incoming, error) {
+	const query = `SELECT ` + incomingColumns + `
+		FROM incoming_webhooks i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.id = $1
+	`
+
+	rows, err := i.db.Query(ctx, query, hookID)
+	if err != nil {
+		return incoming.Incoming{}, err
+	}
+
+	hooks, err := scanIncoming(rows)
+	if err != nil {
+		return incoming.Incoming{}, err
+	}
+
+	if len(hooks) == 0 {
+		return incoming.Incoming{}, incoming.ErrNotFound
+	}
+
+	return hooks[0], nil
+}
+
+func (i *incomingRepo) GetByChat(ctx context.Context, chatID int64) ([]incoming.Incoming, error) {
+	const query = `SELECT ` + incomingColumns + `
+		FROM incoming_webhooks i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.chat_id = $1
+		ORDER BY i.id
+	`
+
+	rows, err := i.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanIncoming(rows)
+}
+
+func (i *incomingRepo) Revoke(ctx context.Context, chatID, hookID int64) (int64, error) {
+	const query = `
+		UPDATE incoming_webhooks
+		SET revoked_at = now()
+		WHERE id = $1 AND chat_id = $2 AND revoked_at IS NULL
+		RETURNING user_id
+	`
+
+	var userID int64
+
+	err := i.db.QueryRow(ctx, query, hookID, chatID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, incoming.ErrNotFound
+		}
+
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (i *incomingRepo) Consume(ctx context.Context, hookID int64, now time.Time, windowStart time.Time) (incoming.Usage, error) {
+	const query = `
+		UPDATE incoming_webhooks
+		SET
+			window_count = CASE
+				WHEN window_start < $3 THEN 1
+				ELSE window_count + 1
+			END,
+			window_start = CASE
+				WHEN window_start < $3 THEN $2
+				ELSE window_start
+			END
+		WHERE id = $1
+		RETURNING window_count, window_start
+	`
+
+	usage := incoming.Usage{}
+
+	err := i.db.QueryRow(ctx, query, hookID, now, windowStart).Scan(&usage.Count, &usage.WindowStart)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return incoming.Usage{}, incoming.ErrNotFound
+		}
+
+		return incoming.Usage{}, err
+	}
+
+	return usage, nil
+}
+
+func scanIncoming(rows pgx.Rows) ([]incoming.Incoming, error) {
+	defer rows.Close()
+
+	hooks := make([]incoming.Incoming, 0)
+
+	for rows.Next() {
+		hook := incoming.Incoming{}
+
+		var revokedAt *time.Time
+
+		err := rows.Scan(
+			&hook.ID,
+			&hook.ChatID,
+			&hook.UserID,
+			&hook.Name,
+			&hook.CreatedBy,
+			&hook.TokenHash,
+			&hook.RateLimit,
+			&hook.CreatedAt,
+			&revokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if revokedAt != nil {
+			hook.RevokedAt = *revokedAt
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, rows.Err()
+}
@@ -0,0 +1,19 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+)
+
+type Repo interface {
+	Get(ctx context.Context, key lockout.Key) (lockout.State, error)
+	// Reserve counts an attempt in one step unless the key is locked at now, and reports whether it did.
+	// An attempt past the free ones locks the key for the base delay straight away.
+	Reserve(ctx context.Context, key lockout.Key, policy lockout.Policy, now time.Time) (lockout.State, bool, error)
+	// Release gives back a reserved attempt, lifting the lock once the count is within the free attempts again.
+	Release(ctx context.Context, key lockout.Key, policy lockout.Policy) error
+	Lock(ctx context.Context, key lockout.Key, until time.Time) error
+	Reset(ctx context.Context, key lockout.Key) error
+}
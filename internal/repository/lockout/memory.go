@@ -0,0 +1,99 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+)
+
+type memoryState struct {
+	lockout.State
+	lastFailureAt time.Time
+}
+
+type memoryRepo struct {
+	states map[lockout.Key]memoryState
+	mu     *sync.Mutex
+}
+
+func NewMemoryLockoutRepo() Repo {
+	return &memoryRepo{
+		states: make(map[lockout.Key]memoryState),
+		mu:     &sync.Mutex{},
+	}
+}
+
+func (m *memoryRepo) Get(ctx context.Context, key lockout.Key) (lockout.State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.states[key].State, nil
+}
+
+func (m *memoryRepo) Reserve(ctx context.Context, key lockout.Key, policy lockout.Policy, now time.Time) (lockout.State, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.states[key]
+	if state.RetryAfter(now) > 0 {
+		return state.State, false, nil
+	}
+
+	if state.lastFailureAt.Before(now.Add(-policy.Window)) {
+		state.Failures = 0
+	}
+
+	state.Failures++
+	state.lastFailureAt = now
+
+	if state.Failures > policy.FreeAttempts {
+		state.LockedUntil = now.Add(policy.BaseDelay)
+	}
+
+	m.states[key] = state
+
+	return state.State, true, nil
+}
+
+func (m *memoryRepo) Release(ctx context.Context, key lockout.Key, policy lockout.Policy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[key]
+	if !ok || state.Failures == 0 {
+		return nil
+	}
+
+	state.Failures--
+	if state.Failures <= policy.FreeAttempts {
+		state.LockedUntil = time.Time{}
+	}
+
+	m.states[key] = state
+
+	return nil
+}
+
+func (m *memoryRepo) Lock(ctx context.Context, key lockout.Key, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[key]
+	if ok && until.After(state.LockedUntil) {
+		state.LockedUntil = until
+		m.states[key] = state
+	}
+
+	return nil
+}
+
+func (m *memoryRepo) Reset(ctx context.Context, key lockout.Key) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.states, key)
+
+	return nil
+}
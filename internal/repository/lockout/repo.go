@@ -0,0 +1,137 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type lockoutRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewLockoutRepo(db postgres.QueryExecer) Repo {
+	return &lockoutRepo{
+		db: db,
+	}
+}
+
+func (l *lockoutRepo) Get(ctx context.Context, key lockout.Key) (lockout.State, error) {
+	const query = `
+		SELECT
+			failures,
+			locked_until
+		FROM sign_in_attempts
+		WHERE key = $1
+	`
+
+	state := lockout.State{}
+	var lockedUntil *time.Time
+
+	err := l.db.QueryRow(ctx, query, key.String()).Scan(&state.Failures, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return lockout.State{}, nil
+		}
+
+		return lockout.State{}, err
+	}
+
+	if lockedUntil != nil {
+		state.LockedUntil = *lockedUntil
+	}
+
+	return state, nil
+}
+
+func (l *lockoutRepo) Reserve(ctx context.Context, key lockout.Key, policy lockout.Policy, now time.Time) (lockout.State, bool, error) {
+	// The conflict update is skipped while the key is locked, so no row comes back for a refused attempt.
+	const query = `
+		INSERT INTO sign_in_attempts(key, failures, last_failure_at, locked_until)
+		VALUES ($1, 1, $2, CASE WHEN 1 > $4::INT THEN $5::TIMESTAMPTZ END)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN sign_in_attempts.last_failure_at < $3 THEN 1
+				ELSE sign_in_attempts.failures + 1
+			END,
+			last_failure_at = $2,
+			locked_until = CASE
+				WHEN CASE
+					WHEN sign_in_attempts.last_failure_at < $3 THEN 1
+					ELSE sign_in_attempts.failures + 1
+				END > $4::INT THEN $5::TIMESTAMPTZ
+				ELSE sign_in_attempts.locked_until
+			END
+		WHERE sign_in_attempts.locked_until IS NULL OR sign_in_attempts.locked_until <= $2
+		RETURNING failures, locked_until
+	`
+
+	state := lockout.State{}
+	var lockedUntil *time.Time
+
+	err := l.db.QueryRow(ctx, query, key.String(), now, now.Add(-policy.Window), policy.FreeAttempts, now.Add(policy.BaseDelay)).Scan(&state.Failures, &lockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			state, err = l.Get(ctx, key)
+
+			return state, false, err
+		}
+
+		return lockout.State{}, false, err
+	}
+
+	if lockedUntil != nil {
+		state.LockedUntil = *lockedUntil
+	}
+
+	return state, true, nil
+}
+
+func (l *lockoutRepo) Release(ctx context.Context, key lockout.Key, policy lockout.Policy) error {
+	const query = `
+		UPDATE sign_in_attempts SET
+			failures = failures - 1,
+			locked_until = CASE WHEN failures - 1 > $2 THEN locked_until END
+		WHERE key = $1 AND failures > 0
+	`
+
+	_, err := l.db.Exec(ctx, query, key.String(), policy.FreeAttempts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *lockoutRepo) Lock(ctx context.Context, key lockout.Key, until time.Time) error {
+	const query = `
+		UPDATE sign_in_attempts
+		SET locked_until = GREATEST(locked_until, $2)
+		WHERE key = $1
+	`
+
+	_, err := l.db.Exec(ctx, query, key.String(), until)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l *lockoutRepo) Reset(ctx context.Context, key lockout.Key) error {
+	const query = `
+		DELETE FROM sign_in_attempts
+		WHERE key = $1
+	`
+
+	_, err := l.db.Exec(ctx, query, key.String())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
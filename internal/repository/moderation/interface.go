@@ -0,0 +1,18 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Ban(ctx context.Context, ban moderation.Ban) error
+	Unban(ctx context.Context, chatID, userID int64) error
+	GetBan(ctx context.Context, chatID, userID int64) (moderation.Ban, error)
+	GetBans(ctx context.Context, chatID int64) ([]moderation.Ban, error)
+	AddEntry(ctx context.Context, entry moderation.Entry) (moderation.Entry, error)
+	GetLog(ctx context.Context, chatID int64, limit int) ([]moderation.Entry, error)
+}
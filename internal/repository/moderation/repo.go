@@ -0,0 +1,219 @@
+package moderation
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type moderationRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewModerationRepo(db postgres.QueryExecer) Repo {
+	return &moderationRepo{
+		db: db,
+	}
+}
+
+func (m *moderationRepo) WithTx(tx postgres.Tx) Repo {
+	return &moderationRepo{
+		db: tx,
+	}
+}
+
+func (m *moderationRepo) Ban(ctx context.Context, ban moderation.Ban) error {
+	const query = `
+		INSERT INTO chat_bans(chat_id, user_id, banned_by, reason, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chat_id, user_id) DO UPDATE SET
+			banned_by = EXCLUDED.banned_by,
+			reason = EXCLUDED.reason,
+			expires_at = EXCLUDED.expires_at,
+			created_at = now()
+	`
+
+	_, err := m.db.Exec(ctx, query, ban.ChatID, ban.UserID, ban.BannedBy, ban.Reason, nullTime(ban.ExpiresAt))
+
+	return err
+}
+
+func (m *moderationRepo) Unban(ctx context.Context, chatID, userID int64) error {
+	const query = `
+		DELETE FROM chat_bans
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	res, err := m.db.Exec(ctx, query, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return moderation.ErrNotBanned
+	}
+
+	return nil
+}
+
+func (m *moderationRepo) GetBan(ctx context.Context, chatID, userID int64) (moderation.Ban, error) {
+	const query = `
+		SELECT
+			chat_id,
+			user_id,
+			banned_by,
+			reason,
+			expires_at,
+			created_at
+		FROM chat_bans
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	rows, err := m.db.Query(ctx, query, chatID, userID)
+	if err != nil {
+		return moderation.Ban{}, err
+	}
+
+	bans, err := scanBans(rows)
+	if err != nil {
+		return moderation.Ban{}, err
+	}
+
+	if len(bans) == 0 {
+		return moderation.Ban{}, moderation.ErrNotBanned
+	}
+
+	return bans[0], nil
+}
+
+func (m *moderationRepo) GetBans(ctx context.Context, chatID int64) ([]moderation.Ban, error) {
+	const query = `
+		SELECT
+			chat_id,
+			user_id,
+			banned_by,
+			reason,
+			expires_at,
+			created_at
+		FROM chat_bans
+		WHERE chat_id = $1 AND (expires_at IS NULL OR expires_at > now())
+		ORDER BY created_at DESC
+	`
+
+	rows, err := m.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanBans(rows)
+}
+
+func (m *moderationRepo) AddEntry(ctx context.Context, entry moderation.Entry) (moderation.Entry, error) {
+	const query = `
+		INSERT INTO moderation_log(chat_id, actor_id, target_id, action, reason, expires_at, message_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0))
+		RETURNING id, created_at
+	`
+
+	err := m.db.QueryRow(ctx, query,
+		entry.ChatID,
+		entry.ActorID,
+		entry.TargetID,
+		string(entry.Action),
+		entry.Reason,
+		nullTime(entry.ExpiresAt),
+		entry.MessageID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return moderation.Entry{}, err
+	}
+
+	return entry, nil
+}
+
+func (m *moderationRepo) GetLog(ctx context.Context, chatID int64, limit int) ([]moderation.Entry, error) {
+	const query = `
+		SELECT
+			id,
+			chat_id,
+			actor_id,
+			target_id,
+			action,
+			reason,
+			expires_at,
+			COALESCE(message_id, 0),
+			created_at
+		FROM moderation_log
+		WHERE chat_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	rows, err := m.db.Query(ctx, query, chatID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]moderation.Entry, 0)
+
+	for rows.Next() {
+		entry := moderation.Entry{}
+
+		var (
+			action    string
+			expiresAt *time.Time
+		)
+
+		err = rows.Scan(&entry.ID, &entry.ChatID, &entry.ActorID, &entry.TargetID, &action, &entry.Reason, &expiresAt, &entry.MessageID, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Action = moderation.Action(action)
+
+		if expiresAt != nil {
+			entry.ExpiresAt = *expiresAt
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func scanBans(rows pgx.Rows) ([]moderation.Ban, error) {
+	defer rows.Close()
+
+	bans := make([]moderation.Ban, 0)
+
+	for rows.Next() {
+		ban := moderation.Ban{}
+
+		var expiresAt *time.Time
+
+		err := rows.Scan(&ban.ChatID, &ban.UserID, &ban.BannedBy, &ban.Reason, &expiresAt, &ban.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if expiresAt != nil {
+			ban.ExpiresAt = *expiresAt
+		}
+
+		bans = append(bans, ban)
+	}
+
+	return bans, rows.Err()
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
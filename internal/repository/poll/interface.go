@@ -0,0 +1,21 @@
+package poll
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	// Create stores the poll and its options; it must run in a transaction.
+	Create(ctx context.Context, p poll.Poll) (poll.Poll, error)
+	Get(ctx context.Context, messageID int64) (poll.Poll, error)
+	GetVotes(ctx context.Context, messageID, userID int64) ([]int, error)
+	// SetVotes replaces the user's votes and fails with poll.ErrClosed once the poll closes; it must run in a transaction.
+	SetVotes(ctx context.Context, messageID, userID int64, choice []int) error
+	Close(ctx context.Context, messageID int64) error
+	// CloseDue closes up to limit polls whose deadline passed and returns their message IDs.
+	CloseDue(ctx context.Context, limit int) ([]int64, error)
+}
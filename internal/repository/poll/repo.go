@@ -0,0 +1,301 @@
+package poll
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type pollRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewPollRepo(db postgres.QueryExecer) Repo {
+	return &pollRepo{
+		db: db,
+	}
+}
+
+func (p *pollRepo) WithTx(tx postgres.Tx) Repo {
+	return &pollRepo{
+		db: tx,
+	}
+}
+
+func (p *pollRepo) Create(ctx context.Context, newPoll poll.Poll) (poll.Poll, error) {
+	const insertPoll = `
+		INSERT INTO polls(message_id, chat_id, creator_id, question, multiple, anonymous, closes_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	const insertOptions = `
+		INSERT INTO poll_options(poll_id, idx, text)
+		SELECT $1, idx - 1, text
+		FROM unnest($2::text[]) WITH ORDINALITY AS o(text, idx)
+	`
+
+	err := p.db.QueryRow(ctx, insertPoll,
+		newPoll.MessageID,
+		newPoll.ChatID,
+		newPoll.CreatorID,
+		newPoll.Question,
+		newPoll.Multiple,
+		newPoll.Anonymous,
+		nullTime(newPoll.ClosesAt),
+	).Scan(&newPoll.CreatedAt)
+	if err != nil {
+		return poll.Poll{}, err
+	}
+
+	texts := make([]string, 0, len(newPoll.Options))
+	for _, option := range newPoll.Options {
+		texts = append(texts, option.Text)
+	}
+
+	_, err = p.db.Exec(ctx, insertOptions, newPoll.MessageID, texts)
+	if err != nil {
+		return poll.Poll{}, err
+	}
+
+	return newPoll, nil
+}
+
+func (p *pollRepo) Get(ctx context.Context, messageID int64) (poll.Poll, error) {
+	const pollQuery = `
+		SELECT
+			message_id,
+			chat_id,
+			creator_id,
+			question,
+			multiple,
+			anonymous,
+			closes_at,
+			closed_at,
+			created_at,
+			(SELECT count(DISTINCT user_id) FROM poll_votes WHERE poll_id = polls.message_id)
+		FROM polls
+		WHERE message_id = $1
+	`
+
+	// Voter IDs are never read for anonymous polls.
+	const optionsQuery = `
+		SELECT
+			o.idx,
+			o.text,
+			count(v.user_id),
+			COALESCE(array_agg(v.user_id ORDER BY v.created_at, v.user_id) FILTER (WHERE v.user_id IS NOT NULL AND NOT $2), '{}')
+		FROM poll_options o
+		LEFT JOIN poll_votes v ON v.poll_id = o.poll_id AND v.idx = o.idx
+		WHERE o.poll_id = $1
+		GROUP BY o.idx, o.text
+		ORDER BY o.idx
+	`
+
+	res := poll.Poll{}
+
+	var closesAt, closedAt *time.Time
+
+	err := p.db.QueryRow(ctx, pollQuery, messageID).Scan(
+		&res.MessageID,
+		&res.ChatID,
+		&res.CreatorID,
+		&res.Question,
+		&res.Multiple,
+		&res.Anonymous,
+		&closesAt,
+		&closedAt,
+		&res.CreatedAt,
+		&res.Voters,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return poll.Poll{}, poll.ErrNotFound
+		}
+
+		return poll.Poll{}, err
+	}
+
+	if closesAt != nil {
+		res.ClosesAt = *closesAt
+	}
+
+	if closedAt != nil {
+		res.ClosedAt = *closedAt
+	}
+
+	rows, err := p.db.Query(ctx, optionsQuery, messageID, res.Anonymous)
+	if err != nil {
+		return poll.Poll{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		option := poll.Option{}
+
+		err = rows.Scan(&option.Index, &option.Text, &option.Votes, &option.Voters)
+		if err != nil {
+			return poll.Poll{}, err
+		}
+
+		res.Options = append(res.Options, option)
+	}
+
+	return res, rows.Err()
+}
+
+func (p *pollRepo) GetVotes(ctx context.Context, messageID, userID int64) ([]int, error) {
+	const query = `
+		SELECT idx
+		FROM poll_votes
+		WHERE poll_id = $1 AND user_id = $2
+		ORDER BY idx
+	`
+
+	rows, err := p.db.Query(ctx, query, messageID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	choice := make([]int, 0)
+
+	for rows.Next() {
+		var index int
+
+		err = rows.Scan(&index)
+		if err != nil {
+			return nil, err
+		}
+
+		choice = append(choice, index)
+	}
+
+	return choice, rows.Err()
+}
+
+// SetVotes serializes one user's votes on a poll with a transaction-scoped advisory lock, so two concurrent
+// votes cannot both delete and then insert different options. The share lock on the open poll makes a
+// concurrent Close wait for the vote to land without serializing votes of different users.
+func (p *pollRepo) SetVotes(ctx context.Context, messageID, userID int64, choice []int) error {
+	const voterLockQuery = `
+		SELECT pg_advisory_xact_lock(hashtextextended('poll_votes:' || $1::text || ':' || $2::text, 0))
+	`
+
+	const lockQuery = `
+		SELECT 1
+		FROM polls
+		WHERE message_id = $1 AND closed_at IS NULL AND (closes_at IS NULL OR closes_at > now())
+		FOR SHARE
+	`
+
+	const deleteQuery = `
+		DELETE FROM poll_votes
+		WHERE poll_id = $1 AND user_id = $2
+	`
+
+	const insertQuery = `
+		INSERT INTO poll_votes(poll_id, idx, user_id)
+		SELECT $1, idx, $2
+		FROM unnest($3::int[]) AS idx
+	`
+
+	_, err := p.db.Exec(ctx, voterLockQuery, messageID, userID)
+	if err != nil {
+		return err
+	}
+
+	var open int
+
+	err = p.db.QueryRow(ctx, lockQuery, messageID).Scan(&open)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return poll.ErrClosed
+		}
+
+		return err
+	}
+
+	_, err = p.db.Exec(ctx, deleteQuery, messageID, userID)
+	if err != nil {
+		return err
+	}
+
+	if len(choice) == 0 {
+		return nil
+	}
+
+	_, err = p.db.Exec(ctx, insertQuery, messageID, userID, choice)
+
+	return err
+}
+
+func (p *pollRepo) Close(ctx context.Context, messageID int64) error {
+	const query = `
+		UPDATE polls
+		SET closed_at = now()
+		WHERE message_id = $1 AND closed_at IS NULL
+	`
+
+	res, err := p.db.Exec(ctx, query, messageID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return poll.ErrClosed
+	}
+
+	return nil
+}
+
+// CloseDue records the deadline as the close time so each poll is returned once; replicas skip rows
+// another one is closing.
+func (p *pollRepo) CloseDue(ctx context.Context, limit int) ([]int64, error) {
+	const query = `
+		UPDATE polls
+		SET closed_at = closes_at
+		WHERE message_id IN (
+			SELECT message_id
+			FROM polls
+			WHERE closed_at IS NULL AND closes_at <= now()
+			ORDER BY closes_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING message_id
+	`
+
+	rows, err := p.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messageIDs := make([]int64, 0)
+
+	for rows.Next() {
+		var messageID int64
+
+		err = rows.Scan(&messageID)
+		if err != nil {
+			return nil, err
+		}
+
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	return messageIDs, rows.Err()
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
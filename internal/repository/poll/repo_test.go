@@ -0,0 +1,92 @@
+package poll
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connect opens a connection to a migrated database named by TEST_POSTGRES_DSN; the test is skipped without one.
+func connect(t *testing.T) *pgx.Conn {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	conn, err := pgx.Connect(context.Background(), dsn)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close(context.Background())
+	})
+
+	return conn
+}
+
+func Test_PollRepo_SetVotes_ConcurrentSingleChoice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	setup := connect(t)
+
+	var userID, chatID, messageID int64
+
+	login := fmt.Sprintf("pv%d", time.Now().UnixNano()%1e12)
+
+	require.NoError(t, setup.QueryRow(ctx, `INSERT INTO users(login, password) VALUES ($1, '') RETURNING id`, login).Scan(&userID))
+	require.NoError(t, setup.QueryRow(ctx, `INSERT INTO chats(name) VALUES ($1) RETURNING id`, login).Scan(&chatID))
+	require.NoError(t, setup.QueryRow(ctx, `INSERT INTO messages(chat_id, user_id, message, kind) VALUES ($1, $2, 'pick one', 'poll') RETURNING id`, chatID, userID).Scan(&messageID))
+
+	t.Cleanup(func() {
+		_, _ = setup.Exec(ctx, `DELETE FROM messages WHERE chat_id = $1`, chatID)
+		_, _ = setup.Exec(ctx, `DELETE FROM chats WHERE id = $1`, chatID)
+		_, _ = setup.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	_, err := NewPollRepo(setup).Create(ctx, poll.Poll{
+		MessageID: messageID,
+		ChatID:    chatID,
+		CreatorID: userID,
+		Question:  "pick one",
+		Options:   []poll.Option{{Text: "a"}, {Text: "b"}},
+	})
+	require.NoError(t, err)
+
+	first, err := connect(t).Begin(ctx)
+	require.NoError(t, err)
+
+	second, err := connect(t).Begin(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, NewPollRepo(setup).WithTx(first).SetVotes(ctx, messageID, userID, []int{0}))
+
+	// The second vote starts while the first is uncommitted and must wait for it instead of
+	// missing its row in the delete.
+	done := make(chan error, 1)
+	go func() {
+		done <- NewPollRepo(setup).WithTx(second).SetVotes(ctx, messageID, userID, []int{1})
+	}()
+
+	select {
+	case err = <-done:
+		t.Fatalf("second vote did not wait for the first: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, first.Commit(ctx))
+	require.NoError(t, <-done)
+	require.NoError(t, second.Commit(ctx))
+
+	choice, err := NewPollRepo(setup).GetVotes(ctx, messageID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, choice)
+}
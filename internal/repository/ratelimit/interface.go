@@ -0,0 +1,13 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+)
+
+type Repo interface {
+	// Take spends one token if the bucket has one; tokens is what is left, or what is available when denied.
+	Take(ctx context.Context, key ratelimit.Key, bucket ratelimit.Bucket, now time.Time) (allowed bool, tokens float64, err error)
+}
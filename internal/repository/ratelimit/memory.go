@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+)
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+type memoryRepo struct {
+	buckets map[ratelimit.Key]memoryBucket
+	mu      *sync.Mutex
+}
+
+func NewMemoryRateLimitRepo() Repo {
+	return &memoryRepo{
+		buckets: make(map[ratelimit.Key]memoryBucket),
+		mu:      &sync.Mutex{},
+	}
+}
+
+func (m *memoryRepo) Take(ctx context.Context, key ratelimit.Key, bucket ratelimit.Bucket, now time.Time) (bool, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.buckets[key]
+	if !ok {
+		state = memoryBucket{tokens: bucket.Capacity, updatedAt: now}
+	}
+
+	tokens := bucket.Refill(state.tokens, now.Sub(state.updatedAt))
+	if tokens < 1 {
+		return false, tokens, nil
+	}
+
+	if now.After(state.updatedAt) {
+		state.updatedAt = now
+	}
+
+	state.tokens = tokens - 1
+	m.buckets[key] = state
+
+	return true, state.tokens, nil
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type rateLimitRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewRateLimitRepo(db postgres.QueryExecer) Repo {
+	return &rateLimitRepo{
+		db: db,
+	}
+}
+
+func (r *rateLimitRepo) Take(ctx context.Context, key ratelimit.Key, bucket ratelimit.Bucket, now time.Time) (bool, float64, error) {
+	const take = `
+		INSERT INTO rate_limit_buckets(key, tokens, updated_at)
+		VALUES ($1, $2 - 1, $4)
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = LEAST($2, rate_limit_buckets.tokens + GREATEST(0, EXTRACT(EPOCH FROM ($4 - rate_limit_buckets.updated_at))) * $3) - 1,
+			updated_at = GREATEST(rate_limit_buckets.updated_at, $4)
+		WHERE LEAST($2, rate_limit_buckets.tokens + GREATEST(0, EXTRACT(EPOCH FROM ($4 - rate_limit_buckets.updated_at))) * $3) >= 1
+		RETURNING tokens
+	`
+
+	const available = `
+		SELECT LEAST($2, tokens + GREATEST(0, EXTRACT(EPOCH FROM ($4 - updated_at))) * $3)
+		FROM rate_limit_buckets
+		WHERE key = $1
+	`
+
+	var tokens float64
+
+	err := r.db.QueryRow(ctx, take, key.String(), bucket.Capacity, bucket.PerSecond, now).Scan(&tokens)
+	if err == nil {
+		return true, tokens, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, 0, err
+	}
+
+	err = r.db.QueryRow(ctx, available, key.String(), bucket.Capacity, bucket.PerSecond, now).Scan(&tokens)
+	if err != nil {
+		return false, 0, err
+	}
+
+	return false, tokens, nil
+}
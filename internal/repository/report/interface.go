@@ -0,0 +1,16 @@
+package report
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/report"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Create(ctx context.Context, rep report.Report, text string) (report.Report, error)
+	GetOpen(ctx context.Context, chatID int64, limit int) ([]report.Summary, error)
+	GetOpenForMessage(ctx context.Context, chatID, messageID int64) (report.Summary, error)
+	Resolve(ctx context.Context, chatID, messageID, resolvedBy int64, action report.Action) (int, error)
+}
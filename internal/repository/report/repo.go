@@ -0,0 +1,147 @@
+package report
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/report"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+const summaryColumns = `
+	chat_id,
+	message_id,
+	author_id,
+	(array_agg(message_text ORDER BY id))[1],
+	count(*),
+	array_agg(reason ORDER BY id),
+	min(created_at),
+	max(created_at)
+`
+
+type reportRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewReportRepo(db postgres.QueryExecer) Repo {
+	return &reportRepo{
+		db: db,
+	}
+}
+
+func (r *reportRepo) WithTx(tx postgres.Tx) Repo {
+	return &reportRepo{
+		db: tx,
+	}
+}
+
+// Create stores a report with a snapshot of the message text, so the queue still shows it after deletion.
+func (r *reportRepo) Create(ctx context.Context, rep report.Report, text string) (report.Report, error) {
+	const query = `
+		INSERT INTO message_reports(chat_id, message_id, author_id, reporter_id, message_text, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (message_id, reporter_id) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, rep.ChatID, rep.MessageID, rep.AuthorID, rep.ReporterID, text, rep.Reason).Scan(&rep.ID, &rep.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return report.Report{}, report.ErrAlreadyReported
+		}
+
+		return report.Report{}, err
+	}
+
+	return rep, nil
+}
+
+// GetOpen lists messages with open reports, most reported first.
+func (r *reportRepo) GetOpen(ctx context.Context, chatID int64, limit int) ([]report.Summary, error) {
+	const query = `SELECT ` + summaryColumns + `
+		FROM message_reports
+		WHERE chat_id = $1 AND resolved_at IS NULL
+		GROUP BY chat_id, message_id, author_id
+		ORDER BY count(*) DESC, min(created_at)
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, chatID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := make([]report.Summary, 0)
+
+	for rows.Next() {
+		summary, err := scanSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+func (r *reportRepo) GetOpenForMessage(ctx context.Context, chatID, messageID int64) (report.Summary, error) {
+	const query = `SELECT ` + summaryColumns + `
+		FROM message_reports
+		WHERE chat_id = $1 AND message_id = $2 AND resolved_at IS NULL
+		GROUP BY chat_id, message_id, author_id
+	`
+
+	summary, err := scanSummary(r.db.QueryRow(ctx, query, chatID, messageID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return report.Summary{}, report.ErrNotFound
+		}
+
+		return report.Summary{}, err
+	}
+
+	return summary, nil
+}
+
+// Resolve closes every open report on the message and returns how many were closed.
+func (r *reportRepo) Resolve(ctx context.Context, chatID, messageID, resolvedBy int64, action report.Action) (int, error) {
+	const query = `
+		UPDATE message_reports
+		SET
+			resolved_by = $3,
+			resolution = $4,
+			resolved_at = now()
+		WHERE chat_id = $1 AND message_id = $2 AND resolved_at IS NULL
+	`
+
+	res, err := r.db.Exec(ctx, query, chatID, messageID, resolvedBy, string(action))
+	if err != nil {
+		return 0, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return 0, report.ErrNotFound
+	}
+
+	return int(res.RowsAffected()), nil
+}
+
+func scanSummary(row pgx.Row) (report.Summary, error) {
+	summary := report.Summary{}
+
+	err := row.Scan(
+		&summary.ChatID,
+		&summary.MessageID,
+		&summary.AuthorID,
+		&summary.Text,
+		&summary.Count,
+		&summary.Reasons,
+		&summary.FirstReportedAt,
+		&summary.LastReportedAt,
+	)
+
+	return summary, err
+}
@@ -0,0 +1,21 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/schedule"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Create(ctx context.Context, msg schedule.Message) (schedule.Message, error)
+	CountPending(ctx context.Context, userID int64) (int, error)
+	GetPending(ctx context.Context, userID, chatID int64) ([]schedule.Message, error)
+	Cancel(ctx context.Context, userID, scheduledID int64) error
+	// ClaimDue locks due messages until the surrounding transaction ends; it must run in one.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]schedule.Message, error)
+	MarkSent(ctx context.Context, scheduledID, messageID int64) error
+	MarkFailed(ctx context.Context, scheduledID int64, reason string) error
+}
@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/schedule"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+const scheduledColumns = `
+	id,
+	chat_id,
+	user_id,
+	text,
+	send_at,
+	status,
+	last_error,
+	COALESCE(message_id, 0),
+	created_at
+`
+
+type scheduleRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewScheduleRepo(db postgres.QueryExecer) Repo {
+	return &scheduleRepo{
+		db: db,
+	}
+}
+
+func (s *scheduleRepo) WithTx(tx postgres.Tx) Repo {
+	return &scheduleRepo{
+		db: tx,
+	}
+}
+
+func (s *scheduleRepo) Create(ctx context.Context, msg schedule.Message) (schedule.Message, error) {
+	const query = `
+		INSERT INTO scheduled_messages(chat_id, user_id, text, send_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at
+	`
+
+	var status string
+
+	err := s.db.QueryRow(ctx, query, msg.ChatID, msg.UserID, msg.Text, msg.SendAt).Scan(&msg.ID, &status, &msg.CreatedAt)
+	if err != nil {
+		return schedule.Message{}, err
+	}
+
+	msg.Status = schedule.Status(status)
+
+	return msg, nil
+}
+
+func (s *scheduleRepo) CountPending(ctx context.Context, userID int64) (int, error) {
+	const query = `
+		SELECT count(*)
+		FROM scheduled_messages
+		WHERE user_id = $1 AND status = 'pending'
+	`
+
+	var count int
+
+	err := s.db.QueryRow(ctx, query, userID).Scan(&count)
+
+	return count, err
+}
+
+// GetPending lists the user's pending messages, in every chat when chatID is zero.
+func (s *scheduleRepo) GetPending(ctx context.Context, userID, chatID int64) ([]schedule.Message, error) {
+	const query = `SELECT ` + scheduledColumns + `
+		FROM scheduled_messages
+		WHERE user_id = $1 AND ($2 = 0 OR chat_id = $2) AND status = 'pending'
+		ORDER BY send_at, id
+	`
+
+	rows, err := s.db.Query(ctx, query, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanScheduled(rows)
+}
+
+// Cancel waits for a worker holding the row, so a message being sent can no longer be cancelled.
+func (s *scheduleRepo) Cancel(ctx context.Context, userID, scheduledID int64) error {
+	const query = `
+		UPDATE scheduled_messages
+		SET status = 'cancelled'
+		WHERE id = $1 AND user_id = $2 AND status = 'pending'
+	`
+
+	res, err := s.db.Exec(ctx, query, scheduledID, userID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return schedule.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *scheduleRepo) ClaimDue(ctx context.Context, now time.Time, limit int) ([]schedule.Message, error) {
+	const query = `SELECT ` + scheduledColumns + `
+		FROM scheduled_messages
+		WHERE status = 'pending' AND send_at <= $1
+		ORDER BY send_at, id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := s.db.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanScheduled(rows)
+}
+
+func (s *scheduleRepo) MarkSent(ctx context.Context, scheduledID, messageID int64) error {
+	const query = `
+		UPDATE scheduled_messages
+		SET
+			status = 'sent',
+			message_id = $2,
+			sent_at = now()
+		WHERE id = $1
+	`
+
+	_, err := s.db.Exec(ctx, query, scheduledID, messageID)
+
+	return err
+}
+
+func (s *scheduleRepo) MarkFailed(ctx context.Context, scheduledID int64, reason string) error {
+	const query = `
+		UPDATE scheduled_messages
+		SET
+			status = 'failed',
+			last_error = $2
+		WHERE id = $1
+	`
+
+	_, err := s.db.Exec(ctx, query, scheduledID, reason)
+
+	return err
+}
+
+func scanScheduled(rows pgx.Rows) ([]schedule.Message, error) {
+	defer rows.Close()
+
+	messages := make([]schedule.Message, 0)
+
+	for rows.Next() {
+		msg := schedule.Message{}
+
+		var status string
+
+		err := rows.Scan(
+			&msg.ID,
+			&msg.ChatID,
+			&msg.UserID,
+			&msg.Text,
+			&msg.SendAt,
+			&status,
+			&msg.LastError,
+			&msg.MessageID,
+			&msg.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.Status = schedule.Status(status)
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
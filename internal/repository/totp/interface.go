@@ -0,0 +1,19 @@
+package totp
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/totp"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Get(ctx context.Context, userID int64) (totp.Enrollment, error)
+	SavePending(ctx context.Context, userID int64, secret string) error
+	Confirm(ctx context.Context, userID int64, step int64) error
+	UseStep(ctx context.Context, userID int64, step int64) (bool, error)
+	Delete(ctx context.Context, userID int64) error
+	SaveRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+}
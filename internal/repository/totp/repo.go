@@ -0,0 +1,165 @@
+package totp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/totp"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type totpRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewTotpRepo(db postgres.QueryExecer) Repo {
+	return &totpRepo{
+		db: db,
+	}
+}
+
+func (t *totpRepo) WithTx(tx postgres.Tx) Repo {
+	return &totpRepo{
+		db: tx,
+	}
+}
+
+func (t *totpRepo) Get(ctx context.Context, userID int64) (totp.Enrollment, error) {
+	const query = `
+		SELECT
+			user_id,
+			secret,
+			confirmed_at IS NOT NULL,
+			last_used_step
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	enrollment := totp.Enrollment{}
+
+	err := t.db.QueryRow(ctx, query, userID).Scan(&enrollment.UserID, &enrollment.Secret, &enrollment.Confirmed, &enrollment.LastUsedStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return totp.Enrollment{}, totp.ErrNotEnrolled
+		}
+
+		return totp.Enrollment{}, err
+	}
+
+	return enrollment, nil
+}
+
+func (t *totpRepo) SavePending(ctx context.Context, userID int64, secret string) error {
+	const query = `
+		INSERT INTO user_totp(user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			last_used_step = 0,
+			created_at = now()
+		WHERE user_totp.confirmed_at IS NULL
+	`
+
+	res, err := t.db.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return totp.ErrAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (t *totpRepo) Confirm(ctx context.Context, userID int64, step int64) error {
+	const query = `
+		UPDATE user_totp
+		SET
+			confirmed_at = now(),
+			last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+
+	res, err := t.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return totp.ErrAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (t *totpRepo) UseStep(ctx context.Context, userID int64, step int64) (bool, error) {
+	const query = `
+		UPDATE user_totp
+		SET last_used_step = $2
+		WHERE user_id = $1 AND last_used_step < $2
+	`
+
+	res, err := t.db.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func (t *totpRepo) Delete(ctx context.Context, userID int64) error {
+	queries := []string{
+		`DELETE FROM totp_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_totp WHERE user_id = $1`,
+	}
+
+	for _, query := range queries {
+		_, err := t.db.Exec(ctx, query, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *totpRepo) SaveRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	const deleteQuery = `
+		DELETE FROM totp_recovery_codes
+		WHERE user_id = $1
+	`
+
+	const insertQuery = `
+		INSERT INTO totp_recovery_codes(user_id, code_hash)
+		SELECT $1, UNNEST($2::TEXT[])
+	`
+
+	_, err := t.db.Exec(ctx, deleteQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.db.Exec(ctx, insertQuery, userID, codeHashes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *totpRepo) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	const query = `
+		UPDATE totp_recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	res, err := t.db.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
@@ -0,0 +1,16 @@
+package user
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	GetProfile(ctx context.Context, userID int64) (user.Profile, error)
+	GetProfiles(ctx context.Context, userIDs []int64) ([]user.Profile, error)
+	SearchByLoginPrefix(ctx context.Context, prefix string, limit int) ([]user.Profile, error)
+	UpdateProfile(ctx context.Context, profile user.Profile) (user.Profile, error)
+}
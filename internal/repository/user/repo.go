@@ -0,0 +1,141 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type userRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewUserRepo(db postgres.QueryExecer) Repo {
+	return &userRepo{
+		db: db,
+	}
+}
+
+func (u *userRepo) WithTx(tx postgres.Tx) Repo {
+	return &userRepo{
+		db: tx,
+	}
+}
+
+func (u *userRepo) GetProfile(ctx context.Context, userID int64) (user.Profile, error) {
+	const query = `
+		SELECT
+			id,
+			login,
+			display_name,
+			avatar_ref,
+			bio
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	profile := user.Profile{}
+
+	err := u.db.QueryRow(ctx, query, userID).Scan(&profile.UserID, &profile.Login, &profile.DisplayName, &profile.AvatarRef, &profile.Bio)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.Profile{}, domain.ErrNotFound
+		}
+
+		return user.Profile{}, err
+	}
+
+	return profile, nil
+}
+
+func (u *userRepo) GetProfiles(ctx context.Context, userIDs []int64) ([]user.Profile, error) {
+	const query = `
+		SELECT
+			id,
+			login,
+			display_name,
+			avatar_ref,
+			bio
+		FROM users
+		WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+	`
+
+	rows, err := u.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanProfiles(rows)
+}
+
+func (u *userRepo) SearchByLoginPrefix(ctx context.Context, prefix string, limit int) ([]user.Profile, error) {
+	const query = `
+		SELECT
+			id,
+			login,
+			display_name,
+			avatar_ref,
+			bio
+		FROM users
+		WHERE login LIKE $1 AND deleted_at IS NULL
+		ORDER BY login
+		LIMIT $2
+	`
+
+	rows, err := u.db.Query(ctx, query, likeEscaper.Replace(prefix)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanProfiles(rows)
+}
+
+func (u *userRepo) UpdateProfile(ctx context.Context, profile user.Profile) (user.Profile, error) {
+	const query = `
+		UPDATE users
+		SET
+			display_name = $2,
+			avatar_ref = $3,
+			bio = $4
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING login
+	`
+
+	err := u.db.QueryRow(ctx, query, profile.UserID, profile.DisplayName, profile.AvatarRef, profile.Bio).Scan(&profile.Login)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.Profile{}, domain.ErrNotFound
+		}
+
+		return user.Profile{}, err
+	}
+
+	return profile, nil
+}
+
+func scanProfiles(rows pgx.Rows) ([]user.Profile, error) {
+	defer rows.Close()
+
+	profiles := make([]user.Profile, 0)
+
+	for rows.Next() {
+		profile := user.Profile{}
+
+		err := rows.Scan(&profile.UserID, &profile.Login, &profile.DisplayName, &profile.AvatarRef, &profile.Bio)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+type Repo interface {
+	WithTx(tx postgres.Tx) Repo
+	Create(ctx context.Context, hook webhook.Webhook) (webhook.Webhook, error)
+	Count(ctx context.Context, chatID int64) (int, error)
+	GetByChat(ctx context.Context, chatID int64) ([]webhook.Webhook, error)
+	GetSubscribed(ctx context.Context, chatID int64, eventType event.Type) ([]webhook.Webhook, error)
+	Delete(ctx context.Context, chatID, webhookID int64) error
+	Enable(ctx context.Context, chatID, webhookID int64) error
+	Enqueue(ctx context.Context, deliveries []webhook.Delivery) error
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]webhook.Delivery, error)
+	MarkDelivered(ctx context.Context, deliveryID int64, statusCode int) error
+	MarkFailed(ctx context.Context, deliveryID int64, statusCode int, reason string, nextAttemptAt time.Time, final bool) error
+	RegisterFailure(ctx context.Context, webhookID int64, disableAfter int) error
+	ResetFailures(ctx context.Context, webhookID int64) error
+	GetDeliveries(ctx context.Context, chatID, webhookID int64, limit int) ([]webhook.Delivery, error)
+}
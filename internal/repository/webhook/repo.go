@@ -0,0 +1,420 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+const webhookColumns = `
+	id,
+	chat_id,
+	url,
+	secret,
+	events,
+	created_by,
+	consecutive_failures,
+	disabled_at,
+	disabled_reason,
+	created_at
+`
+
+type webhookRepo struct {
+	db postgres.QueryExecer
+}
+
+func NewWebhookRepo(db postgres.QueryExecer) Repo {
+	return &webhookRepo{
+		db: db,
+	}
+}
+
+func (w *webhookRepo) WithTx(tx postgres.Tx) Repo {
+	return &webhookRepo{
+		db: tx,
+	}
+}
+
+func (w *webhookRepo) Create(ctx context.Context, hook webhook.Webhook) (webhook.Webhook, error) {
+	const query = `
+		INSERT INTO webhooks(chat_id, url, secret, events, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := w.db.QueryRow(ctx, query, hook.ChatID, hook.URL, hook.Secret, eventStrings(hook.Events), hook.CreatedBy).Scan(&hook.ID, &hook.CreatedAt)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	return hook, nil
+}
+
+func (w *webhookRepo) Count(ctx context.Context, chatID int64) (int, error) {
+	const query = `
+		SELECT count(*)
+		FROM webhooks
+		WHERE chat_id = $1
+	`
+
+	var count int
+
+	err := w.db.QueryRow(ctx, query, chatID).Scan(&count)
+
+	return count, err
+}
+
+func (w *webhookRepo) GetByChat(ctx context.Context, chatID int64) ([]webhook.Webhook, error) {
+	const query = `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE chat_id = $1
+		ORDER BY id
+	`
+
+	rows, err := w.db.Query(ctx, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhooks(rows)
+}
+
+func (w *webhookRepo) GetSubscribed(ctx context.Context, chatID int64, eventType event.Type) ([]webhook.Webhook, error) {
+	const query = `SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE chat_id = $1 AND $2 = ANY(events) AND disabled_at IS NULL
+		ORDER BY id
+	`
+
+	rows, err := w.db.Query(ctx, query, chatID, string(eventType))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhooks(rows)
+}
+
+func (w *webhookRepo) Delete(ctx context.Context, chatID, webhookID int64) error {
+	const query = `
+		DELETE FROM webhooks
+		WHERE id = $1 AND chat_id = $2
+	`
+
+	res, err := w.db.Exec(ctx, query, webhookID, chatID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+
+	return nil
+}
+
+func (w *webhookRepo) Enable(ctx context.Context, chatID, webhookID int64) error {
+	const query = `
+		UPDATE webhooks
+		SET
+			disabled_at = NULL,
+			disabled_reason = '',
+			consecutive_failures = 0
+		WHERE id = $1 AND chat_id = $2
+	`
+
+	res, err := w.db.Exec(ctx, query, webhookID, chatID)
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+
+	return nil
+}
+
+func (w *webhookRepo) Enqueue(ctx context.Context, deliveries []webhook.Delivery) error {
+	const query = `
+		INSERT INTO webhook_deliveries(webhook_id, event_id, event_type, payload)
+		SELECT * FROM unnest($1::BIGINT[], $2::TEXT[], $3::TEXT[], $4::TEXT[])
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	var (
+		webhookIDs = make([]int64, 0, len(deliveries))
+		eventIDs   = make([]string, 0, len(deliveries))
+		eventTypes = make([]string, 0, len(deliveries))
+		payloads   = make([]string, 0, len(deliveries))
+	)
+
+	for _, delivery := range deliveries {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+		eventIDs = append(eventIDs, delivery.EventID)
+		eventTypes = append(eventTypes, delivery.EventType)
+		payloads = append(payloads, string(delivery.Payload))
+	}
+
+	_, err := w.db.Exec(ctx, query, webhookIDs, eventIDs, eventTypes, payloads)
+
+	return err
+}
+
+// ClaimDue leases due deliveries so concurrent workers on other replicas skip them.
+func (w *webhookRepo) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	const query = `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending'
+				AND d.next_attempt_at <= $1
+				AND (d.locked_until IS NULL OR d.locked_until <= $1)
+				AND w.disabled_at IS NULL
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET locked_until = $2
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING
+			d.id,
+			d.webhook_id,
+			d.event_id,
+			d.event_type,
+			d.payload,
+			d.attempts,
+			d.created_at,
+			w.url,
+			w.secret
+	`
+
+	rows, err := w.db.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhook.Delivery, 0)
+
+	for rows.Next() {
+		delivery := webhook.Delivery{
+			Status: webhook.StatusPending,
+		}
+
+		var payload string
+
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Attempts,
+			&delivery.CreatedAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.Payload = []byte(payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (w *webhookRepo) MarkDelivered(ctx context.Context, deliveryID int64, statusCode int) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET
+			status = 'delivered',
+			attempts = attempts + 1,
+			last_status_code = $2,
+			last_error = '',
+			locked_until = NULL,
+			delivered_at = now()
+		WHERE id = $1
+	`
+
+	_, err := w.db.Exec(ctx, query, deliveryID, statusCode)
+
+	return err
+}
+
+func (w *webhookRepo) MarkFailed(ctx context.Context, deliveryID int64, statusCode int, reason string, nextAttemptAt time.Time, final bool) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET
+			status = CASE WHEN $5 THEN 'failed' ELSE 'pending' END,
+			attempts = attempts + 1,
+			last_status_code = $2,
+			last_error = $3,
+			next_attempt_at = $4,
+			locked_until = NULL
+		WHERE id = $1
+	`
+
+	_, err := w.db.Exec(ctx, query, deliveryID, statusCode, reason, nextAttemptAt, final)
+
+	return err
+}
+
+func (w *webhookRepo) RegisterFailure(ctx context.Context, webhookID int64, disableAfter int) error {
+	const query = `
+		UPDATE webhooks
+		SET
+			consecutive_failures = consecutive_failures + 1,
+			disabled_at = CASE
+				WHEN disabled_at IS NULL AND consecutive_failures + 1 >= $2 THEN now()
+				ELSE disabled_at
+			END,
+			disabled_reason = CASE
+				WHEN disabled_at IS NULL AND consecutive_failures + 1 >= $2 THEN 'too many failed deliveries'
+				ELSE disabled_reason
+			END
+		WHERE id = $1
+	`
+
+	_, err := w.db.Exec(ctx, query, webhookID, disableAfter)
+
+	return err
+}
+
+func (w *webhookRepo) ResetFailures(ctx context.Context, webhookID int64) error {
+	const query = `
+		UPDATE webhooks
+		SET consecutive_failures = 0
+		WHERE id = $1 AND consecutive_failures > 0
+	`
+
+	_, err := w.db.Exec(ctx, query, webhookID)
+
+	return err
+}
+
+func (w *webhookRepo) GetDeliveries(ctx context.Context, chatID, webhookID int64, limit int) ([]webhook.Delivery, error) {
+	const query = `
+		SELECT
+			d.id,
+			d.webhook_id,
+			d.event_id,
+			d.event_type,
+			d.status,
+			d.attempts,
+			d.next_attempt_at,
+			d.last_status_code,
+			d.last_error,
+			d.created_at,
+			d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.webhook_id = $2 AND w.chat_id = $1
+		ORDER BY d.id DESC
+		LIMIT $3
+	`
+
+	rows, err := w.db.Query(ctx, query, chatID, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]webhook.Delivery, 0)
+
+	for rows.Next() {
+		delivery := webhook.Delivery{}
+
+		var (
+			status      string
+			deliveredAt *time.Time
+		)
+
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.Status = webhook.Status(status)
+
+		if deliveredAt != nil {
+			delivery.DeliveredAt = *deliveredAt
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func scanWebhooks(rows pgx.Rows) ([]webhook.Webhook, error) {
+	defer rows.Close()
+
+	hooks := make([]webhook.Webhook, 0)
+
+	for rows.Next() {
+		hook := webhook.Webhook{}
+
+		var (
+			events     []string
+			disabledAt *time.Time
+		)
+
+		err := rows.Scan(
+			&hook.ID,
+			&hook.ChatID,
+			&hook.URL,
+			&hook.Secret,
+			&events,
+			&hook.CreatedBy,
+			&hook.ConsecutiveFailures,
+			&disabledAt,
+			&hook.DisabledReason,
+			&hook.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range events {
+			hook.Events = append(hook.Events, event.Type(item))
+		}
+
+		if disabledAt != nil {
+			hook.DisabledAt = *disabledAt
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	return hooks, rows.Err()
+}
+
+func eventStrings(events []event.Type) []string {
+	res := make([]string, 0, len(events))
+	for _, item := range events {
+		res = append(res, string(item))
+	}
+
+	return res
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/credentials"
+	"github.com/monobearotaku/online-chat-api/internal/domain/identity"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+)
+
+const loginAttempts = 5
+
+// SignInExternal issues a token for an identity verified by an external provider, creating and linking
+// a local account on first login. Accounts with TOTP enabled get a challenge for VerifyTotp instead.
+func (s *authService) SignInExternal(ctx context.Context, external identity.External) (token.SignIn, error) {
+	usr, err := s.linkedUser(ctx, external)
+	if errors.Is(err, identity.ErrNotFound) {
+		usr, err = s.createExternalUser(ctx, external)
+		if errors.Is(err, identity.ErrAlreadyLinked) {
+			usr, err = s.linkedUser(ctx, external)
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return token.SignIn{}, err
+		}
+
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignInExternal: %w", err)
+	}
+
+	challenge, err := s.secondFactorChallenge(ctx, usr)
+	if err != nil {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignInExternal: %w", err)
+	}
+
+	if challenge != "" {
+		return token.SignIn{
+			Challenge: challenge,
+		}, nil
+	}
+
+	newToken, err := s.createToken(ctx, usr)
+	if err != nil {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignInExternal creating token: %w", err)
+	}
+
+	return token.SignIn{
+		Token: newToken,
+	}, nil
+}
+
+// ReauthenticateExternal issues a short-lived token confirming the user of an already linked identity, which
+// accounts created through a provider use in place of a password to change credentials or delete the account.
+func (s *authService) ReauthenticateExternal(ctx context.Context, external identity.External) (token.Token, error) {
+	usr, err := s.linkedUser(ctx, external)
+	if err != nil {
+		if errors.Is(err, identity.ErrNotFound) || errors.Is(err, domain.ErrNotFound) {
+			return "", err
+		}
+
+		return "", fmt.Errorf("Auth.Service.ReauthenticateExternal: %w", err)
+	}
+
+	reauth, err := s.tokenizer.CreateToken(ctx, token.AudienceReauth, strconv.FormatInt(usr.ID, 10), data.TokenData{
+		data.VersionKey: strconv.FormatInt(usr.TokenVersion, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Auth.Service.ReauthenticateExternal creating token: %w", err)
+	}
+
+	return reauth, nil
+}
+
+func (s *authService) linkedUser(ctx context.Context, external identity.External) (user.User, error) {
+	linked, err := s.identity.Get(ctx, external.Provider, external.Subject)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return s.auth.GetUserById(ctx, linked.UserID)
+}
+
+func (s *authService) createExternalUser(ctx context.Context, external identity.External) (usr user.User, err error) {
+	tx, err := s.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return user.User{}, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	authRepo := s.auth.WithTx(tx)
+
+	for attempt := 0; attempt < loginAttempts; attempt++ {
+		suffix := ""
+		if attempt > 0 {
+			suffix, err = loginSuffix()
+			if err != nil {
+				return user.User{}, err
+			}
+		}
+
+		login := external.Login(suffix)
+
+		// External accounts get no password hash, so password sign in never matches them.
+		err = authRepo.CreateUser(ctx, credentials.Credentials{Login: login})
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			continue
+		}
+
+		if err != nil {
+			return user.User{}, fmt.Errorf("creating user: %w", err)
+		}
+
+		usr, err = authRepo.GetUser(ctx, login)
+		if err != nil {
+			return user.User{}, fmt.Errorf("getting created user: %w", err)
+		}
+
+		break
+	}
+
+	if usr.ID == 0 {
+		return user.User{}, fmt.Errorf("no free login for %q: %w", external.Login(""), domain.ErrAlreadyExists)
+	}
+
+	err = s.identity.WithTx(tx).Create(ctx, identity.Identity{
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		UserID:   usr.ID,
+		Email:    external.Email,
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	return usr, nil
+}
+
+func loginSuffix() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%04d", n.Int64()), nil
+}
@@ -3,11 +3,24 @@ package auth
 import (
 	"context"
 
+	"github.com/monobearotaku/online-chat-api/internal/domain"
 	"github.com/monobearotaku/online-chat-api/internal/domain/credentials"
+	"github.com/monobearotaku/online-chat-api/internal/domain/identity"
 	"github.com/monobearotaku/online-chat-api/internal/domain/token"
 )
 
 type Service interface {
-	SignIn(ctx context.Context, cred credentials.Credentials) (token.Token, error)
+	SignIn(ctx context.Context, cred credentials.Credentials, clientAddr string) (token.SignIn, error)
 	SignUp(ctx context.Context, cred credentials.Credentials) (token.Token, error)
+	// ChangePassword, DeleteAccount and DisableTotp confirm the caller with the current password or, for accounts
+	// that sign in through a provider, a reauthentication token from ReauthenticateExternal.
+	ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword domain.Password, reauth token.Token) (token.Token, error)
+	DeleteAccount(ctx context.Context, userID int64, password domain.Password, reauth token.Token) error
+	CheckTokenVersion(ctx context.Context, userID int64, version int64) error
+	EnrollTotp(ctx context.Context, userID int64) (provisioningURI string, secret string, err error)
+	ConfirmTotp(ctx context.Context, userID int64, code string) ([]string, error)
+	VerifyTotp(ctx context.Context, challenge token.Token, code string) (token.Token, error)
+	DisableTotp(ctx context.Context, userID int64, password domain.Password, reauth token.Token) error
+	SignInExternal(ctx context.Context, external identity.External) (token.SignIn, error)
+	ReauthenticateExternal(ctx context.Context, external identity.External) (token.Token, error)
 }
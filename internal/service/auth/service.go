@@ -6,27 +6,64 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/jackc/pgx/v5"
 	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
 	"github.com/monobearotaku/online-chat-api/internal/domain/credentials"
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
 	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
+	"github.com/monobearotaku/online-chat-api/internal/domain/totp"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
 	"github.com/monobearotaku/online-chat-api/internal/postgres"
 	"github.com/monobearotaku/online-chat-api/internal/repository/auth"
+	"github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	identityRepo "github.com/monobearotaku/online-chat-api/internal/repository/identity"
+	totpRepo "github.com/monobearotaku/online-chat-api/internal/repository/totp"
+	"github.com/monobearotaku/online-chat-api/internal/service/hasher"
+	lockoutService "github.com/monobearotaku/online-chat-api/internal/service/lockout"
 	"github.com/monobearotaku/online-chat-api/internal/service/tokenizer"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type authService struct {
 	auth       auth.Repo
+	chat       chat.Repo
 	tokenizer  tokenizer.Tokenizer
+	lockout    lockoutService.Service
+	hasher     hasher.PasswordHasher
+	totp       totpRepo.Repo
+	identity   identityRepo.Repo
 	txBeginner postgres.TxBeginner
+
+	messagesPolicy user.MessagesPolicy
+	logger         log.Logger
+
+	dummyHash domain.Password
 }
 
-func NewAuthService(auth auth.Repo, tokenizer tokenizer.Tokenizer, txBeginner postgres.TxBeginner) Service {
+// dummyPassword only ever gets hashed once at start up; no account can sign in with its hash.
+const dummyPassword domain.Password = "dummy password for unknown logins"
+
+func NewAuthService(auth auth.Repo, chat chat.Repo, tokenizer tokenizer.Tokenizer, lockout lockoutService.Service, hasher hasher.PasswordHasher, totp totpRepo.Repo, identity identityRepo.Repo, txBeginner postgres.TxBeginner, messagesPolicy user.MessagesPolicy, logger log.Logger) Service {
+	dummyHash, err := hasher.Hash(dummyPassword)
+	if err != nil {
+		level.Error(logger).Log("error", fmt.Errorf("Auth.Service hashing dummy password: %w", err))
+	}
+
 	return &authService{
-		auth:       auth,
-		tokenizer:  tokenizer,
-		txBeginner: txBeginner,
+		auth:           auth,
+		chat:           chat,
+		tokenizer:      tokenizer,
+		lockout:        lockout,
+		hasher:         hasher,
+		totp:           totp,
+		identity:       identity,
+		txBeginner:     txBeginner,
+		messagesPolicy: messagesPolicy,
+		logger:         logger,
+		dummyHash:      dummyHash,
 	}
 }
 
@@ -64,10 +101,7 @@ func (s *authService) SignUp(ctx context.Context, cred credentials.Credentials)
 		return "", fmt.Errorf("Auth.Service.SignUp geting user: %w", err)
 	}
 
-	newToken, err = s.tokenizer.CreateToken(ctx, map[string]string{
-		"userID": strconv.FormatInt(usr.ID, 10),
-		"login":  usr.Login.String(),
-	})
+	newToken, err = s.createToken(ctx, usr)
 
 	if err != nil {
 		return "", fmt.Errorf("Auth.Service.SignUp creating token: %w", err)
@@ -76,40 +110,319 @@ func (s *authService) SignUp(ctx context.Context, cred credentials.Credentials)
 	return newToken, nil
 }
 
-func (s *authService) SignIn(ctx context.Context, cred credentials.Credentials) (newToken token.Token, err error) {
+func (s *authService) SignIn(ctx context.Context, cred credentials.Credentials, clientAddr string) (token.SignIn, error) {
+	loginKey := lockout.LoginKey(cred.Login)
+
+	keys := []lockout.Key{loginKey}
+	if clientAddr != "" {
+		keys = append(keys, lockout.AddressKey(clientAddr))
+	}
+
+	err := s.lockout.Reserve(ctx, keys...)
+	if err != nil {
+		return token.SignIn{}, err
+	}
+
 	usr, err := s.auth.GetUser(ctx, cred.Login)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignIn getting user data: %w", err)
+	}
+
+	// An unknown login is verified against the dummy hash, so it takes as long to refuse as a wrong password.
+	found := err == nil
+
+	hash := s.dummyHash
+	if found {
+		hash = usr.PasswordHash
+	}
+
+	ok, rehash := s.hasher.Verify(cred.Password, hash)
+	if !found || !ok {
+		return token.SignIn{}, credentials.ErrWrongCreds
+	}
+
+	err = s.lockout.Release(ctx, keys...)
+	if err != nil {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignIn releasing attempt: %w", err)
+	}
+
+	// The stored hash still verifies, so a failed upgrade only means trying again at the next sign in.
+	if rehash {
+		err = s.rehashPassword(ctx, usr, cred.Password)
+		if err != nil {
+			level.Error(s.logger).Log("error", fmt.Errorf("Auth.Service.SignIn rehashing password: %w", err))
+		}
+	}
+
+	challenge, err := s.secondFactorChallenge(ctx, usr)
+	if err != nil {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignIn: %w", err)
+	}
+
+	if challenge != "" {
+		return token.SignIn{
+			Challenge: challenge,
+		}, nil
+	}
+
+	err = s.lockout.RegisterSuccess(ctx, loginKey)
+	if err != nil {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignIn registering success: %w", err)
+	}
+
+	newToken, err := s.createToken(ctx, usr)
+	if err != nil {
+		return token.SignIn{}, fmt.Errorf("Auth.Service.SignIn creating token: %w", err)
+	}
+
+	return token.SignIn{
+		Token: newToken,
+	}, nil
+}
+
+func (s *authService) ChangePassword(ctx context.Context, userID int64, oldPassword, newPassword domain.Password, reauth token.Token) (token.Token, error) {
+	err := newPassword.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	usr, err := s.auth.GetUserById(ctx, userID)
 	if err != nil {
-		return "", fmt.Errorf("Auth.Service.SignIn getting user data: %w", err)
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", err
+		}
+
+		return "", fmt.Errorf("Auth.Service.ChangePassword getting user: %w", err)
 	}
 
-	if !s.comparePassword(cred.Password, usr.PasswordHash) {
-		return "", credentials.ErrWrongCreds
+	err = s.confirmUser(ctx, usr, oldPassword, reauth)
+	if err != nil {
+		return "", err
 	}
 
-	newToken, err = s.tokenizer.CreateToken(ctx, map[string]string{
-		"userID": strconv.FormatInt(usr.ID, 10),
-		"login":  usr.Login.String(),
+	cred, err := s.securePassword(credentials.Credentials{
+		Login:    usr.Login,
+		Password: newPassword,
 	})
+	if err != nil {
+		return "", fmt.Errorf("Auth.Service.ChangePassword hashing pass: %w", err)
+	}
 
+	err = s.auth.UpdatePassword(ctx, usr.ID, cred.Password)
 	if err != nil {
-		return "", fmt.Errorf("Auth.Service.SignUp creating token: %w", err)
+		return "", fmt.Errorf("Auth.Service.ChangePassword updating password: %w", err)
+	}
+
+	usr.TokenVersion++
+
+	newToken, err := s.createToken(ctx, usr)
+	if err != nil {
+		return "", fmt.Errorf("Auth.Service.ChangePassword creating token: %w", err)
 	}
 
 	return newToken, nil
 }
 
+func (s *authService) DeleteAccount(ctx context.Context, userID int64, password domain.Password, reauth token.Token) (err error) {
+	usr, err := s.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Auth.Service.DeleteAccount getting user: %w", err)
+	}
+
+	err = s.confirmUser(ctx, usr, password, reauth)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Auth.Service.DeleteAccount begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	chatRepo := s.chat.WithTx(tx)
+
+	memberships, err := chatRepo.GetUserChats(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("Auth.Service.DeleteAccount getting user chats: %w", err)
+	}
+
+	for _, membership := range memberships {
+		if membership.Role == chatDomain.Owner {
+			err = s.handOverChat(ctx, chatRepo, membership.ChatID, usr.ID)
+			if err != nil {
+				return fmt.Errorf("Auth.Service.DeleteAccount handing over chat %d: %w", membership.ChatID, err)
+			}
+		}
+
+		err = chatRepo.RemoveUserFromChat(ctx, membership.ChatID, usr.ID)
+		if err != nil {
+			return fmt.Errorf("Auth.Service.DeleteAccount leaving chat %d: %w", membership.ChatID, err)
+		}
+	}
+
+	if s.messagesPolicy == user.DeleteMessages {
+		err = chatRepo.DeleteUserMessages(ctx, usr.ID)
+		if err != nil {
+			return fmt.Errorf("Auth.Service.DeleteAccount deleting messages: %w", err)
+		}
+	}
+
+	err = s.totp.WithTx(tx).Delete(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("Auth.Service.DeleteAccount deleting totp: %w", err)
+	}
+
+	err = s.identity.WithTx(tx).DeleteByUser(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("Auth.Service.DeleteAccount unlinking identities: %w", err)
+	}
+
+	err = s.auth.WithTx(tx).DeleteUser(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("Auth.Service.DeleteAccount deleting user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *authService) CheckTokenVersion(ctx context.Context, userID int64, version int64) error {
+	usr, err := s.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return token.ErrTokenRevoked
+		}
+
+		return fmt.Errorf("Auth.Service.CheckTokenVersion getting user: %w", err)
+	}
+
+	if usr.TokenVersion != version {
+		return token.ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// handOverChat passes ownership to the next owner or the oldest member, dissolving the chat when nobody is left.
+func (s *authService) handOverChat(ctx context.Context, chatRepo chat.Repo, chatID, ownerID int64) error {
+	chtUsers, err := chatRepo.GetChatUsers(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	var successor *chatDomain.ChatUser
+
+	for i, item := range chtUsers.Users {
+		if item.UserID == ownerID {
+			continue
+		}
+
+		if item.Role == chatDomain.Owner {
+			return nil
+		}
+
+		if successor == nil {
+			successor = &chtUsers.Users[i]
+		}
+	}
+
+	if successor == nil {
+		return chatRepo.DeleteChat(ctx, chatID)
+	}
+
+	return chatRepo.SetUserRole(ctx, chatID, successor.UserID, chatDomain.Owner)
+}
+
+// secondFactorChallenge returns a challenge token to exchange in VerifyTotp when the user has TOTP enabled,
+// and an empty token otherwise.
+func (s *authService) secondFactorChallenge(ctx context.Context, usr user.User) (token.Token, error) {
+	enrollment, err := s.totp.Get(ctx, usr.ID)
+	if err != nil {
+		if errors.Is(err, totp.ErrNotEnrolled) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("getting totp enrollment: %w", err)
+	}
+
+	if !enrollment.Confirmed {
+		return "", nil
+	}
+
+	challenge, err := s.tokenizer.CreateToken(ctx, token.AudienceChallenge, strconv.FormatInt(usr.ID, 10), data.TokenData{
+		data.VersionKey: strconv.FormatInt(usr.TokenVersion, 10),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+func (s *authService) createToken(ctx context.Context, usr user.User) (token.Token, error) {
+	return s.tokenizer.CreateToken(ctx, token.AudienceAuth, strconv.FormatInt(usr.ID, 10), data.TokenData{
+		data.LoginKey:   usr.Login.String(),
+		data.VersionKey: strconv.FormatInt(usr.TokenVersion, 10),
+	})
+}
+
+func (s *authService) rehashPassword(ctx context.Context, usr user.User, password domain.Password) error {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return s.auth.RehashPassword(ctx, usr.ID, usr.PasswordHash, hash)
+}
+
 func (s *authService) securePassword(cred credentials.Credentials) (credentials.Credentials, error) {
-	bytes, err := bcrypt.GenerateFromPassword(cred.Password.Bytes(), 14)
+	hash, err := s.hasher.Hash(cred.Password)
 	if err != nil {
 		return cred, err
 	}
 
 	return credentials.Credentials{
 		Login:    cred.Login,
-		Password: domain.Password(bytes),
+		Password: hash,
 	}, nil
 }
 
+// confirmUser checks the password or, when one is given, a reauthentication token issued to the user
+// since their last password change.
+func (s *authService) confirmUser(ctx context.Context, usr user.User, password domain.Password, reauth token.Token) error {
+	if reauth == "" {
+		if !s.comparePassword(password, usr.PasswordHash) {
+			return credentials.ErrWrongCreds
+		}
+
+		return nil
+	}
+
+	tokenData, err := s.tokenizer.ValidateAndExtractData(ctx, reauth, token.AudienceReauth)
+	if err != nil {
+		return err
+	}
+
+	if tokenData.Subject() != strconv.FormatInt(usr.ID, 10) || tokenData[data.VersionKey] != strconv.FormatInt(usr.TokenVersion, 10) {
+		return token.ErrInvalidToken
+	}
+
+	return nil
+}
+
 func (s *authService) comparePassword(password, hash domain.Password) bool {
-	return bcrypt.CompareHashAndPassword(hash.Bytes(), password.Bytes()) == nil
+	ok, _ := s.hasher.Verify(password, hash)
+	return ok
 }
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
+	"github.com/monobearotaku/online-chat-api/internal/domain/totp"
+	totpCodes "github.com/monobearotaku/online-chat-api/internal/pkg/totp"
+)
+
+const (
+	totpIssuer        = "online-chat-api"
+	totpSkew          = 1
+	recoveryCodeBytes = 5
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (s *authService) EnrollTotp(ctx context.Context, userID int64) (string, string, error) {
+	usr, err := s.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", "", err
+		}
+
+		return "", "", fmt.Errorf("Auth.Service.EnrollTotp getting user: %w", err)
+	}
+
+	secret, err := totpCodes.GenerateSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("Auth.Service.EnrollTotp generating secret: %w", err)
+	}
+
+	err = s.totp.SavePending(ctx, usr.ID, secret)
+	if err != nil {
+		if errors.Is(err, totp.ErrAlreadyEnabled) {
+			return "", "", err
+		}
+
+		return "", "", fmt.Errorf("Auth.Service.EnrollTotp saving secret: %w", err)
+	}
+
+	return totpCodes.ProvisioningURI(totpIssuer, usr.Login.String(), secret), secret, nil
+}
+
+func (s *authService) ConfirmTotp(ctx context.Context, userID int64, code string) (recoveryCodes []string, err error) {
+	enrollment, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, totp.ErrNotEnrolled) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("Auth.Service.ConfirmTotp getting enrollment: %w", err)
+	}
+
+	if enrollment.Confirmed {
+		return nil, totp.ErrAlreadyEnabled
+	}
+
+	step, ok := totpCodes.Validate(enrollment.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, totp.ErrInvalidCode
+	}
+
+	recoveryCodes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, fmt.Errorf("Auth.Service.ConfirmTotp generating recovery codes: %w", err)
+	}
+
+	tx, err := s.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Auth.Service.ConfirmTotp begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	err = s.totp.WithTx(tx).Confirm(ctx, userID, step)
+	if err != nil {
+		if errors.Is(err, totp.ErrAlreadyEnabled) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("Auth.Service.ConfirmTotp confirming: %w", err)
+	}
+
+	err = s.totp.WithTx(tx).SaveRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("Auth.Service.ConfirmTotp saving recovery codes: %w", err)
+	}
+
+	return recoveryCodes, nil
+}
+
+func (s *authService) VerifyTotp(ctx context.Context, challenge token.Token, code string) (token.Token, error) {
+	tokenData, err := s.tokenizer.ValidateAndExtractData(ctx, challenge, token.AudienceChallenge)
+	if err != nil {
+		return "", err
+	}
+
+	userID, err := strconv.ParseInt(tokenData.Subject(), 10, 64)
+	if err != nil {
+		return "", token.ErrInvalidToken
+	}
+
+	version, err := strconv.ParseInt(tokenData[data.VersionKey], 10, 64)
+	if err != nil {
+		return "", token.ErrInvalidToken
+	}
+
+	usr, err := s.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", token.ErrTokenRevoked
+		}
+
+		return "", fmt.Errorf("Auth.Service.VerifyTotp getting user: %w", err)
+	}
+
+	if usr.TokenVersion != version {
+		return "", token.ErrTokenRevoked
+	}
+
+	loginKey := lockout.LoginKey(usr.Login)
+
+	err = s.lockout.Reserve(ctx, loginKey)
+	if err != nil {
+		return "", err
+	}
+
+	ok, err := s.verifySecondFactor(ctx, usr.ID, code)
+	if err != nil {
+		return "", fmt.Errorf("Auth.Service.VerifyTotp verifying code: %w", err)
+	}
+
+	if !ok {
+		return "", totp.ErrWrongSecondStep
+	}
+
+	err = s.lockout.RegisterSuccess(ctx, loginKey)
+	if err != nil {
+		return "", fmt.Errorf("Auth.Service.VerifyTotp registering success: %w", err)
+	}
+
+	newToken, err := s.createToken(ctx, usr)
+	if err != nil {
+		return "", fmt.Errorf("Auth.Service.VerifyTotp creating token: %w", err)
+	}
+
+	return newToken, nil
+}
+
+func (s *authService) DisableTotp(ctx context.Context, userID int64, password domain.Password, reauth token.Token) error {
+	usr, err := s.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Auth.Service.DisableTotp getting user: %w", err)
+	}
+
+	err = s.confirmUser(ctx, usr, password, reauth)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.totp.Get(ctx, usr.ID)
+	if err != nil {
+		if errors.Is(err, totp.ErrNotEnrolled) {
+			return err
+		}
+
+		return fmt.Errorf("Auth.Service.DisableTotp getting enrollment: %w", err)
+	}
+
+	err = s.totp.Delete(ctx, usr.ID)
+	if err != nil {
+		return fmt.Errorf("Auth.Service.DisableTotp deleting enrollment: %w", err)
+	}
+
+	return nil
+}
+
+// verifySecondFactor accepts either a fresh TOTP code or an unused recovery code.
+func (s *authService) verifySecondFactor(ctx context.Context, userID int64, code string) (bool, error) {
+	enrollment, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, totp.ErrNotEnrolled) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if !enrollment.Confirmed {
+		return false, nil
+	}
+
+	if step, ok := totpCodes.Validate(enrollment.Secret, code, time.Now(), totpSkew); ok {
+		return s.totp.UseStep(ctx, userID, step)
+	}
+
+	return s.totp.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, totp.RecoveryCodesCount)
+	hashes := make([]string, 0, totp.RecoveryCodesCount)
+
+	for i := 0; i < totp.RecoveryCodesCount; i++ {
+		raw := make([]byte, recoveryCodeBytes)
+
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encoded := strings.ToLower(recoveryEncoding.EncodeToString(raw))
+		code := encoded[:4] + "-" + encoded[4:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,19 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/bot"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+)
+
+type Service interface {
+	CreateBot(ctx context.Context, ownerID int64, login domain.Login) (bot.Bot, error)
+	GetBots(ctx context.Context, ownerID int64) ([]bot.Bot, error)
+	CreateAPIKey(ctx context.Context, ownerID, botID int64, name string, scopes []apikey.Scope) (apikey.APIKey, string, error)
+	GetAPIKeys(ctx context.Context, ownerID, botID int64) ([]apikey.APIKey, error)
+	RevokeAPIKey(ctx context.Context, ownerID, botID, keyID int64) error
+	AuthenticateAPIKey(ctx context.Context, key string) (principal.Principal, []apikey.Scope, error)
+}
@@ -0,0 +1,245 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/apikey"
+	"github.com/monobearotaku/online-chat-api/internal/domain/bot"
+	"github.com/monobearotaku/online-chat-api/internal/domain/principal"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	"github.com/monobearotaku/online-chat-api/internal/repository/auth"
+	botRepo "github.com/monobearotaku/online-chat-api/internal/repository/bot"
+)
+
+const (
+	prefixBytes = 8
+	secretBytes = 32
+)
+
+type botService struct {
+	bots       botRepo.Repo
+	auth       auth.Repo
+	txBeginner postgres.TxBeginner
+}
+
+func NewBotService(bots botRepo.Repo, auth auth.Repo, txBeginner postgres.TxBeginner) Service {
+	return &botService{
+		bots:       bots,
+		auth:       auth,
+		txBeginner: txBeginner,
+	}
+}
+
+func (b *botService) CreateBot(ctx context.Context, ownerID int64, login domain.Login) (bot.Bot, error) {
+	err := login.Validate()
+	if err != nil {
+		return bot.Bot{}, err
+	}
+
+	newBot, err := b.bots.CreateBot(ctx, ownerID, login)
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return bot.Bot{}, err
+		}
+
+		return bot.Bot{}, fmt.Errorf("Bot.Service.CreateBot creating bot: %w", err)
+	}
+
+	return newBot, nil
+}
+
+func (b *botService) GetBots(ctx context.Context, ownerID int64) ([]bot.Bot, error) {
+	bots, err := b.bots.GetOwnedBots(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("Bot.Service.GetBots getting bots: %w", err)
+	}
+
+	return bots, nil
+}
+
+func (b *botService) CreateAPIKey(ctx context.Context, ownerID, botID int64, name string, scopes []apikey.Scope) (key apikey.APIKey, token string, err error) {
+	err = apikey.ValidateName(name)
+	if err != nil {
+		return apikey.APIKey{}, "", err
+	}
+
+	if len(scopes) == 0 {
+		return apikey.APIKey{}, "", apikey.ErrNoScopes
+	}
+
+	err = b.checkOwner(ctx, ownerID, botID)
+	if err != nil {
+		return apikey.APIKey{}, "", err
+	}
+
+	prefix, err := randomString(prefixBytes, hex.EncodeToString)
+	if err != nil {
+		return apikey.APIKey{}, "", fmt.Errorf("Bot.Service.CreateAPIKey generating prefix: %w", err)
+	}
+
+	secret, err := randomString(secretBytes, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return apikey.APIKey{}, "", fmt.Errorf("Bot.Service.CreateAPIKey generating secret: %w", err)
+	}
+
+	tx, err := b.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return apikey.APIKey{}, "", fmt.Errorf("Bot.Service.CreateAPIKey begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	// The bot's row lock makes concurrent requests count keys one at a time.
+	err = b.bots.WithTx(tx).LockBot(ctx, botID)
+	if err != nil {
+		if errors.Is(err, bot.ErrNotFound) {
+			return apikey.APIKey{}, "", err
+		}
+
+		return apikey.APIKey{}, "", fmt.Errorf("Bot.Service.CreateAPIKey locking bot: %w", err)
+	}
+
+	active, err := b.bots.WithTx(tx).CountActiveKeys(ctx, botID)
+	if err != nil {
+		return apikey.APIKey{}, "", fmt.Errorf("Bot.Service.CreateAPIKey counting keys: %w", err)
+	}
+
+	if active >= apikey.MaxActiveKeys {
+		return apikey.APIKey{}, "", apikey.ErrTooManyKeys
+	}
+
+	key, err = b.bots.WithTx(tx).CreateKey(ctx, apikey.APIKey{
+		BotID:  botID,
+		Prefix: prefix,
+		Hash:   hashSecret(secret),
+		Name:   name,
+		Scopes: scopes,
+	})
+	if err != nil {
+		return apikey.APIKey{}, "", fmt.Errorf("Bot.Service.CreateAPIKey saving key: %w", err)
+	}
+
+	return key, apikey.Format(prefix, secret), nil
+}
+
+func (b *botService) GetAPIKeys(ctx context.Context, ownerID, botID int64) ([]apikey.APIKey, error) {
+	err := b.checkOwner(ctx, ownerID, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := b.bots.GetKeys(ctx, botID)
+	if err != nil {
+		return nil, fmt.Errorf("Bot.Service.GetAPIKeys getting keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (b *botService) RevokeAPIKey(ctx context.Context, ownerID, botID, keyID int64) error {
+	err := b.checkOwner(ctx, ownerID, botID)
+	if err != nil {
+		return err
+	}
+
+	err = b.bots.RevokeKey(ctx, botID, keyID)
+	if err != nil {
+		if errors.Is(err, apikey.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Bot.Service.RevokeAPIKey revoking key: %w", err)
+	}
+
+	return nil
+}
+
+func (b *botService) AuthenticateAPIKey(ctx context.Context, rawKey string) (principal.Principal, []apikey.Scope, error) {
+	prefix, secret, ok := apikey.Parse(rawKey)
+	if !ok {
+		return principal.Principal{}, nil, apikey.ErrInvalidKey
+	}
+
+	key, err := b.bots.GetKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apikey.ErrNotFound) {
+			return principal.Principal{}, nil, apikey.ErrInvalidKey
+		}
+
+		return principal.Principal{}, nil, fmt.Errorf("Bot.Service.AuthenticateAPIKey getting key: %w", err)
+	}
+
+	if key.Revoked() || subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashSecret(secret))) != 1 {
+		return principal.Principal{}, nil, apikey.ErrInvalidKey
+	}
+
+	usr, err := b.auth.GetUserById(ctx, key.BotID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return principal.Principal{}, nil, apikey.ErrInvalidKey
+		}
+
+		return principal.Principal{}, nil, fmt.Errorf("Bot.Service.AuthenticateAPIKey getting bot: %w", err)
+	}
+
+	err = b.bots.TouchKey(ctx, key.ID)
+	if err != nil {
+		return principal.Principal{}, nil, fmt.Errorf("Bot.Service.AuthenticateAPIKey touching key: %w", err)
+	}
+
+	return principal.Principal{
+		UserID:       usr.ID,
+		Login:        usr.Login.String(),
+		Bot:          true,
+		TokenVersion: usr.TokenVersion,
+	}, key.Scopes, nil
+}
+
+func (b *botService) checkOwner(ctx context.Context, ownerID, botID int64) error {
+	found, err := b.bots.GetBot(ctx, botID)
+	if err != nil {
+		if errors.Is(err, bot.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Bot.Service getting bot: %w", err)
+	}
+
+	if found.OwnerID != ownerID {
+		return bot.ErrNotOwner
+	}
+
+	return nil
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	raw := make([]byte, size)
+
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(raw), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
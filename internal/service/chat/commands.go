@@ -0,0 +1,606 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	commandDomain "github.com/monobearotaku/online-chat-api/internal/domain/command"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	kindNotice     = "notice"
+	kindCommand    = "command"
+	kindError      = "error"
+	kindDeleted    = "deleted"
+	kindPollUpdate = "poll_update"
+)
+
+type builtin struct {
+	name        string
+	description string
+	run         func(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error)
+}
+
+func (b builtin) Name() string {
+	return b.name
+}
+
+func (b builtin) Description() string {
+	return b.description
+}
+
+func (b builtin) Execute(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	return b.run(ctx, inv)
+}
+
+func (c *chatService) builtins() []commandDomain.Command {
+	return []commandDomain.Command{
+		builtin{name: "help", description: "List the commands available in this chat", run: c.helpCommand},
+		builtin{name: "invite", description: "/invite <login> adds a user to the chat (owners only)", run: c.inviteCommand},
+		builtin{name: "kick", description: "/kick <login> [reason] removes a member from the chat (owners only)", run: c.kickCommand},
+		builtin{name: "topic", description: "/topic [text] shows or sets the chat topic (owners only to set)", run: c.topicCommand},
+		builtin{name: "me", description: "/me <text> posts an action message", run: c.meCommand},
+		builtin{name: "mute", description: "/mute <login> [duration] [reason] stops a member from sending, 10m by default (owners only)", run: c.muteCommand},
+		builtin{name: "unmute", description: "/unmute <login> lets a muted member send again (owners only)", run: c.unmuteCommand},
+		builtin{name: "ban", description: "/ban <login> [duration] [reason] removes a user and keeps them out, forever by default (owners only)", run: c.banCommand},
+		builtin{name: "unban", description: "/unban <login> lets a banned user be added again (owners only)", run: c.unbanCommand},
+		builtin{name: "ttl", description: "/ttl [duration|off] shows or sets how long messages last before they disappear (owners only to set)", run: c.ttlCommand},
+		builtin{name: "slowmode", description: "/slowmode [interval|off] shows or sets the time members wait between messages (owners only to set)", run: c.slowModeCommand},
+	}
+}
+
+// RegisterCommand adds an in-process command plug-in available in every chat.
+func (c *chatService) RegisterCommand(cmd commandDomain.Command) error {
+	err := commandDomain.ValidateName(cmd.Name())
+	if err != nil {
+		return err
+	}
+
+	c.registryMu.Lock()
+	defer c.registryMu.Unlock()
+
+	if _, ok := c.registry[cmd.Name()]; ok {
+		return commandDomain.ErrAlreadyRegistered
+	}
+
+	c.registry[cmd.Name()] = cmd
+
+	return nil
+}
+
+func (c *chatService) RegisterBotCommand(ctx context.Context, botID, chatID int64, name, description string) (commandDomain.Registration, error) {
+	err := commandDomain.ValidateName(name)
+	if err != nil {
+		return commandDomain.Registration{}, err
+	}
+
+	err = commandDomain.ValidateDescription(description)
+	if err != nil {
+		return commandDomain.Registration{}, err
+	}
+
+	if c.lookup(name) != nil {
+		return commandDomain.Registration{}, commandDomain.ErrAlreadyRegistered
+	}
+
+	err = c.ValidateChat(ctx, botID, chatID)
+	if err != nil {
+		return commandDomain.Registration{}, err
+	}
+
+	registration, err := c.commands.Register(ctx, commandDomain.Registration{
+		ChatID:      chatID,
+		Name:        name,
+		Description: description,
+		BotID:       botID,
+	})
+	if err != nil {
+		if errors.Is(err, commandDomain.ErrAlreadyRegistered) {
+			return commandDomain.Registration{}, err
+		}
+
+		return commandDomain.Registration{}, fmt.Errorf("Chat.Service.RegisterBotCommand saving command: %w", err)
+	}
+
+	return registration, nil
+}
+
+func (c *chatService) UnregisterBotCommand(ctx context.Context, botID, chatID int64, name string) error {
+	err := c.commands.Unregister(ctx, chatID, name, botID)
+	if err != nil {
+		if errors.Is(err, commandDomain.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.UnregisterBotCommand deleting command: %w", err)
+	}
+
+	return nil
+}
+
+// GetCommands lists built-in and plug-in commands followed by the ones bots registered in the chat.
+func (c *chatService) GetCommands(ctx context.Context, userID, chatID int64) ([]commandDomain.Registration, error) {
+	err := c.ValidateChat(ctx, userID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	registrations, err := c.commands.GetByChat(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("Chat.Service.GetCommands getting commands: %w", err)
+	}
+
+	c.registryMu.RLock()
+	commands := make([]commandDomain.Registration, 0, len(c.registry)+len(registrations))
+	for _, cmd := range c.registry {
+		commands = append(commands, commandDomain.Registration{
+			ChatID:      chatID,
+			Name:        cmd.Name(),
+			Description: cmd.Description(),
+		})
+	}
+	c.registryMu.RUnlock()
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+
+	return append(commands, registrations...), nil
+}
+
+func (c *chatService) SetTopic(ctx context.Context, ownerID, chatID int64, topic string) error {
+	err := chatDomain.ValidateTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = c.chat.SetTopic(ctx, chatID, topic)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.SetTopic saving topic: %w", err)
+	}
+
+	return nil
+}
+
+func (c *chatService) SetMessageTTL(ctx context.Context, ownerID, chatID int64, ttl time.Duration) error {
+	err := chatDomain.ValidateTTL(ttl)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = c.chat.SetMessageTTL(ctx, chatID, ttl)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.SetMessageTTL saving ttl: %w", err)
+	}
+
+	details := ""
+	if ttl > 0 {
+		details = "new messages disappear after " + formatDuration(ttl)
+	}
+
+	c.publish(ctx, event.NewNoticeEvent(chatID, switchNotice(c.login(ctx, ownerID), "disappearing messages", details)))
+
+	return nil
+}
+
+func (c *chatService) SetRetentionPolicy(ctx context.Context, ownerID, chatID int64, policy retention.Policy) error {
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = c.chat.SetRetention(ctx, chatID, policy)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.SetRetentionPolicy saving policy: %w", err)
+	}
+
+	limits := make([]string, 0, 2)
+	if policy.KeepDays > 0 {
+		limits = append(limits, fmt.Sprintf("messages older than %d days", policy.KeepDays))
+	}
+
+	if policy.KeepMessages > 0 {
+		limits = append(limits, fmt.Sprintf("all but the newest %d messages", policy.KeepMessages))
+	}
+
+	text := " reset message retention to the server default"
+	if len(limits) > 0 {
+		text = " set message retention: " + strings.Join(limits, " and ") + " will be deleted"
+	}
+
+	text = c.login(ctx, ownerID) + text
+
+	c.publish(ctx, event.NewNoticeEvent(chatID, text))
+
+	return nil
+}
+
+func (c *chatService) SetExportPermission(ctx context.Context, ownerID, chatID, userID int64, allowed bool) error {
+	err := c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = c.chat.SetCanExport(ctx, chatID, userID, allowed)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatHaveNoUser) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.SetExportPermission saving permission: %w", err)
+	}
+
+	return nil
+}
+
+func (c *chatService) SetSlowMode(ctx context.Context, ownerID, chatID int64, interval time.Duration) error {
+	err := chatDomain.ValidateSlowMode(interval)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = c.chat.SetSlowMode(ctx, chatID, interval)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.SetSlowMode saving interval: %w", err)
+	}
+
+	details := ""
+	if interval > 0 {
+		details = "one message every " + formatDuration(interval)
+	}
+
+	c.publish(ctx, event.NewNoticeEvent(chatID, switchNotice(c.login(ctx, ownerID), "slow mode", details)))
+
+	return nil
+}
+
+// switchNotice announces that login turned a chat setting on with the given details, or off when there are none.
+func switchNotice(login, setting, details string) string {
+	if details == "" {
+		return login + " turned " + setting + " off"
+	}
+
+	return login + " turned " + setting + " on: " + details
+}
+
+func (c *chatService) lookup(name string) commandDomain.Command {
+	c.registryMu.RLock()
+	defer c.registryMu.RUnlock()
+
+	return c.registry[name]
+}
+
+// runCommand executes a command typed on a stream; replies go to the caller or, if not ephemeral,
+// to the whole chat as a notice. Unknown names fall back to commands bots registered in the chat.
+func (c *chatService) runCommand(ctx context.Context, caller user.User, chatID int64, name, args string, stream chatv1.ChatService_ConnectToChatServer) error {
+	inv := commandDomain.Invocation{
+		ChatID:      chatID,
+		CallerID:    caller.ID,
+		CallerLogin: caller.Login.String(),
+		Name:        name,
+		Args:        args,
+	}
+
+	cmd := c.lookup(name)
+	if cmd == nil {
+		return c.forwardCommand(ctx, inv)
+	}
+
+	reply, err := cmd.Execute(ctx, inv)
+	if err != nil {
+		return err
+	}
+
+	if reply.Text == "" {
+		return nil
+	}
+
+	if reply.Ephemeral {
+		return stream.Send(&chatv1.ChatMessageResponse{
+			ChatId:    chatID,
+			Message:   reply.Text,
+			Kind:      kindNotice,
+			Ephemeral: true,
+			CreatedAt: timestamppb.New(c.now()),
+		})
+	}
+
+	c.publish(ctx, event.NewNoticeEvent(chatID, reply.Text))
+
+	return nil
+}
+
+func (c *chatService) forwardCommand(ctx context.Context, inv commandDomain.Invocation) error {
+	registration, err := c.commands.Get(ctx, inv.ChatID, inv.Name)
+	if err != nil {
+		if errors.Is(err, commandDomain.ErrNotFound) {
+			return commandDomain.ErrUnknownCommand
+		}
+
+		return fmt.Errorf("getting bot command: %w", err)
+	}
+
+	c.publish(ctx, event.NewCommandEvent(inv.ChatID, event.Command{
+		Name:        inv.Name,
+		Args:        inv.Args,
+		CallerID:    inv.CallerID,
+		CallerLogin: inv.CallerLogin,
+		BotID:       registration.BotID,
+	}))
+
+	return nil
+}
+
+func (c *chatService) helpCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	commands, err := c.GetCommands(ctx, inv.CallerID, inv.ChatID)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	lines := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		line := commandDomain.Prefix + cmd.Name + " - " + cmd.Description
+		if cmd.BotLogin != "" {
+			line += " (" + cmd.BotLogin + ")"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return commandDomain.Ephemeral(strings.Join(lines, "\n")), nil
+}
+
+func (c *chatService) inviteCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	login, ok := targetLogin(inv)
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /invite <login>"), nil
+	}
+
+	usr, err := c.auth.GetUser(ctx, login)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	err = c.AddUserToChat(ctx, inv.CallerID, inv.ChatID, usr.ID)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	return commandDomain.Reply{Text: fmt.Sprintf("%s invited %s", inv.CallerLogin, usr.Login)}, nil
+}
+
+func (c *chatService) kickCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	login, ok := targetLogin(inv)
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /kick <login> [reason]"), nil
+	}
+
+	usr, err := c.auth.GetUser(ctx, login)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	reason := strings.Join(inv.Fields()[1:], " ")
+
+	return commandDomain.Reply{}, c.KickUser(ctx, inv.CallerID, inv.ChatID, usr.ID, reason)
+}
+
+func (c *chatService) topicCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	if inv.Args == "" {
+		cht, err := c.chat.GetById(ctx, inv.ChatID)
+		if err != nil {
+			return commandDomain.Reply{}, err
+		}
+
+		if cht.Topic == "" {
+			return commandDomain.Ephemeral("No topic is set"), nil
+		}
+
+		return commandDomain.Ephemeral("Topic: " + cht.Topic), nil
+	}
+
+	err := c.SetTopic(ctx, inv.CallerID, inv.ChatID, inv.Args)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	return commandDomain.Reply{Text: fmt.Sprintf("%s set the topic to: %s", inv.CallerLogin, inv.Args)}, nil
+}
+
+func (c *chatService) meCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	if inv.Args == "" {
+		return commandDomain.Ephemeral("Usage: /me <text>"), nil
+	}
+
+	caller, err := c.auth.GetUserById(ctx, inv.CallerID)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	_, err = c.postMessage(ctx, caller, inv.ChatID, inv.Args, chatDomain.KindAction, "", 0)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	return commandDomain.Reply{}, nil
+}
+
+func (c *chatService) muteCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	login, ok := targetLogin(inv)
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /mute <login> [duration] [reason]"), nil
+	}
+
+	usr, err := c.auth.GetUser(ctx, login)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	duration, reason, ok := commandDomain.SplitDuration(inv.Fields()[1:])
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /mute <login> [duration] [reason], e.g. /mute bob 30m spam"), nil
+	}
+
+	return commandDomain.Reply{}, c.MuteUser(ctx, inv.CallerID, inv.ChatID, usr.ID, duration, reason)
+}
+
+func (c *chatService) unmuteCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	login, ok := targetLogin(inv)
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /unmute <login>"), nil
+	}
+
+	usr, err := c.auth.GetUser(ctx, login)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	return commandDomain.Reply{}, c.UnmuteUser(ctx, inv.CallerID, inv.ChatID, usr.ID)
+}
+
+func (c *chatService) banCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	login, ok := targetLogin(inv)
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /ban <login> [duration] [reason]"), nil
+	}
+
+	usr, err := c.auth.GetUser(ctx, login)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	duration, reason, ok := commandDomain.SplitDuration(inv.Fields()[1:])
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /ban <login> [duration] [reason], e.g. /ban bob 30m spam"), nil
+	}
+
+	return commandDomain.Reply{}, c.BanUser(ctx, inv.CallerID, inv.ChatID, usr.ID, duration, reason)
+}
+
+func (c *chatService) unbanCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	login, ok := targetLogin(inv)
+	if !ok {
+		return commandDomain.Ephemeral("Usage: /unban <login>"), nil
+	}
+
+	usr, err := c.auth.GetUser(ctx, login)
+	if err != nil {
+		return commandDomain.Reply{}, err
+	}
+
+	return commandDomain.Reply{}, c.UnbanUser(ctx, inv.CallerID, inv.ChatID, usr.ID)
+}
+
+func (c *chatService) slowModeCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	fields := inv.Fields()
+	if len(fields) == 0 {
+		cht, err := c.chat.GetById(ctx, inv.ChatID)
+		if err != nil {
+			return commandDomain.Reply{}, err
+		}
+
+		if cht.SlowMode == 0 {
+			return commandDomain.Ephemeral("Slow mode is off"), nil
+		}
+
+		return commandDomain.Ephemeral("Slow mode: one message every " + formatDuration(cht.SlowMode)), nil
+	}
+
+	var interval time.Duration
+
+	if fields[0] != "off" {
+		parsed, err := time.ParseDuration(fields[0])
+		if err != nil {
+			return commandDomain.Ephemeral("Usage: /slowmode [interval|off], e.g. /slowmode 30s"), nil
+		}
+
+		interval = parsed
+	}
+
+	return commandDomain.Reply{}, c.SetSlowMode(ctx, inv.CallerID, inv.ChatID, interval)
+}
+
+func (c *chatService) ttlCommand(ctx context.Context, inv commandDomain.Invocation) (commandDomain.Reply, error) {
+	fields := inv.Fields()
+	if len(fields) == 0 {
+		cht, err := c.chat.GetById(ctx, inv.ChatID)
+		if err != nil {
+			return commandDomain.Reply{}, err
+		}
+
+		if cht.MessageTTL == 0 {
+			return commandDomain.Ephemeral("Disappearing messages are off"), nil
+		}
+
+		return commandDomain.Ephemeral("Messages disappear after " + formatDuration(cht.MessageTTL)), nil
+	}
+
+	var ttl time.Duration
+
+	if fields[0] != "off" {
+		parsed, err := time.ParseDuration(fields[0])
+		if err != nil {
+			return commandDomain.Ephemeral("Usage: /ttl [duration|off], e.g. /ttl 24h"), nil
+		}
+
+		ttl = parsed
+	}
+
+	return commandDomain.Reply{}, c.SetMessageTTL(ctx, inv.CallerID, inv.ChatID, ttl)
+}
+
+func targetLogin(inv commandDomain.Invocation) (domain.Login, bool) {
+	fields := inv.Fields()
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	return domain.Login(fields[0]), true
+}
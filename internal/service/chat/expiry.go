@@ -0,0 +1,33 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const expiredBatchSize = 500
+
+var expiredTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "chat_expired_messages_total",
+	Help: "Number of disappearing messages deleted after their time to live.",
+})
+
+// DeleteExpiredMessages deletes one batch of expired messages and tells live streams they are gone.
+func (c *chatService) DeleteExpiredMessages(ctx context.Context) (int, error) {
+	messages, err := c.chat.DeleteExpiredMessages(ctx, expiredBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("Chat.Service.DeleteExpiredMessages deleting messages: %w", err)
+	}
+
+	for _, msg := range messages {
+		c.publish(ctx, event.NewMessageEvent(event.MessageDeleted, msg))
+	}
+
+	expiredTotal.Add(float64(len(messages)))
+
+	return len(messages), nil
+}
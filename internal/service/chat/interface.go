@@ -2,10 +2,18 @@ package chat
 
 import (
 	"context"
+	"time"
 
 	"github.com/monobearotaku/online-chat-api/internal/domain/token"
 
 	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/command"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+	"github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/monobearotaku/online-chat-api/internal/domain/report"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+	"github.com/monobearotaku/online-chat-api/internal/domain/schedule"
 	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
 )
 
@@ -16,4 +24,36 @@ type Service interface {
 	StartMessaging(context.Context, int64, int64, chatv1.ChatService_ConnectToChatServer) error
 	AddUserToChat(context.Context, int64, int64, int64) error
 	SendMessage(context.Context, string, chat.Message)
+	PostMessage(ctx context.Context, userID, chatID int64, text string, ttl time.Duration) (chat.Message, error)
+	SendEvent(ctx context.Context, key string, evt event.Event)
+	SetTopic(ctx context.Context, ownerID, chatID int64, topic string) error
+	ScheduleMessage(ctx context.Context, userID, chatID int64, text string, sendAt time.Time) (schedule.Message, error)
+	GetScheduledMessages(ctx context.Context, userID, chatID int64) ([]schedule.Message, error)
+	CancelScheduledMessage(ctx context.Context, userID, scheduledID int64) error
+	SendDueScheduled(ctx context.Context) (int, error)
+	CreatePoll(ctx context.Context, userID, chatID int64, newPoll poll.Poll) (poll.Poll, error)
+	GetPoll(ctx context.Context, userID, messageID int64) (poll.Poll, []int, error)
+	VotePoll(ctx context.Context, userID, messageID int64, choice []int) (poll.Poll, error)
+	ClosePoll(ctx context.Context, userID, messageID int64) (poll.Poll, error)
+	CloseDuePolls(ctx context.Context) (int, error)
+	SetMessageTTL(ctx context.Context, ownerID, chatID int64, ttl time.Duration) error
+	DeleteExpiredMessages(ctx context.Context) (int, error)
+	SetRetentionPolicy(ctx context.Context, ownerID, chatID int64, policy retention.Policy) error
+	SetExportPermission(ctx context.Context, ownerID, chatID, userID int64, allowed bool) error
+	SetSlowMode(ctx context.Context, ownerID, chatID int64, interval time.Duration) error
+	KickUser(ctx context.Context, ownerID, chatID, userID int64, reason string) error
+	MuteUser(ctx context.Context, ownerID, chatID, userID int64, duration time.Duration, reason string) error
+	UnmuteUser(ctx context.Context, ownerID, chatID, userID int64) error
+	BanUser(ctx context.Context, ownerID, chatID, userID int64, duration time.Duration, reason string) error
+	UnbanUser(ctx context.Context, ownerID, chatID, userID int64) error
+	GetBans(ctx context.Context, ownerID, chatID int64) ([]moderation.Ban, error)
+	GetModerationLog(ctx context.Context, ownerID, chatID int64, limit int) ([]moderation.Entry, error)
+	ReportMessage(ctx context.Context, reporterID, chatID, messageID int64, reason string) (report.Report, error)
+	GetReports(ctx context.Context, ownerID, chatID int64, limit int) ([]report.Summary, error)
+	GetReportContext(ctx context.Context, ownerID, chatID, messageID int64, around int) ([]chat.Message, error)
+	ResolveReport(ctx context.Context, ownerID, chatID, messageID int64, resolution report.Resolution) error
+	RegisterCommand(cmd command.Command) error
+	RegisterBotCommand(ctx context.Context, botID, chatID int64, name, description string) (command.Registration, error)
+	UnregisterBotCommand(ctx context.Context, botID, chatID int64, name string) error
+	GetCommands(ctx context.Context, userID, chatID int64) ([]command.Registration, error)
 }
@@ -0,0 +1,431 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+func (c *chatService) KickUser(ctx context.Context, ownerID, chatID, userID int64, reason string) (err error) {
+	err = moderation.ValidateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkTarget(ctx, ownerID, chatID, userID, true)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Chat.Service.KickUser begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			c.memberRemoved(ctx, chatID, userID)
+			c.announce(ctx, moderation.Entry{ChatID: chatID, ActorID: ownerID, TargetID: userID, Action: moderation.ActionKick, Reason: reason})
+		}
+	}()
+
+	err = c.chat.WithTx(tx).RemoveUserFromChat(ctx, chatID, userID)
+	if err != nil {
+		return fmt.Errorf("Chat.Service.KickUser removing user: %w", err)
+	}
+
+	_, err = c.moderation.WithTx(tx).AddEntry(ctx, moderation.Entry{
+		ChatID:   chatID,
+		ActorID:  ownerID,
+		TargetID: userID,
+		Action:   moderation.ActionKick,
+		Reason:   reason,
+	})
+	if err != nil {
+		return fmt.Errorf("Chat.Service.KickUser logging: %w", err)
+	}
+
+	return nil
+}
+
+// MuteUser stops a member from sending; a zero duration mutes for the default duration.
+func (c *chatService) MuteUser(ctx context.Context, ownerID, chatID, userID int64, duration time.Duration, reason string) error {
+	err := moderation.ValidateDuration(duration)
+	if err != nil {
+		return err
+	}
+
+	if duration == 0 {
+		duration = moderation.DefaultMuteDuration
+	}
+
+	return c.setMute(ctx, moderation.Entry{
+		ChatID:    chatID,
+		ActorID:   ownerID,
+		TargetID:  userID,
+		Action:    moderation.ActionMute,
+		Reason:    reason,
+		ExpiresAt: moderation.Expiry(c.now(), duration),
+	})
+}
+
+func (c *chatService) UnmuteUser(ctx context.Context, ownerID, chatID, userID int64) error {
+	return c.setMute(ctx, moderation.Entry{
+		ChatID:   chatID,
+		ActorID:  ownerID,
+		TargetID: userID,
+		Action:   moderation.ActionUnmute,
+	})
+}
+
+func (c *chatService) setMute(ctx context.Context, entry moderation.Entry) (err error) {
+	err = moderation.ValidateReason(entry.Reason)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkTarget(ctx, entry.ActorID, entry.ChatID, entry.TargetID, true)
+	if err != nil {
+		return err
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Chat.Service.MuteUser begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			c.announce(ctx, entry)
+		}
+	}()
+
+	err = c.applyMute(ctx, tx, entry)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatHaveNoUser) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.MuteUser: %w", err)
+	}
+
+	return nil
+}
+
+// applyMute saves a mute or unmute and logs it within tx.
+func (c *chatService) applyMute(ctx context.Context, tx postgres.Tx, entry moderation.Entry) error {
+	err := c.chat.WithTx(tx).SetMutedUntil(ctx, entry.ChatID, entry.TargetID, entry.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatHaveNoUser) {
+			return err
+		}
+
+		return fmt.Errorf("saving mute: %w", err)
+	}
+
+	_, err = c.moderation.WithTx(tx).AddEntry(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("logging: %w", err)
+	}
+
+	return nil
+}
+
+// BanUser removes the user from the chat and keeps them out; a zero duration bans permanently.
+// Users who are not members yet can be banned pre-emptively.
+func (c *chatService) BanUser(ctx context.Context, ownerID, chatID, userID int64, duration time.Duration, reason string) (err error) {
+	err = moderation.ValidateDuration(duration)
+	if err != nil {
+		return err
+	}
+
+	err = moderation.ValidateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkTarget(ctx, ownerID, chatID, userID, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.BanUser getting user: %w", err)
+	}
+
+	entry := moderation.Entry{
+		ChatID:    chatID,
+		ActorID:   ownerID,
+		TargetID:  userID,
+		Action:    moderation.ActionBan,
+		Reason:    reason,
+		ExpiresAt: moderation.Expiry(c.now(), duration),
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Chat.Service.BanUser begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			c.memberRemoved(ctx, chatID, userID)
+			c.announce(ctx, entry)
+		}
+	}()
+
+	err = c.applyBan(ctx, tx, entry)
+	if err != nil {
+		return fmt.Errorf("Chat.Service.BanUser: %w", err)
+	}
+
+	return nil
+}
+
+// applyBan saves the ban, removes the user from the chat and logs it within tx.
+func (c *chatService) applyBan(ctx context.Context, tx postgres.Tx, entry moderation.Entry) error {
+	err := c.moderation.WithTx(tx).Ban(ctx, moderation.Ban{
+		ChatID:    entry.ChatID,
+		UserID:    entry.TargetID,
+		BannedBy:  entry.ActorID,
+		Reason:    entry.Reason,
+		ExpiresAt: entry.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("saving ban: %w", err)
+	}
+
+	err = c.chat.WithTx(tx).RemoveUserFromChat(ctx, entry.ChatID, entry.TargetID)
+	if err != nil {
+		return fmt.Errorf("removing user: %w", err)
+	}
+
+	_, err = c.moderation.WithTx(tx).AddEntry(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("logging: %w", err)
+	}
+
+	return nil
+}
+
+func (c *chatService) UnbanUser(ctx context.Context, ownerID, chatID, userID int64) (err error) {
+	err = c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	entry := moderation.Entry{
+		ChatID:   chatID,
+		ActorID:  ownerID,
+		TargetID: userID,
+		Action:   moderation.ActionUnban,
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Chat.Service.UnbanUser begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			c.announce(ctx, entry)
+		}
+	}()
+
+	err = c.moderation.WithTx(tx).Unban(ctx, chatID, userID)
+	if err != nil {
+		if errors.Is(err, moderation.ErrNotBanned) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.UnbanUser deleting ban: %w", err)
+	}
+
+	_, err = c.moderation.WithTx(tx).AddEntry(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("Chat.Service.UnbanUser logging: %w", err)
+	}
+
+	return nil
+}
+
+func (c *chatService) GetBans(ctx context.Context, ownerID, chatID int64) ([]moderation.Ban, error) {
+	err := c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	bans, err := c.moderation.GetBans(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("Chat.Service.GetBans getting bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+func (c *chatService) GetModerationLog(ctx context.Context, ownerID, chatID int64, limit int) ([]moderation.Entry, error) {
+	err := c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := c.moderation.GetLog(ctx, chatID, moderation.ParseLogLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("Chat.Service.GetModerationLog getting log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// checkBan rejects users with an active ban in the chat.
+func (c *chatService) checkBan(ctx context.Context, chatID, userID int64) error {
+	ban, err := c.moderation.GetBan(ctx, chatID, userID)
+	if err != nil {
+		if errors.Is(err, moderation.ErrNotBanned) {
+			return nil
+		}
+
+		return fmt.Errorf("getting ban: %w", err)
+	}
+
+	now := c.now()
+	if !ban.Active(now) {
+		return nil
+	}
+
+	return moderation.ErrBanned.WithRetryAfter(ban.RetryAfter(now))
+}
+
+func (c *chatService) checkOwner(ctx context.Context, ownerID, chatID int64) error {
+	chtUsers, err := c.chat.GetChatUsers(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("getting chat users: %w", err)
+	}
+
+	if !chtUsers.IsOwner(ownerID) {
+		return chatDomain.ErrUserNotOwner
+	}
+
+	return nil
+}
+
+// checkTarget lets owners act on anyone but other owners; member requires the target to be in the chat.
+func (c *chatService) checkTarget(ctx context.Context, ownerID, chatID, userID int64, member bool) error {
+	chtUsers, err := c.chat.GetChatUsers(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("getting chat users: %w", err)
+	}
+
+	if !chtUsers.IsOwner(ownerID) {
+		return chatDomain.ErrUserNotOwner
+	}
+
+	target, ok := chtUsers.Get(userID)
+	if !ok && member {
+		return chatDomain.ErrChatHaveNoUser
+	}
+
+	if ok && target.Role == chatDomain.Owner {
+		return chatDomain.ErrCannotTargetOwner
+	}
+
+	return nil
+}
+
+func (c *chatService) memberRemoved(ctx context.Context, chatID, userID int64) {
+	c.publish(ctx, event.NewMemberEvent(event.MemberRemoved, chatID, event.Member{
+		UserID: userID,
+	}))
+}
+
+// announce broadcasts a moderation action to the chat as a notice.
+func (c *chatService) announce(ctx context.Context, entry moderation.Entry) {
+	actor, target := c.login(ctx, entry.ActorID), c.login(ctx, entry.TargetID)
+
+	var notice string
+
+	switch entry.Action {
+	case moderation.ActionKick:
+		notice = fmt.Sprintf("%s removed %s from the chat", actor, target)
+	case moderation.ActionMute:
+		notice = fmt.Sprintf("%s muted %s for %s", actor, target, formatDuration(entry.ExpiresAt.Sub(c.now())))
+	case moderation.ActionUnmute:
+		notice = fmt.Sprintf("%s unmuted %s", actor, target)
+	case moderation.ActionBan:
+		if entry.ExpiresAt.IsZero() {
+			notice = fmt.Sprintf("%s banned %s", actor, target)
+		} else {
+			notice = fmt.Sprintf("%s banned %s for %s", actor, target, formatDuration(entry.ExpiresAt.Sub(c.now())))
+		}
+	case moderation.ActionUnban:
+		notice = fmt.Sprintf("%s unbanned %s", actor, target)
+	}
+
+	if entry.Reason != "" {
+		notice += ": " + entry.Reason
+	}
+
+	c.publish(ctx, event.NewNoticeEvent(entry.ChatID, notice))
+}
+
+func (c *chatService) login(ctx context.Context, userID int64) string {
+	usr, err := c.auth.GetUserById(ctx, userID)
+	if err != nil {
+		return fmt.Sprintf("user %d", userID)
+	}
+
+	return usr.Login.String()
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+
+	text := d.String()
+	if d%time.Minute == 0 {
+		text = strings.TrimSuffix(text, "0s")
+	}
+
+	if d%time.Hour == 0 {
+		text = strings.TrimSuffix(text, "0m")
+	}
+
+	return text
+}
@@ -0,0 +1,246 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	filterDomain "github.com/monobearotaku/online-chat-api/internal/domain/filter"
+	pollDomain "github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+)
+
+const duePollsBatchSize = 100
+
+// CreatePoll posts a poll message; the poll goes through the same checks, filters and limits as a text message
+// and is stored with its message in one transaction.
+func (c *chatService) CreatePoll(ctx context.Context, userID, chatID int64, newPoll pollDomain.Poll) (created pollDomain.Poll, err error) {
+	newPoll.Question = strings.TrimSpace(newPoll.Question)
+
+	err = newPoll.Validate(c.now())
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	currentUser, err := c.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return pollDomain.Poll{}, err
+		}
+
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service.CreatePoll getting user: %w", err)
+	}
+
+	err = c.limiter.Take(ctx, ratelimit.UserKey(userID))
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	msg, member, err := c.prepareMessage(ctx, currentUser, chatID, newPoll.Question, chatDomain.KindPoll, 0)
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	for i, option := range newPoll.Options {
+		newPoll.Options[i].Text, err = c.filters.Check(ctx, filterDomain.Message{
+			ChatID: chatID,
+			UserID: userID,
+			Text:   option.Text,
+		})
+		if err != nil {
+			return pollDomain.Poll{}, err
+		}
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service.CreatePoll begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return
+		}
+
+		evt := event.NewMessageEvent(event.MessageCreated, msg)
+		evt.Poll = &created
+		c.publish(ctx, evt)
+	}()
+
+	err = c.takeChatSlot(ctx, c.chat.WithTx(tx), chatID, member)
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	msg, err = c.chat.WithTx(tx).SaveMessage(ctx, msg, 0)
+	if err != nil {
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service.CreatePoll saving message: %w", err)
+	}
+
+	newPoll.MessageID = msg.ID
+	newPoll.ChatID = chatID
+	newPoll.CreatorID = userID
+	newPoll.Question = msg.Msg
+
+	created, err = c.polls.WithTx(tx).Create(ctx, newPoll)
+	if err != nil {
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service.CreatePoll saving poll: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetPoll returns the poll's tally and the options the caller voted for.
+func (c *chatService) GetPoll(ctx context.Context, userID, messageID int64) (pollDomain.Poll, []int, error) {
+	p, err := c.getPoll(ctx, userID, messageID)
+	if err != nil {
+		return pollDomain.Poll{}, nil, err
+	}
+
+	choice, err := c.polls.GetVotes(ctx, messageID, userID)
+	if err != nil {
+		return pollDomain.Poll{}, nil, fmt.Errorf("Chat.Service.GetPoll getting votes: %w", err)
+	}
+
+	return p, choice, nil
+}
+
+// VotePoll replaces the caller's vote and pushes the new tally to every connected stream.
+func (c *chatService) VotePoll(ctx context.Context, userID, messageID int64, choice []int) (pollDomain.Poll, error) {
+	p, err := c.getPoll(ctx, userID, messageID)
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	if p.Closed(c.now()) {
+		return pollDomain.Poll{}, pollDomain.ErrClosed
+	}
+
+	choice, err = p.NormalizeChoice(choice)
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	err = c.limiter.Take(ctx, ratelimit.UserKey(userID))
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	err = c.setVotes(ctx, userID, messageID, choice)
+	if err != nil {
+		if errors.Is(err, pollDomain.ErrClosed) {
+			return pollDomain.Poll{}, err
+		}
+
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service.VotePoll saving votes: %w", err)
+	}
+
+	return c.publishPoll(ctx, messageID)
+}
+
+// ClosePoll stops voting; the creator and chat owners may close a poll.
+func (c *chatService) ClosePoll(ctx context.Context, userID, messageID int64) (pollDomain.Poll, error) {
+	p, err := c.getPoll(ctx, userID, messageID)
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	if p.CreatorID != userID {
+		err = c.checkOwner(ctx, userID, p.ChatID)
+		if err != nil {
+			if errors.Is(err, chatDomain.ErrUserNotOwner) {
+				return pollDomain.Poll{}, pollDomain.ErrNotCreator
+			}
+
+			return pollDomain.Poll{}, fmt.Errorf("Chat.Service.ClosePoll: %w", err)
+		}
+	}
+
+	err = c.polls.Close(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, pollDomain.ErrClosed) {
+			return pollDomain.Poll{}, err
+		}
+
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service.ClosePoll closing: %w", err)
+	}
+
+	return c.publishPoll(ctx, messageID)
+}
+
+// CloseDuePolls closes one batch of polls whose deadline passed and pushes their final tally to live streams.
+func (c *chatService) CloseDuePolls(ctx context.Context) (int, error) {
+	messageIDs, err := c.polls.CloseDue(ctx, duePollsBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("Chat.Service.CloseDuePolls closing polls: %w", err)
+	}
+
+	for _, messageID := range messageIDs {
+		_, err = c.publishPoll(ctx, messageID)
+		if err != nil {
+			return 0, fmt.Errorf("Chat.Service.CloseDuePolls: %w", err)
+		}
+	}
+
+	return len(messageIDs), nil
+}
+
+// getPoll loads a poll the user can see as a chat member.
+func (c *chatService) getPoll(ctx context.Context, userID, messageID int64) (pollDomain.Poll, error) {
+	p, err := c.polls.Get(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, pollDomain.ErrNotFound) {
+			return pollDomain.Poll{}, err
+		}
+
+		return pollDomain.Poll{}, fmt.Errorf("getting poll: %w", err)
+	}
+
+	err = c.ValidateChat(ctx, userID, p.ChatID)
+	if err != nil {
+		return pollDomain.Poll{}, err
+	}
+
+	return p, nil
+}
+
+func (c *chatService) setVotes(ctx context.Context, userID, messageID int64, choice []int) (err error) {
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	return c.polls.WithTx(tx).SetVotes(ctx, messageID, userID, choice)
+}
+
+// publishPoll reads the committed tally and fans it out through Kafka.
+func (c *chatService) publishPoll(ctx context.Context, messageID int64) (pollDomain.Poll, error) {
+	p, err := c.polls.Get(ctx, messageID)
+	if err != nil {
+		return pollDomain.Poll{}, fmt.Errorf("Chat.Service reading poll tally: %w", err)
+	}
+
+	c.publish(ctx, event.NewPollEvent(p))
+
+	return p, nil
+}
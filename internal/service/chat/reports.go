@@ -0,0 +1,259 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/moderation"
+	"github.com/monobearotaku/online-chat-api/internal/domain/report"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+)
+
+// ReportMessage flags a message for the chat's owners; each member can report a message once.
+func (c *chatService) ReportMessage(ctx context.Context, reporterID, chatID, messageID int64, reason string) (rep report.Report, err error) {
+	err = report.ValidateReason(reason)
+	if err != nil {
+		return report.Report{}, err
+	}
+
+	err = c.ValidateChat(ctx, reporterID, chatID)
+	if err != nil {
+		return report.Report{}, err
+	}
+
+	msg, err := c.chat.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrMessageNotFound) {
+			return report.Report{}, err
+		}
+
+		return report.Report{}, fmt.Errorf("Chat.Service.ReportMessage getting message: %w", err)
+	}
+
+	if msg.UserID == reporterID {
+		return report.Report{}, report.ErrOwnMessage
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return report.Report{}, fmt.Errorf("Chat.Service.ReportMessage begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	rep, err = c.reports.WithTx(tx).Create(ctx, report.Report{
+		ChatID:     chatID,
+		MessageID:  messageID,
+		AuthorID:   msg.UserID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	}, msg.Msg)
+	if err != nil {
+		if errors.Is(err, report.ErrAlreadyReported) {
+			return report.Report{}, err
+		}
+
+		return report.Report{}, fmt.Errorf("Chat.Service.ReportMessage saving report: %w", err)
+	}
+
+	_, err = c.moderation.WithTx(tx).AddEntry(ctx, moderation.Entry{
+		ChatID:    chatID,
+		ActorID:   reporterID,
+		TargetID:  msg.UserID,
+		MessageID: messageID,
+		Action:    moderation.ActionReport,
+		Reason:    reason,
+	})
+	if err != nil {
+		return report.Report{}, fmt.Errorf("Chat.Service.ReportMessage logging: %w", err)
+	}
+
+	return rep, nil
+}
+
+func (c *chatService) GetReports(ctx context.Context, ownerID, chatID int64, limit int) ([]report.Summary, error) {
+	err := c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := c.reports.GetOpen(ctx, chatID, report.ParseQueueLimit(limit))
+	if err != nil {
+		return nil, fmt.Errorf("Chat.Service.GetReports getting reports: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// GetReportContext returns the history around a reported message so owners can judge it in context.
+func (c *chatService) GetReportContext(ctx context.Context, ownerID, chatID, messageID int64, around int) ([]chatDomain.Message, error) {
+	err := c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := c.chat.GetMessagesAround(ctx, chatID, messageID, report.ParseContext(around))
+	if err != nil {
+		return nil, fmt.Errorf("Chat.Service.GetReportContext getting messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ResolveReport closes the open reports on a message and applies the resolution in the same transaction,
+// logging every step to the moderation log.
+func (c *chatService) ResolveReport(ctx context.Context, ownerID, chatID, messageID int64, resolution report.Resolution) (err error) {
+	err = resolution.Validate()
+	if err != nil {
+		return err
+	}
+
+	// Only the owner may learn whether a message was reported at all.
+	err = c.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	summary, err := c.reports.GetOpenForMessage(ctx, chatID, messageID)
+	if err != nil {
+		if errors.Is(err, report.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.ResolveReport getting reports: %w", err)
+	}
+
+	switch resolution.Action {
+	case report.ActionMute:
+		err = c.checkTarget(ctx, ownerID, chatID, summary.AuthorID, true)
+	case report.ActionBan:
+		err = c.checkTarget(ctx, ownerID, chatID, summary.AuthorID, false)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	entry := moderation.Entry{
+		ChatID:    chatID,
+		ActorID:   ownerID,
+		TargetID:  summary.AuthorID,
+		MessageID: messageID,
+		Reason:    resolution.Reason,
+	}
+
+	deleted := false
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Chat.Service.ResolveReport begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return
+		}
+
+		if deleted {
+			c.publish(ctx, event.NewMessageEvent(event.MessageDeleted, chatDomain.Message{
+				ID:     messageID,
+				ChatID: chatID,
+				UserID: summary.AuthorID,
+			}))
+		}
+
+		switch resolution.Action {
+		case report.ActionMute:
+			c.announce(ctx, entry)
+		case report.ActionBan:
+			c.memberRemoved(ctx, chatID, summary.AuthorID)
+			c.announce(ctx, entry)
+		}
+	}()
+
+	_, err = c.reports.WithTx(tx).Resolve(ctx, chatID, messageID, ownerID, resolution.Action)
+	if err != nil {
+		if errors.Is(err, report.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.ResolveReport resolving reports: %w", err)
+	}
+
+	if resolution.Deletes() {
+		deleted, err = c.deleteReported(ctx, tx, entry)
+		if err != nil {
+			return fmt.Errorf("Chat.Service.ResolveReport: %w", err)
+		}
+	}
+
+	switch resolution.Action {
+	case report.ActionDismiss:
+		entry.Action = moderation.ActionDismissReport
+
+		_, err = c.moderation.WithTx(tx).AddEntry(ctx, entry)
+	case report.ActionMute:
+		duration := resolution.Duration
+		if duration == 0 {
+			duration = moderation.DefaultMuteDuration
+		}
+
+		entry.Action = moderation.ActionMute
+		entry.ExpiresAt = moderation.Expiry(c.now(), duration)
+
+		err = c.applyMute(ctx, tx, entry)
+	case report.ActionBan:
+		entry.Action = moderation.ActionBan
+		entry.ExpiresAt = moderation.Expiry(c.now(), resolution.Duration)
+
+		err = c.applyBan(ctx, tx, entry)
+	}
+
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatHaveNoUser) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.ResolveReport applying %s: %w", resolution.Action, err)
+	}
+
+	return nil
+}
+
+// deleteReported removes the message and logs it; a message that is already gone is not an error.
+func (c *chatService) deleteReported(ctx context.Context, tx postgres.Tx, entry moderation.Entry) (bool, error) {
+	err := c.chat.WithTx(tx).DeleteMessage(ctx, entry.ChatID, entry.MessageID)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrMessageNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("deleting message: %w", err)
+	}
+
+	entry.Action = moderation.ActionDeleteMessage
+
+	_, err = c.moderation.WithTx(tx).AddEntry(ctx, entry)
+	if err != nil {
+		return false, fmt.Errorf("logging: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,185 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/schedule"
+)
+
+const scheduledBatchSize = 50
+
+func (c *chatService) ScheduleMessage(ctx context.Context, userID, chatID int64, text string, sendAt time.Time) (msg schedule.Message, err error) {
+	err = schedule.ValidateSendAt(c.now(), sendAt)
+	if err != nil {
+		return schedule.Message{}, err
+	}
+
+	err = chatDomain.Message{Msg: text}.Validate()
+	if err != nil {
+		return schedule.Message{}, err
+	}
+
+	err = c.ValidateChat(ctx, userID, chatID)
+	if err != nil {
+		return schedule.Message{}, err
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return schedule.Message{}, fmt.Errorf("Chat.Service.ScheduleMessage begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	// The sender's row lock makes concurrent requests count pending messages one at a time.
+	err = c.auth.WithTx(tx).LockUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return schedule.Message{}, err
+		}
+
+		return schedule.Message{}, fmt.Errorf("Chat.Service.ScheduleMessage locking sender: %w", err)
+	}
+
+	pending, err := c.schedules.WithTx(tx).CountPending(ctx, userID)
+	if err != nil {
+		return schedule.Message{}, fmt.Errorf("Chat.Service.ScheduleMessage counting pending: %w", err)
+	}
+
+	if pending >= schedule.MaxPending {
+		return schedule.Message{}, schedule.ErrTooMany
+	}
+
+	msg, err = c.schedules.WithTx(tx).Create(ctx, schedule.Message{
+		ChatID: chatID,
+		UserID: userID,
+		Text:   text,
+		SendAt: sendAt,
+	})
+	if err != nil {
+		return schedule.Message{}, fmt.Errorf("Chat.Service.ScheduleMessage saving message: %w", err)
+	}
+
+	return msg, nil
+}
+
+func (c *chatService) GetScheduledMessages(ctx context.Context, userID, chatID int64) ([]schedule.Message, error) {
+	messages, err := c.schedules.GetPending(ctx, userID, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("Chat.Service.GetScheduledMessages getting messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+func (c *chatService) CancelScheduledMessage(ctx context.Context, userID, scheduledID int64) error {
+	err := c.schedules.Cancel(ctx, userID, scheduledID)
+	if err != nil {
+		if errors.Is(err, schedule.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Chat.Service.CancelScheduledMessage cancelling: %w", err)
+	}
+
+	return nil
+}
+
+// SendDueScheduled posts one batch of due scheduled messages. Claiming, saving and marking them sent share
+// a transaction whose row locks other replicas skip, so each message is stored exactly once; events are
+// published after commit. Messages the sender may no longer post are marked failed with the reason.
+func (c *chatService) SendDueScheduled(ctx context.Context) (processed int, err error) {
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("Chat.Service.SendDueScheduled begin tx: %w", err)
+	}
+
+	sent := make([]chatDomain.Message, 0)
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return
+		}
+
+		for _, msg := range sent {
+			c.publish(ctx, event.NewMessageEvent(event.MessageCreated, msg))
+		}
+	}()
+
+	due, err := c.schedules.WithTx(tx).ClaimDue(ctx, c.now(), scheduledBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("Chat.Service.SendDueScheduled claiming messages: %w", err)
+	}
+
+	for _, item := range due {
+		msg, err := c.sendScheduled(ctx, item)
+		if err != nil {
+			domainErr, ok := domain.AsError(err)
+			if !ok {
+				return 0, fmt.Errorf("Chat.Service.SendDueScheduled sending %d: %w", item.ID, err)
+			}
+
+			err = c.schedules.WithTx(tx).MarkFailed(ctx, item.ID, domainErr.Reason())
+			if err != nil {
+				return 0, fmt.Errorf("Chat.Service.SendDueScheduled marking %d failed: %w", item.ID, err)
+			}
+
+			continue
+		}
+
+		msg, err = c.chat.WithTx(tx).SaveMessage(ctx, msg, 0)
+		if err != nil {
+			return 0, fmt.Errorf("Chat.Service.SendDueScheduled saving %d: %w", item.ID, err)
+		}
+
+		err = c.schedules.WithTx(tx).MarkSent(ctx, item.ID, msg.ID)
+		if err != nil {
+			return 0, fmt.Errorf("Chat.Service.SendDueScheduled marking %d sent: %w", item.ID, err)
+		}
+
+		sent = append(sent, msg)
+	}
+
+	return len(due), nil
+}
+
+// sendScheduled runs the same checks as a live message; rate limits and slow mode do not apply
+// because the sender is not present.
+func (c *chatService) sendScheduled(ctx context.Context, item schedule.Message) (chatDomain.Message, error) {
+	sender, err := c.auth.GetUserById(ctx, item.UserID)
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	err = c.checkBan(ctx, item.ChatID, item.UserID)
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	msg, _, err := c.prepareMessage(ctx, sender, item.ChatID, item.Text, chatDomain.KindText, 0)
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	return msg, nil
+}
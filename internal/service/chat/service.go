@@ -6,23 +6,43 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/monobearotaku/online-chat-api/internal/domain"
 	"github.com/monobearotaku/online-chat-api/internal/kafka/producer"
 	"github.com/monobearotaku/online-chat-api/internal/pkg/slices"
+	filterService "github.com/monobearotaku/online-chat-api/internal/service/filter"
+	rateLimitService "github.com/monobearotaku/online-chat-api/internal/service/ratelimit"
 	"github.com/monobearotaku/online-chat-api/internal/service/tokenizer"
 	chatv1 "github.com/monobearotaku/online-chat-api/proto/chat/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	commandDomain "github.com/monobearotaku/online-chat-api/internal/domain/command"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	filterDomain "github.com/monobearotaku/online-chat-api/internal/domain/filter"
+	pollDomain "github.com/monobearotaku/online-chat-api/internal/domain/poll"
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
 	"github.com/monobearotaku/online-chat-api/internal/domain/token"
+	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
 
 	"github.com/monobearotaku/online-chat-api/internal/postgres"
 	"github.com/monobearotaku/online-chat-api/internal/repository/auth"
 	"github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	"github.com/monobearotaku/online-chat-api/internal/repository/command"
+	"github.com/monobearotaku/online-chat-api/internal/repository/moderation"
+	"github.com/monobearotaku/online-chat-api/internal/repository/poll"
+	"github.com/monobearotaku/online-chat-api/internal/repository/report"
+	"github.com/monobearotaku/online-chat-api/internal/repository/schedule"
 )
 
 type connection struct {
@@ -33,32 +53,80 @@ type connection struct {
 
 type connections []connection
 
-type chatService struct {
-	chat chat.Repo
-	auth auth.Repo
+// lockedStream serializes sends: replies from the receiving goroutine and fan-out goroutines share one stream,
+// and gRPC does not allow concurrent sends on a stream.
+type lockedStream struct {
+	chatv1.ChatService_ConnectToChatServer
+	mu sync.Mutex
+}
+
+func (l *lockedStream) Send(resp *chatv1.ChatMessageResponse) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
+	return l.ChatService_ConnectToChatServer.Send(resp)
+}
+
+type chatService struct {
+	chat       chat.Repo
+	auth       auth.Repo
+	commands   command.Repo
+	moderation moderation.Repo
+	reports    report.Repo
+	schedules  schedule.Repo
+	polls      poll.Repo
+
+	limiter    rateLimitService.Service
+	filters    filterService.Service
 	tokenizer  tokenizer.Tokenizer
 	txBeginner postgres.TxBeginner
 
 	producer producer.Producer
+	logger   log.Logger
+
+	// pollToProto is the API's poll mapping, used for polls pushed to streams.
+	pollToProto func(pollDomain.Poll) *chatv1.Poll
 
 	chatIdToStream map[int64]connections
 	clientToChatId map[int64]map[int64]struct{}
 
 	mu *sync.Mutex
+
+	registry   map[string]commandDomain.Command
+	registryMu *sync.RWMutex
+
+	now func() time.Time
 }
 
-func NewChatService(chat chat.Repo, auth auth.Repo, tokenizer tokenizer.Tokenizer, producer producer.Producer, txBeginner postgres.TxBeginner) Service {
-	return &chatService{
+func NewChatService(chat chat.Repo, auth auth.Repo, commands command.Repo, moderation moderation.Repo, reports report.Repo, schedules schedule.Repo, polls poll.Repo, limiter rateLimitService.Service, filters filterService.Service, tokenizer tokenizer.Tokenizer, producer producer.Producer, txBeginner postgres.TxBeginner, logger log.Logger, pollToProto func(pollDomain.Poll) *chatv1.Poll) Service {
+	service := &chatService{
 		chat:           chat,
 		auth:           auth,
+		commands:       commands,
+		moderation:     moderation,
+		reports:        reports,
+		schedules:      schedules,
+		polls:          polls,
+		limiter:        limiter,
+		filters:        filters,
 		tokenizer:      tokenizer,
 		txBeginner:     txBeginner,
 		producer:       producer,
+		logger:         logger,
+		pollToProto:    pollToProto,
 		chatIdToStream: make(map[int64]connections),
 		clientToChatId: make(map[int64]map[int64]struct{}),
 		mu:             &sync.Mutex{},
+		registry:       make(map[string]commandDomain.Command),
+		registryMu:     &sync.RWMutex{},
+		now:            time.Now,
+	}
+
+	for _, cmd := range service.builtins() {
+		service.registry[cmd.Name()] = cmd
 	}
+
+	return service
 }
 
 func (c *chatService) CreateChat(ctx context.Context, ownerID int64, name string) (newChat chatDomain.Chat, err error) {
@@ -131,6 +199,11 @@ func (c *chatService) AddUserToChat(ctx context.Context, ownerID int64, chatID i
 		return chatDomain.ErrUserNotOwner
 	}
 
+	err = c.checkBan(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
 	_, err = c.auth.GetUserById(ctx, userID)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
@@ -145,28 +218,58 @@ func (c *chatService) AddUserToChat(ctx context.Context, ownerID int64, chatID i
 		return fmt.Errorf("Chat.Service.AddUserToChat failed to add user to chat: %w", err)
 	}
 
+	c.publish(ctx, event.NewMemberEvent(event.MemberAdded, chatID, event.Member{
+		UserID: userID,
+		Role:   chatDomain.Member,
+	}))
+
 	return nil
 }
 
 func (c *chatService) JoinChat(ctx context.Context, userID, chatID int64) (token.Token, error) {
-	err := c.ValidateChat(ctx, userID, chatID)
+	err := c.checkBan(ctx, chatID, userID)
 	if err != nil {
 		return "", err
 	}
 
-	return c.tokenizer.CreateToken(ctx, map[string]string{
-		"userID": strconv.FormatInt(userID, 10),
-		"chatID": strconv.FormatInt(chatID, 10),
+	err = c.ValidateChat(ctx, userID, chatID)
+	if err != nil {
+		return "", err
+	}
+
+	usr, err := c.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", err
+		}
+
+		return "", fmt.Errorf("Chat.Service.JoinChat failed to get user: %w", err)
+	}
+
+	return c.tokenizer.CreateToken(ctx, token.AudienceSession, strconv.FormatInt(userID, 10), data.TokenData{
+		data.ChatIDKey:  strconv.FormatInt(chatID, 10),
+		data.VersionKey: strconv.FormatInt(usr.TokenVersion, 10),
 	})
 }
 
 func (c *chatService) StartMessaging(ctx context.Context, userID, chatID int64, stream chatv1.ChatService_ConnectToChatServer) error {
+	err := c.checkBan(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = c.ValidateChat(ctx, userID, chatID)
+	if err != nil {
+		return err
+	}
+
 	currentUser, err := c.auth.GetUserById(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("Chat.Service.StartMessaging failed to get user id:%w", err)
 	}
 
 	userUuid := uuid.NewString()
+	stream = &lockedStream{ChatService_ConnectToChatServer: stream}
 
 	c.addConnection(stream, userID, chatID, userUuid)
 	defer c.removeConnection(userID, userUuid)
@@ -185,46 +288,315 @@ func (c *chatService) StartMessaging(ctx context.Context, userID, chatID int64,
 			continue
 		}
 
-		domainMsg := chatDomain.Message{
-			UserID: currentUser.ID,
-			ChatID: chatID,
-			Msg:    msg.Message,
-			Login:  currentUser.Login.String(),
+		err = c.limiter.Take(ctx, ratelimit.UserKey(userID))
+		if err != nil {
+			if !c.replyError(stream, chatID, err) {
+				return fmt.Errorf("Chat.Service.StartMessaging: %w", err)
+			}
+
+			continue
 		}
 
-		err = c.chat.SaveMessage(ctx, domainMsg)
-		if err != nil {
-			return fmt.Errorf("Chat.Service.StartMessaging failed to save msg:%w", err)
+		if name, args, ok := commandDomain.Parse(msg.Message); ok {
+			err = c.runCommand(ctx, currentUser, chatID, name, args, stream)
+		} else {
+			_, err = c.postMessage(ctx, currentUser, chatID, commandDomain.Unescape(msg.Message), chatDomain.KindText, userUuid, msg.Ttl.AsDuration())
 		}
 
-		err = c.producer.Produce(ctx, userUuid, domainMsg)
 		if err != nil {
-			fmt.Println("Chat.Service.StartMessaging failed to save msg:", err)
+			if !c.replyError(stream, chatID, err) {
+				return fmt.Errorf("Chat.Service.StartMessaging: %w", err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// replyError answers errors the sender can fix with a typed error response instead of closing the stream.
+func (c *chatService) replyError(stream chatv1.ChatService_ConnectToChatServer, chatID int64, err error) bool {
+	domainErr, ok := domain.AsError(err)
+	if !ok || errors.Is(err, chatDomain.ErrChatHaveNoUser) {
+		return false
+	}
+
+	streamErr := &chatv1.StreamError{
+		Reason:  domainErr.Reason(),
+		Message: domainErr.Error(),
+		Field:   domainErr.Field(),
+	}
+
+	if retryAfter := domainErr.RetryAfter(); retryAfter > 0 {
+		streamErr.RetryAfter = durationpb.New(retryAfter)
+	}
+
+	_ = stream.Send(&chatv1.ChatMessageResponse{
+		ChatId:    chatID,
+		Message:   domainErr.Error(),
+		Kind:      kindError,
+		Ephemeral: true,
+		Error:     streamErr,
+		CreatedAt: timestamppb.New(c.now()),
+	})
+
+	return true
+}
+
+func (c *chatService) PostMessage(ctx context.Context, userID, chatID int64, text string, ttl time.Duration) (chatDomain.Message, error) {
+	currentUser, err := c.auth.GetUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return chatDomain.Message{}, err
+		}
+
+		return chatDomain.Message{}, fmt.Errorf("Chat.Service.PostMessage failed to get user: %w", err)
+	}
+
+	err = c.limiter.Take(ctx, ratelimit.UserKey(userID))
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	msg, err := c.postMessage(ctx, currentUser, chatID, text, chatDomain.KindText, uuid.NewString(), ttl)
+	if err != nil {
+		return chatDomain.Message{}, fmt.Errorf("Chat.Service.PostMessage: %w", err)
+	}
+
+	return msg, nil
+}
+
+// postMessage checks the sender may write, then validates, stores and fans out a message;
+// key identifies the sending connection, which is skipped on delivery, and a non-zero ttl makes the message disappear.
+func (c *chatService) postMessage(ctx context.Context, sender user.User, chatID int64, text string, kind chatDomain.MessageKind, key string, ttl time.Duration) (chatDomain.Message, error) {
+	msg, member, err := c.prepareMessage(ctx, sender, chatID, text, kind, ttl)
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	msg, err = c.saveMessage(ctx, msg, member, ttl)
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	err = c.producer.Produce(ctx, key, event.NewMessageEvent(event.MessageCreated, msg))
+	if err != nil {
+		level.Error(c.logger).Log("error", fmt.Errorf("Chat.Service failed to produce msg: %w", err))
+	}
+
+	return msg, nil
+}
+
+// prepareMessage validates a message, checks the sender is an unmuted member and runs the moderation filters.
+func (c *chatService) prepareMessage(ctx context.Context, sender user.User, chatID int64, text string, kind chatDomain.MessageKind, ttl time.Duration) (chatDomain.Message, chatDomain.ChatUser, error) {
+	msg := chatDomain.Message{
+		UserID: sender.ID,
+		ChatID: chatID,
+		Msg:    text,
+		Login:  sender.Login.String(),
+		Bot:    sender.IsBot,
+		Kind:   kind,
+	}
+
+	err := msg.Validate()
+	if err != nil {
+		return chatDomain.Message{}, chatDomain.ChatUser{}, err
+	}
+
+	err = chatDomain.ValidateTTL(ttl)
+	if err != nil {
+		return chatDomain.Message{}, chatDomain.ChatUser{}, err
+	}
+
+	chtUsers, err := c.chat.GetChatUsers(ctx, chatID)
+	if err != nil {
+		return chatDomain.Message{}, chatDomain.ChatUser{}, fmt.Errorf("failed to get chat users: %w", err)
+	}
+
+	member, ok := chtUsers.Get(sender.ID)
+	if !ok {
+		return chatDomain.Message{}, chatDomain.ChatUser{}, chatDomain.ErrChatHaveNoUser
+	}
+
+	if now := c.now(); member.Muted(now) {
+		return chatDomain.Message{}, chatDomain.ChatUser{}, chatDomain.ErrUserMuted.WithRetryAfter(member.MutedUntil.Sub(now))
+	}
+
+	msg.Msg, err = c.filters.Check(ctx, filterDomain.Message{
+		ChatID: chatID,
+		UserID: sender.ID,
+		Text:   msg.Msg,
+	})
+	if err != nil {
+		return chatDomain.Message{}, chatDomain.ChatUser{}, err
+	}
+
+	return msg, member, nil
+}
+
+// saveMessage takes the sender's chat slot and stores the message in one transaction, so a message
+// that is rate limited or fails to save does not use up the sender's slow mode interval.
+func (c *chatService) saveMessage(ctx context.Context, msg chatDomain.Message, member chatDomain.ChatUser, ttl time.Duration) (saved chatDomain.Message, err error) {
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return chatDomain.Message{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	err = c.takeChatSlot(ctx, c.chat.WithTx(tx), msg.ChatID, member)
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	saved, err = c.chat.WithTx(tx).SaveMessage(ctx, msg, ttl)
+	if err != nil {
+		return chatDomain.Message{}, fmt.Errorf("failed to save msg: %w", err)
+	}
+
+	return saved, nil
+}
+
+// takeChatSlot applies slow mode to non-owners and then the chat's shared rate limit.
+// Slow mode is checked first so a rejected sender does not spend the chat's tokens; it must run in the
+// transaction that saves the message, which rolls the slow mode slot back if the message is not saved.
+func (c *chatService) takeChatSlot(ctx context.Context, chatRepo chat.Repo, chatID int64, member chatDomain.ChatUser) error {
+	if member.Role != chatDomain.Owner {
+		now := c.now()
+
+		nextAt, ok, err := chatRepo.TouchLastMessage(ctx, chatID, member.UserID, now)
+		if err != nil {
+			return fmt.Errorf("failed to check slow mode: %w", err)
+		}
+
+		if !ok {
+			return chatDomain.ErrSlowMode.WithRetryAfter(nextAt.Sub(now).Round(time.Second))
+		}
+	}
+
+	return c.limiter.Take(ctx, ratelimit.ChatKey(chatID))
+}
+
+func (c *chatService) publish(ctx context.Context, evt event.Event) {
+	err := c.producer.Produce(ctx, evt.ID, evt)
+	if err != nil {
+		level.Error(c.logger).Log("error", fmt.Errorf("Chat.Service failed to produce event: %w", err))
+	}
+}
+
 func (c *chatService) SendMessage(ctx context.Context, uuid string, msg chatDomain.Message) {
 	go func() {
-		c.sendMsg(uuid, msg)
+		c.sendMsg(uuid, msg, nil)
 	}()
 }
 
-func (c *chatService) sendMsg(uuid string, msg chatDomain.Message) {
+// SendEvent delivers an event consumed from Kafka to the streams connected to this replica.
+func (c *chatService) SendEvent(ctx context.Context, key string, evt event.Event) {
+	switch evt.Type {
+	case event.MessageCreated:
+		if evt.Message != nil {
+			go c.sendMsg(key, *evt.Message, evt.Poll)
+		}
+	case event.PollUpdated:
+		if evt.Poll != nil {
+			go c.broadcast(evt.ChatID, "", &chatv1.ChatMessageResponse{
+				ChatId:    evt.ChatID,
+				MessageId: evt.Poll.MessageID,
+				Kind:      kindPollUpdate,
+				Poll:      c.pollToProto(*evt.Poll),
+				CreatedAt: timestamppb.New(evt.OccurredAt),
+			})
+		}
+	case event.MessageDeleted:
+		if evt.Message != nil {
+			go c.broadcast(evt.ChatID, "", &chatv1.ChatMessageResponse{
+				ChatId:    evt.ChatID,
+				MessageId: evt.Message.ID,
+				Kind:      kindDeleted,
+				CreatedAt: timestamppb.New(evt.OccurredAt),
+			})
+		}
+	case event.Notice:
+		go c.broadcast(evt.ChatID, "", &chatv1.ChatMessageResponse{
+			ChatId:    evt.ChatID,
+			Message:   evt.Notice,
+			Kind:      kindNotice,
+			CreatedAt: timestamppb.New(evt.OccurredAt),
+		})
+	case event.CommandInvoked:
+		if evt.Command != nil {
+			go c.sendToUser(evt.ChatID, evt.Command.BotID, &chatv1.ChatMessageResponse{
+				ChatId:    evt.ChatID,
+				UserId:    evt.Command.CallerID,
+				Login:     evt.Command.CallerLogin,
+				Message:   strings.TrimSpace(commandDomain.Prefix + evt.Command.Name + " " + evt.Command.Args),
+				Kind:      kindCommand,
+				Ephemeral: true,
+				CreatedAt: timestamppb.New(evt.OccurredAt),
+			})
+		}
+	case event.MemberRemoved:
+		if evt.Member != nil {
+			c.dropMember(evt.ChatID, evt.Member.UserID)
+		}
+	}
+}
+
+// sendMsg delivers a new message; p is set when the message is a poll.
+func (c *chatService) sendMsg(uuid string, msg chatDomain.Message, p *pollDomain.Poll) {
+	kind := msg.Kind
+	if kind == "" {
+		kind = chatDomain.KindText
+	}
+
+	resp := &chatv1.ChatMessageResponse{
+		Message:   msg.Msg,
+		UserId:    msg.UserID,
+		ChatId:    msg.ChatID,
+		Login:     msg.Login,
+		Bot:       msg.Bot,
+		MessageId: msg.ID,
+		CreatedAt: timestamppb.New(msg.CreatedAt),
+		Kind:      string(kind),
+	}
+
+	if !msg.ExpiresAt.IsZero() {
+		resp.ExpiresAt = timestamppb.New(msg.ExpiresAt)
+	}
+
+	if p != nil {
+		resp.Poll = c.pollToProto(*p)
+	}
+
+	c.broadcast(msg.ChatID, uuid, resp)
+}
+
+func (c *chatService) broadcast(chatID int64, skipUuid string, resp *chatv1.ChatMessageResponse) {
 	c.mu.Lock()
-	allConnections := c.chatIdToStream[msg.ChatID]
+	allConnections := c.chatIdToStream[chatID]
 	c.mu.Unlock()
 
 	for _, connect := range allConnections {
-		if connect.userUuid != uuid {
-			connect.stream.Send(&chatv1.ChatMessageResponse{
-				Message: msg.Msg,
-				UserId:  msg.UserID,
-				ChatId:  msg.ChatID,
-				Login:   msg.Login,
-			})
+		if connect.userUuid != skipUuid {
+			connect.stream.Send(resp)
+		}
+	}
+}
+
+func (c *chatService) sendToUser(chatID, userID int64, resp *chatv1.ChatMessageResponse) {
+	c.mu.Lock()
+	allConnections := c.chatIdToStream[chatID]
+	c.mu.Unlock()
+
+	for _, connect := range allConnections {
+		if connect.userID == userID {
+			connect.stream.Send(resp)
 		}
 	}
 }
@@ -245,6 +617,26 @@ func (c *chatService) addConnection(stream chatv1.ChatService_ConnectToChatServe
 	})
 }
 
+// dropMember stops delivering a chat to a removed member; their next send is rejected.
+func (c *chatService) dropMember(chatID, userID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conns := c.chatIdToStream[chatID]
+
+	conns = slices.RemoveFunc(conns, func(i int) bool {
+		return conns[i].userID == userID
+	})
+
+	if len(conns) == 0 {
+		delete(c.chatIdToStream, chatID)
+	} else {
+		c.chatIdToStream[chatID] = conns
+	}
+
+	delete(c.clientToChatId[userID], chatID)
+}
+
 func (c *chatService) removeConnection(userID int64, userUuid string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
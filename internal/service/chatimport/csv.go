@@ -0,0 +1,142 @@
+package chatimport
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+)
+
+type csvSource struct {
+	chat     chatimport.Chat
+	messages []chatimport.Message
+}
+
+// NewCSVSource reads one chat from a CSV file with a header row naming the timestamp, user and text columns
+// and an optional id column. Timestamps are RFC 3339 or Unix seconds. Without ids, a message is identified by
+// its content and how often the same content appeared before it, so re-running an appended file is safe.
+func NewCSVSource(r io.Reader, chatName string) (Source, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("missing header: %w", chatimport.ErrInvalidRecord)
+		}
+
+		return nil, errors.Join(chatimport.ErrInvalidRecord, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"timestamp", "user", "text"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %s column: %w", name, chatimport.ErrInvalidRecord)
+		}
+	}
+
+	idColumn, hasID := columns["id"]
+
+	s := &csvSource{
+		chat: chatimport.Chat{
+			ExternalID: chatName,
+			Name:       chatName,
+		},
+	}
+
+	members := make(map[string]bool)
+	seen := make(map[string]int)
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(chatimport.ErrInvalidRecord, err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		field := func(name string) string {
+			i := columns[name]
+			if i >= len(record) {
+				return ""
+			}
+
+			return record[i]
+		}
+
+		user := strings.TrimSpace(field("user"))
+		text := field("text")
+
+		if user == "" || strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("line %d: empty user or text: %w", line, chatimport.ErrInvalidRecord)
+		}
+
+		createdAt, err := parseCSVTime(strings.TrimSpace(field("timestamp")))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var externalID string
+		if hasID && idColumn < len(record) && record[idColumn] != "" {
+			externalID = record[idColumn]
+		} else {
+			sum := sha256.Sum256([]byte(field("timestamp") + "\x00" + user + "\x00" + text))
+			key := hex.EncodeToString(sum[:16])
+			externalID = key + "#" + strconv.Itoa(seen[key])
+			seen[key]++
+		}
+
+		if !members[user] {
+			members[user] = true
+			s.chat.Members = append(s.chat.Members, user)
+		}
+
+		s.messages = append(s.messages, chatimport.Message{
+			ExternalID: externalID,
+			UserID:     user,
+			Text:       text,
+			Kind:       chat.KindText,
+			CreatedAt:  createdAt,
+		})
+	}
+
+	return s, nil
+}
+
+func (s *csvSource) Chats() []chatimport.Chat {
+	return []chatimport.Chat{s.chat}
+}
+
+func (s *csvSource) Messages(c chatimport.Chat) ([]chatimport.Message, error) {
+	return s.messages, nil
+}
+
+// User treats the user column as both the external ID and the name.
+func (s *csvSource) User(externalID string) chatimport.User {
+	return chatimport.User{ExternalID: externalID, Name: externalID}
+}
+
+func parseCSVTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t.UTC(), nil
+	}
+
+	return parseUnixTime(value)
+}
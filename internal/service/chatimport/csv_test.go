@@ -0,0 +1,88 @@
+package chatimport
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CSVSource(t *testing.T) {
+	t.Parallel()
+
+	src, err := NewCSVSource(strings.NewReader(
+		"Timestamp,User,Text,ID\n"+
+			"2024-04-13T18:05:41+02:00,alice,hello,m1\n"+
+			"1712966400.5,bob,\"multi\nline\",m2\n",
+	), "team")
+	require.NoError(t, err)
+
+	chats := src.Chats()
+	require.Equal(t, []chatimport.Chat{
+		{ExternalID: "team", Name: "team", Members: []string{"alice", "bob"}},
+	}, chats)
+
+	messages, err := src.Messages(chats[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []chatimport.Message{
+		{ExternalID: "m1", UserID: "alice", Text: "hello", Kind: chat.KindText, CreatedAt: time.Date(2024, 4, 13, 16, 5, 41, 0, time.UTC)},
+		{ExternalID: "m2", UserID: "bob", Text: "multi\nline", Kind: chat.KindText, CreatedAt: time.Unix(1712966400, int64(500*time.Millisecond)).UTC()},
+	}, messages)
+}
+
+func Test_CSVSource_DerivedIDs(t *testing.T) {
+	t.Parallel()
+
+	const rows = "timestamp,user,text\n" +
+		"1712966400,alice,+1\n" +
+		"1712966400,alice,+1\n"
+
+	first, err := NewCSVSource(strings.NewReader(rows), "team")
+	require.NoError(t, err)
+
+	appended, err := NewCSVSource(strings.NewReader(rows+"1712966500,bob,ok\n"), "team")
+	require.NoError(t, err)
+
+	firstMessages, err := first.Messages(first.Chats()[0])
+	require.NoError(t, err)
+
+	appendedMessages, err := appended.Messages(appended.Chats()[0])
+	require.NoError(t, err)
+
+	require.Len(t, firstMessages, 2)
+	require.Len(t, appendedMessages, 3)
+
+	assert.NotEqual(t, firstMessages[0].ExternalID, firstMessages[1].ExternalID, "repeated rows stay distinct")
+	assert.Equal(t, firstMessages[0].ExternalID, appendedMessages[0].ExternalID, "ids are stable across runs")
+	assert.Equal(t, firstMessages[1].ExternalID, appendedMessages[1].ExternalID, "ids are stable across runs")
+}
+
+func Test_NewCSVSource_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty file", input: ""},
+		{name: "missing column", input: "timestamp,user\n1712966400,alice\n"},
+		{name: "bad timestamp", input: "timestamp,user,text\nyesterday,alice,hi\n"},
+		{name: "empty text", input: "timestamp,user,text\n1712966400,alice,\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewCSVSource(strings.NewReader(tt.input), "team")
+			assert.ErrorIs(t, err, chatimport.ErrInvalidRecord)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package chatimport
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+)
+
+type Service interface {
+	// Import copies every chat of src; running it again only adds what the previous runs did not.
+	Import(ctx context.Context, src Source, opts chatimport.Options) (chatimport.Report, error)
+}
+
+// Source reads an export of another chat tool.
+type Source interface {
+	Chats() []chatimport.Chat
+	// Messages reads one chat's messages; they need not be in order.
+	Messages(chat chatimport.Chat) ([]chatimport.Message, error)
+	// User describes an external user ID seen in a chat or message.
+	User(externalID string) chatimport.User
+}
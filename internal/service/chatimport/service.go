@@ -0,0 +1,349 @@
+package chatimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	authRepo "github.com/monobearotaku/online-chat-api/internal/repository/auth"
+	chatRepo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	chatImportRepo "github.com/monobearotaku/online-chat-api/internal/repository/chatimport"
+)
+
+const (
+	batchSize = 500
+	// maxLoginAttempts bounds the search for a free placeholder login.
+	maxLoginAttempts = 50
+)
+
+type chatImportService struct {
+	imports    chatImportRepo.Repo
+	chat       chatRepo.Repo
+	auth       authRepo.Repo
+	txBeginner postgres.TxBeginner
+}
+
+func NewChatImportService(imports chatImportRepo.Repo, chat chatRepo.Repo, auth authRepo.Repo, txBeginner postgres.TxBeginner) Service {
+	return &chatImportService{
+		imports:    imports,
+		chat:       chat,
+		auth:       auth,
+		txBeginner: txBeginner,
+	}
+}
+
+// run holds the state of one Import call.
+type run struct {
+	src     Source
+	opts    chatimport.Options
+	ownerID int64
+	users   map[string]int64
+	report  chatimport.Report
+}
+
+// Import writes history straight to the database: imported messages are not filtered, rate limited or pushed
+// to live streams and webhooks. Messages are saved in batches, each in its own transaction, so an interrupted
+// import resumes where it stopped when it is run again.
+func (c *chatImportService) Import(ctx context.Context, src Source, opts chatimport.Options) (chatimport.Report, error) {
+	owner, err := c.auth.GetUser(ctx, opts.Owner)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return chatimport.Report{}, chatimport.ErrOwnerNotFound
+		}
+
+		return chatimport.Report{}, fmt.Errorf("ChatImport.Service.Import getting owner: %w", err)
+	}
+
+	r := &run{
+		src:     src,
+		opts:    opts,
+		ownerID: owner.ID,
+		users:   make(map[string]int64),
+	}
+
+	for _, cht := range src.Chats() {
+		err = c.importChat(ctx, r, cht)
+		if err != nil {
+			return r.report, fmt.Errorf("ChatImport.Service.Import chat %q: %w", cht.Name, err)
+		}
+
+		r.report.Chats++
+	}
+
+	return r.report, nil
+}
+
+func (c *chatImportService) importChat(ctx context.Context, r *run, cht chatimport.Chat) error {
+	chatID, err := c.ensureChat(ctx, r, cht)
+	if err != nil {
+		return err
+	}
+
+	messages, err := r.src.Messages(cht)
+	if err != nil {
+		return fmt.Errorf("reading messages: %w", err)
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+
+	members := make([]string, 0, len(cht.Members)+len(messages))
+	members = append(members, cht.Members...)
+
+	for _, msg := range messages {
+		members = append(members, msg.UserID)
+	}
+
+	added := make(map[string]bool, len(members))
+
+	for _, externalID := range members {
+		if added[externalID] {
+			continue
+		}
+
+		added[externalID] = true
+
+		userID, ok := r.users[externalID]
+		if !ok {
+			userID, err = c.resolveUser(ctx, r, externalID)
+			if err != nil {
+				return fmt.Errorf("mapping user %q: %w", externalID, err)
+			}
+		}
+
+		err = c.chat.AddUserToChat(ctx, chatID, userID, chatDomain.Member)
+		if err != nil {
+			return fmt.Errorf("adding member: %w", err)
+		}
+	}
+
+	for start := 0; start < len(messages); start += batchSize {
+		end := min(start+batchSize, len(messages))
+
+		err = c.saveBatch(ctx, r, chatID, messages[start:end])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureChat returns the chat an earlier run created, or creates it owned by the importing account.
+func (c *chatImportService) ensureChat(ctx context.Context, r *run, cht chatimport.Chat) (chatID int64, err error) {
+	chatID, ok, err := c.imports.GetChat(ctx, r.opts.Source, cht.ExternalID)
+	if err != nil {
+		return 0, fmt.Errorf("getting imported chat: %w", err)
+	}
+
+	if ok {
+		return chatID, nil
+	}
+
+	name := r.opts.ChatPrefix + cht.Name
+	if utf8.RuneCountInString(name) == 0 {
+		return 0, chatDomain.ErrChatInvalidName
+	}
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			r.report.ChatsCreated++
+		}
+	}()
+
+	created, err := c.chat.WithTx(tx).CreateChat(ctx, chatDomain.Chat{Name: name})
+	if err != nil {
+		return 0, fmt.Errorf("creating chat %q: %w", name, err)
+	}
+
+	err = c.chat.WithTx(tx).AddUserToChat(ctx, created.ID, r.ownerID, chatDomain.Owner)
+	if err != nil {
+		return 0, fmt.Errorf("adding owner: %w", err)
+	}
+
+	if cht.Topic != "" {
+		err = c.chat.WithTx(tx).SetTopic(ctx, created.ID, truncate(cht.Topic, chatDomain.MaxTopicLength))
+		if err != nil {
+			return 0, fmt.Errorf("setting topic: %w", err)
+		}
+	}
+
+	err = c.imports.WithTx(tx).LinkChat(ctx, r.opts.Source, cht.ExternalID, created.ID)
+	if err != nil {
+		return 0, fmt.Errorf("linking chat: %w", err)
+	}
+
+	return created.ID, nil
+}
+
+// resolveUser maps a foreign user, in order, through the explicit account mapping, an earlier run,
+// an account with the same login when MatchLogins is set, and finally a new placeholder account.
+// Deleted accounts are skipped at every step: auth.GetUser and the import mapping both ignore them.
+func (c *chatImportService) resolveUser(ctx context.Context, r *run, externalID string) (int64, error) {
+	u := r.src.User(externalID)
+
+	login, ok := r.opts.Accounts[externalID]
+	if !ok {
+		login, ok = r.opts.Accounts[u.Name]
+	}
+
+	if ok {
+		account, err := c.auth.GetUser(ctx, login)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return 0, fmt.Errorf("%s: %w", login, chatimport.ErrAccountMissing)
+			}
+
+			return 0, err
+		}
+
+		return c.linkUser(ctx, r, externalID, account.ID, false)
+	}
+
+	userID, ok, err := c.imports.GetUser(ctx, r.opts.Source, externalID)
+	if err != nil {
+		return 0, err
+	}
+
+	if ok {
+		r.users[externalID] = userID
+
+		return userID, nil
+	}
+
+	if r.opts.MatchLogins {
+		account, err := c.auth.GetUser(ctx, domain.Login(u.Name))
+		if err == nil {
+			return c.linkUser(ctx, r, externalID, account.ID, false)
+		}
+
+		if !errors.Is(err, domain.ErrNotFound) {
+			return 0, err
+		}
+	}
+
+	return c.createPlaceholder(ctx, r, u)
+}
+
+func (c *chatImportService) linkUser(ctx context.Context, r *run, externalID string, userID int64, placeholder bool) (int64, error) {
+	err := c.imports.LinkUser(ctx, r.opts.Source, externalID, userID, placeholder)
+	if err != nil {
+		return 0, err
+	}
+
+	r.users[externalID] = userID
+
+	return userID, nil
+}
+
+// createPlaceholder adds the account and its mapping together, so a crash never leaves an unmapped placeholder.
+func (c *chatImportService) createPlaceholder(ctx context.Context, r *run, u chatimport.User) (userID int64, err error) {
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			r.users[u.ExternalID] = userID
+			r.report.Placeholders++
+		}
+	}()
+
+	for attempt := 0; attempt < maxLoginAttempts; attempt++ {
+		var ok bool
+
+		userID, ok, err = c.imports.WithTx(tx).CreatePlaceholder(ctx, chatimport.PlaceholderLogin(u.Name, attempt), u.Bot)
+		if err != nil {
+			return 0, fmt.Errorf("creating placeholder: %w", err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		err = c.imports.WithTx(tx).LinkUser(ctx, r.opts.Source, u.ExternalID, userID, true)
+		if err != nil {
+			return 0, fmt.Errorf("linking placeholder: %w", err)
+		}
+
+		return userID, nil
+	}
+
+	return 0, fmt.Errorf("no free login for %q after %d attempts", u.Name, maxLoginAttempts)
+}
+
+func (c *chatImportService) saveBatch(ctx context.Context, r *run, chatID int64, messages []chatimport.Message) (err error) {
+	var saved int
+
+	tx, err := c.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err == nil {
+			r.report.Messages += saved
+			r.report.Skipped += len(messages) - saved
+		}
+	}()
+
+	for _, msg := range messages {
+		ok, err := c.imports.WithTx(tx).SaveMessage(ctx, msg.ExternalID, chatDomain.Message{
+			ChatID:    chatID,
+			UserID:    r.users[msg.UserID],
+			Msg:       msg.Text,
+			Kind:      msg.Kind,
+			CreatedAt: msg.CreatedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("saving message %q: %w", msg.ExternalID, err)
+		}
+
+		if ok {
+			saved++
+		}
+	}
+
+	return nil
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+
+	return string(runes[:limit])
+}
@@ -0,0 +1,336 @@
+package chatimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	authRepo "github.com/monobearotaku/online-chat-api/internal/repository/auth"
+	chatRepo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	chatImportRepo "github.com/monobearotaku/online-chat-api/internal/repository/chatimport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSaveFailed = errors.New("connection reset")
+
+// store is an in-memory database; writes made in a transaction apply only when it commits.
+type store struct {
+	nextID int64
+
+	accounts map[domain.Login]int64
+	deleted  map[int64]bool
+
+	importedChats map[string]int64
+	importedUsers map[string]int64
+	// messages holds the imported external IDs of each chat.
+	messages map[int64]map[string]bool
+	members  map[int64]map[int64]bool
+
+	// failAt makes saving the message with this external ID fail once.
+	failAt string
+}
+
+func newStore(accounts ...domain.Login) *store {
+	s := &store{
+		accounts:      make(map[domain.Login]int64),
+		deleted:       make(map[int64]bool),
+		importedChats: make(map[string]int64),
+		importedUsers: make(map[string]int64),
+		messages:      make(map[int64]map[string]bool),
+		members:       make(map[int64]map[int64]bool),
+	}
+
+	for _, login := range accounts {
+		s.accounts[login] = s.id()
+	}
+
+	return s
+}
+
+func (s *store) id() int64 {
+	s.nextID++
+
+	return s.nextID
+}
+
+func (s *store) messageCount() int {
+	count := 0
+	for _, ids := range s.messages {
+		count += len(ids)
+	}
+
+	return count
+}
+
+type fakeTx struct {
+	postgres.QueryExecer
+
+	pending []func()
+}
+
+func (f *fakeTx) Commit(ctx context.Context) error {
+	for _, write := range f.pending {
+		write()
+	}
+
+	return nil
+}
+
+func (f *fakeTx) Rollback(ctx context.Context) error {
+	f.pending = nil
+
+	return nil
+}
+
+type fakeTxBeginner struct{}
+
+func (fakeTxBeginner) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (postgres.Tx, error) {
+	return &fakeTx{}, nil
+}
+
+// writer applies a write at once or queues it on the transaction the repo is bound to.
+type writer struct {
+	tx *fakeTx
+}
+
+func (w writer) write(apply func()) {
+	if w.tx == nil {
+		apply()
+		return
+	}
+
+	w.tx.pending = append(w.tx.pending, apply)
+}
+
+type fakeImports struct {
+	chatImportRepo.Repo
+	writer
+
+	st *store
+}
+
+func (f *fakeImports) WithTx(tx postgres.Tx) chatImportRepo.Repo {
+	return &fakeImports{st: f.st, writer: writer{tx: tx.(*fakeTx)}}
+}
+
+func (f *fakeImports) GetChat(ctx context.Context, source, externalID string) (int64, bool, error) {
+	chatID, ok := f.st.importedChats[source+"/"+externalID]
+
+	return chatID, ok, nil
+}
+
+func (f *fakeImports) LinkChat(ctx context.Context, source, externalID string, chatID int64) error {
+	f.write(func() { f.st.importedChats[source+"/"+externalID] = chatID })
+
+	return nil
+}
+
+func (f *fakeImports) GetUser(ctx context.Context, source, externalID string) (int64, bool, error) {
+	userID, ok := f.st.importedUsers[source+"/"+externalID]
+	if !ok || f.st.deleted[userID] {
+		return 0, false, nil
+	}
+
+	return userID, true, nil
+}
+
+func (f *fakeImports) LinkUser(ctx context.Context, source, externalID string, userID int64, placeholder bool) error {
+	f.write(func() { f.st.importedUsers[source+"/"+externalID] = userID })
+
+	return nil
+}
+
+func (f *fakeImports) CreatePlaceholder(ctx context.Context, login domain.Login, bot bool) (int64, bool, error) {
+	if _, ok := f.st.accounts[login]; ok {
+		return 0, false, nil
+	}
+
+	userID := f.st.id()
+	f.write(func() { f.st.accounts[login] = userID })
+
+	return userID, true, nil
+}
+
+func (f *fakeImports) SaveMessage(ctx context.Context, externalID string, msg chat.Message) (bool, error) {
+	if externalID == f.st.failAt {
+		f.st.failAt = ""
+
+		return false, errSaveFailed
+	}
+
+	if f.st.messages[msg.ChatID][externalID] {
+		return false, nil
+	}
+
+	f.write(func() { f.st.messages[msg.ChatID][externalID] = true })
+
+	return true, nil
+}
+
+type fakeChats struct {
+	chatRepo.Repo
+	writer
+
+	st *store
+}
+
+func (f *fakeChats) WithTx(tx postgres.Tx) chatRepo.Repo {
+	return &fakeChats{st: f.st, writer: writer{tx: tx.(*fakeTx)}}
+}
+
+func (f *fakeChats) CreateChat(ctx context.Context, cht chat.Chat) (chat.Chat, error) {
+	cht.ID = f.st.id()
+
+	f.write(func() {
+		f.st.messages[cht.ID] = make(map[string]bool)
+		f.st.members[cht.ID] = make(map[int64]bool)
+	})
+
+	return cht, nil
+}
+
+func (f *fakeChats) AddUserToChat(ctx context.Context, chatID int64, userID int64, role chat.Role) error {
+	f.write(func() { f.st.members[chatID][userID] = true })
+
+	return nil
+}
+
+func (f *fakeChats) SetTopic(ctx context.Context, chatID int64, topic string) error {
+	return nil
+}
+
+type fakeAuth struct {
+	authRepo.Repo
+
+	st *store
+}
+
+func (f *fakeAuth) GetUser(ctx context.Context, login domain.Login) (user.User, error) {
+	userID, ok := f.st.accounts[login]
+	if !ok || f.st.deleted[userID] {
+		return user.User{}, domain.ErrNotFound
+	}
+
+	return user.User{ID: userID, Login: login}, nil
+}
+
+func newService(st *store) Service {
+	return NewChatImportService(&fakeImports{st: st}, &fakeChats{st: st}, &fakeAuth{st: st}, fakeTxBeginner{})
+}
+
+// history builds a CSV chat of n messages alternating between alice and bob, with ids m1 to mn.
+func history(t *testing.T, n int) Source {
+	t.Helper()
+
+	var b strings.Builder
+
+	b.WriteString("timestamp,user,text,id\n")
+
+	for i := 1; i <= n; i++ {
+		author := "alice"
+		if i%2 == 0 {
+			author = "bob"
+		}
+
+		fmt.Fprintf(&b, "%d,%s,message %d,m%d\n", 1712966400+i, author, i, i)
+	}
+
+	src, err := NewCSVSource(strings.NewReader(b.String()), "team")
+	require.NoError(t, err)
+
+	return src
+}
+
+func Test_chatImportService_Import_Twice(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	st := newStore("admin")
+	opts := chatimport.Options{Source: "csv", Owner: "admin"}
+
+	first, err := newService(st).Import(ctx, history(t, 3), opts)
+	require.NoError(t, err)
+	assert.Equal(t, chatimport.Report{Chats: 1, ChatsCreated: 1, Placeholders: 2, Messages: 3}, first)
+
+	second, err := newService(st).Import(ctx, history(t, 3), opts)
+	require.NoError(t, err)
+	assert.Equal(t, chatimport.Report{Chats: 1, Skipped: 3}, second)
+
+	assert.Len(t, st.importedChats, 1)
+	assert.Equal(t, 3, st.messageCount())
+}
+
+func Test_chatImportService_Import_ResumesAfterFailedBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	st := newStore("admin")
+	opts := chatimport.Options{Source: "csv", Owner: "admin"}
+	total := 2*batchSize + 200
+
+	// The second batch fails part way through and is rolled back as a whole.
+	st.failAt = fmt.Sprintf("m%d", batchSize+10)
+
+	first, err := newService(st).Import(ctx, history(t, total), opts)
+	assert.ErrorIs(t, err, errSaveFailed)
+	assert.Equal(t, chatimport.Report{ChatsCreated: 1, Placeholders: 2, Messages: batchSize}, first)
+	assert.Equal(t, batchSize, st.messageCount())
+
+	second, err := newService(st).Import(ctx, history(t, total), opts)
+	require.NoError(t, err)
+	assert.Equal(t, chatimport.Report{Chats: 1, Messages: total - batchSize, Skipped: batchSize}, second)
+	assert.Equal(t, total, st.messageCount())
+}
+
+func Test_chatImportService_Import_MatchLogins(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	st := newStore("admin", "alice")
+	opts := chatimport.Options{Source: "csv", Owner: "admin", MatchLogins: true}
+	alice := st.accounts["alice"]
+
+	report, err := newService(st).Import(ctx, history(t, 2), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Placeholders, "alice matches her account, bob gets a placeholder")
+
+	chatID := st.importedChats["csv/team"]
+	assert.True(t, st.members[chatID][alice])
+
+	// Once alice deletes her account, a re-run maps her again instead of reusing the deleted one.
+	st.deleted[alice] = true
+	st.members[chatID] = make(map[int64]bool)
+
+	report, err = newService(st).Import(ctx, history(t, 2), opts)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Placeholders)
+	assert.False(t, st.members[chatID][alice])
+	assert.NotEqual(t, alice, st.importedUsers["csv/alice"])
+}
+
+func Test_chatImportService_Import_SameTimestamp(t *testing.T) {
+	t.Parallel()
+
+	st := newStore("admin")
+
+	src, err := NewCSVSource(strings.NewReader(
+		"timestamp,user,text,id\n"+
+			"1712966400,alice,first,m1\n"+
+			"1712966400,bob,second,m2\n",
+	), "team")
+	require.NoError(t, err)
+
+	report, err := newService(st).Import(context.Background(), src, chatimport.Options{Source: "csv", Owner: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Messages)
+	assert.Equal(t, 2, st.messageCount())
+}
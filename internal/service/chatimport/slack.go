@@ -0,0 +1,302 @@
+package chatimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+)
+
+type slackUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	IsBot bool   `json:"is_bot"`
+}
+
+type slackChannel struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	Topic   struct {
+		Value string `json:"value"`
+	} `json:"topic"`
+}
+
+type slackFile struct {
+	Name string `json:"name"`
+}
+
+type slackMessage struct {
+	Type     string      `json:"type"`
+	Subtype  string      `json:"subtype"`
+	User     string      `json:"user"`
+	BotID    string      `json:"bot_id"`
+	Username string      `json:"username"`
+	Text     string      `json:"text"`
+	Ts       string      `json:"ts"`
+	Files    []slackFile `json:"files"`
+}
+
+var (
+	slackEntity   = regexp.MustCompile(`<([^<>]+)>`)
+	slackUnescape = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+)
+
+type slackSource struct {
+	fsys  fs.FS
+	users map[string]chatimport.User
+	chats []chatimport.Chat
+}
+
+// NewSlackSource reads a Slack workspace export: users.json, channels.json and groups.json at the root and one
+// directory of daily message files per channel. An empty channels list imports every channel.
+func NewSlackSource(fsys fs.FS, channels []string) (Source, error) {
+	s := &slackSource{
+		fsys:  fsys,
+		users: make(map[string]chatimport.User),
+	}
+
+	var users []slackUser
+
+	err := readJSON(fsys, "users.json", &users)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		s.users[u.ID] = chatimport.User{
+			ExternalID: u.ID,
+			Name:       u.Name,
+			Bot:        u.IsBot,
+		}
+	}
+
+	// wanted tracks the requested channels not found yet.
+	wanted := make(map[string]bool, len(channels))
+	for _, name := range channels {
+		wanted[name] = true
+	}
+
+	filter := len(channels) > 0
+
+	// Private channels are exported to groups.json, which only admins' exports contain.
+	for _, file := range []string{"channels.json", "groups.json"} {
+		var list []slackChannel
+
+		err = readJSON(fsys, file, &list)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, channel := range list {
+			if filter && !wanted[channel.Name] {
+				continue
+			}
+
+			delete(wanted, channel.Name)
+
+			s.chats = append(s.chats, chatimport.Chat{
+				ExternalID: channel.ID,
+				Name:       channel.Name,
+				Topic:      channel.Topic.Value,
+				Members:    channel.Members,
+			})
+		}
+	}
+
+	for name := range wanted {
+		return nil, fmt.Errorf("channel %q is not in the export: %w", name, chatimport.ErrInvalidRecord)
+	}
+
+	return s, nil
+}
+
+func (s *slackSource) Chats() []chatimport.Chat {
+	return s.chats
+}
+
+func (s *slackSource) User(externalID string) chatimport.User {
+	u, ok := s.users[externalID]
+	if !ok {
+		return chatimport.User{ExternalID: externalID, Name: externalID}
+	}
+
+	return u
+}
+
+// Messages keeps conversation messages and drops channel events such as joins and topic changes.
+// Thread replies are imported inline.
+func (s *slackSource) Messages(c chatimport.Chat) ([]chatimport.Message, error) {
+	files, err := fs.Glob(s.fsys, path.Join(escapeGlob(c.Name), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]chatimport.Message, 0)
+
+	for _, file := range files {
+		var day []slackMessage
+
+		err = readJSON(s.fsys, file, &day)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range day {
+			msg, ok, err := s.message(m)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+
+			if ok {
+				messages = append(messages, msg)
+			}
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *slackSource) message(m slackMessage) (chatimport.Message, bool, error) {
+	if m.Type != "message" {
+		return chatimport.Message{}, false, nil
+	}
+
+	kind := chat.KindText
+
+	switch m.Subtype {
+	case "", "bot_message", "file_share", "thread_broadcast":
+	case "me_message":
+		kind = chat.KindAction
+	default:
+		return chatimport.Message{}, false, nil
+	}
+
+	userID := m.User
+	if userID == "" && m.BotID != "" {
+		userID = m.BotID
+
+		// Integrations post without a user, so their bot IDs are only known from messages.
+		if _, ok := s.users[userID]; !ok {
+			s.users[userID] = chatimport.User{ExternalID: userID, Name: m.Username, Bot: true}
+		}
+	}
+
+	if userID == "" {
+		return chatimport.Message{}, false, nil
+	}
+
+	createdAt, err := parseUnixTime(m.Ts)
+	if err != nil {
+		return chatimport.Message{}, false, err
+	}
+
+	lines := make([]string, 0, 1+len(m.Files))
+	if text := s.text(m.Text); text != "" {
+		lines = append(lines, text)
+	}
+
+	for _, file := range m.Files {
+		lines = append(lines, "[file: "+file.Name+"]")
+	}
+
+	if len(lines) == 0 {
+		return chatimport.Message{}, false, nil
+	}
+
+	return chatimport.Message{
+		ExternalID: m.Ts,
+		UserID:     userID,
+		Text:       strings.Join(lines, "\n"),
+		Kind:       kind,
+		CreatedAt:  createdAt,
+	}, true, nil
+}
+
+// text turns Slack markup into plain text: mentions become @name and #channel, links keep their label and URL.
+func (s *slackSource) text(raw string) string {
+	text := slackEntity.ReplaceAllStringFunc(raw, func(entity string) string {
+		target, label, _ := strings.Cut(entity[1:len(entity)-1], "|")
+
+		switch {
+		case strings.HasPrefix(target, "@"):
+			if u, ok := s.users[target[1:]]; ok {
+				return "@" + u.Name
+			}
+
+			if label != "" {
+				return "@" + label
+			}
+
+			return target
+		case strings.HasPrefix(target, "#"):
+			if label != "" {
+				return "#" + label
+			}
+
+			return target
+		case strings.HasPrefix(target, "!"):
+			if label != "" {
+				return label
+			}
+
+			return "@" + target[1:]
+		case label != "" && label != target:
+			return label + " (" + target + ")"
+		default:
+			return target
+		}
+	})
+
+	return strings.TrimSpace(slackUnescape.Replace(text))
+}
+
+// parseUnixTime reads Unix seconds with up to microsecond decimals, the form of Slack's 1700000000.123456.
+func parseUnixTime(ts string) (time.Time, error) {
+	secs, micros, _ := strings.Cut(ts, ".")
+
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp %q: %w", ts, chatimport.ErrInvalidRecord)
+	}
+
+	var usec int64
+	if micros != "" {
+		usec, err = strconv.ParseInt((micros + "000000")[:6], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("timestamp %q: %w", ts, chatimport.ErrInvalidRecord)
+		}
+	}
+
+	return time.Unix(sec, usec*int64(time.Microsecond)).UTC(), nil
+}
+
+func readJSON(fsys fs.FS, name string, v any) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, errors.Join(chatimport.ErrInvalidRecord, err))
+	}
+
+	return nil
+}
+
+func escapeGlob(name string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`).Replace(name)
+}
@@ -0,0 +1,87 @@
+package chatimport
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chatimport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var slackExport = fstest.MapFS{
+	"users.json": {Data: []byte(`[
+		{"id": "U1", "name": "alice"},
+		{"id": "U2", "name": "bob", "is_bot": false}
+	]`)},
+	"channels.json": {Data: []byte(`[
+		{"id": "C1", "name": "general", "members": ["U1", "U2"], "topic": {"value": "Company-wide"}},
+		{"id": "C2", "name": "random", "members": ["U1"]}
+	]`)},
+	"general/2024-04-14.json": {Data: []byte(`[
+		{"type": "message", "user": "U2", "text": "&lt;3 thanks", "ts": "1713052800.000200"}
+	]`)},
+	"general/2024-04-13.json": {Data: []byte(`[
+		{"type": "message", "subtype": "channel_join", "user": "U2", "text": "<@U2> has joined the channel", "ts": "1712966400.000100"},
+		{"type": "message", "user": "U1", "text": "hi <@U2>, see <#C2|random> and <https://example.com|the docs>", "ts": "1712966401.123456"},
+		{"type": "message", "subtype": "me_message", "user": "U1", "text": "waves", "ts": "1712966402.000000"},
+		{"type": "message", "subtype": "bot_message", "bot_id": "B1", "username": "deploybot", "text": "deployed <https://ci.example.com>", "ts": "1712966403.000000"},
+		{"type": "message", "user": "U2", "text": "", "files": [{"name": "notes.txt"}], "ts": "1712966404.000000"}
+	]`)},
+}
+
+func Test_SlackSource(t *testing.T) {
+	t.Parallel()
+
+	src, err := NewSlackSource(slackExport, []string{"general"})
+	require.NoError(t, err)
+
+	chats := src.Chats()
+	require.Equal(t, []chatimport.Chat{
+		{ExternalID: "C1", Name: "general", Topic: "Company-wide", Members: []string{"U1", "U2"}},
+	}, chats)
+
+	messages, err := src.Messages(chats[0])
+	require.NoError(t, err)
+
+	at := func(sec, usec int64) time.Time {
+		return time.Unix(sec, usec*int64(time.Microsecond)).UTC()
+	}
+
+	assert.Equal(t, []chatimport.Message{
+		{ExternalID: "1712966401.123456", UserID: "U1", Text: "hi @bob, see #random and the docs (https://example.com)", Kind: chat.KindText, CreatedAt: at(1712966401, 123456)},
+		{ExternalID: "1712966402.000000", UserID: "U1", Text: "waves", Kind: chat.KindAction, CreatedAt: at(1712966402, 0)},
+		{ExternalID: "1712966403.000000", UserID: "B1", Text: "deployed https://ci.example.com", Kind: chat.KindText, CreatedAt: at(1712966403, 0)},
+		{ExternalID: "1712966404.000000", UserID: "U2", Text: "[file: notes.txt]", Kind: chat.KindText, CreatedAt: at(1712966404, 0)},
+		{ExternalID: "1713052800.000200", UserID: "U2", Text: "<3 thanks", Kind: chat.KindText, CreatedAt: at(1713052800, 200)},
+	}, messages)
+
+	tests := []struct {
+		name       string
+		externalID string
+		want       chatimport.User
+	}{
+		{name: "listed user", externalID: "U1", want: chatimport.User{ExternalID: "U1", Name: "alice"}},
+		{name: "bot seen in messages", externalID: "B1", want: chatimport.User{ExternalID: "B1", Name: "deploybot", Bot: true}},
+		{name: "unknown user", externalID: "U9", want: chatimport.User{ExternalID: "U9", Name: "U9"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, src.User(tt.externalID))
+		})
+	}
+}
+
+func Test_NewSlackSource_MissingChannel(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSlackSource(slackExport, []string{"general", "secret"})
+	assert.ErrorIs(t, err, chatimport.ErrInvalidRecord)
+}
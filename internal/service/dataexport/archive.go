@@ -0,0 +1,56 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// archive writes a ZIP of JSON documents; entries are written one after another and cannot be reopened.
+type archive struct {
+	zw       *zip.Writer
+	modified time.Time
+}
+
+func newArchive(w io.Writer, modified time.Time) *archive {
+	return &archive{
+		zw:       zip.NewWriter(w),
+		modified: modified,
+	}
+}
+
+func (a *archive) create(name string) (io.Writer, error) {
+	return a.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: a.modified,
+	})
+}
+
+// addJSON writes v as one indented JSON document.
+func (a *archive) addJSON(name string, v any) error {
+	w, err := a.create(name)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// addLines opens a JSON Lines entry; the returned encoder is valid until the next entry is added.
+func (a *archive) addLines(name string) (*json.Encoder, error) {
+	w, err := a.create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.NewEncoder(w), nil
+}
+
+func (a *archive) close() error {
+	return a.zw.Close()
+}
@@ -0,0 +1,96 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Archive(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2024, 4, 13, 18, 5, 41, 0, time.UTC)
+
+	var buf bytes.Buffer
+
+	a := newArchive(&buf, modified)
+
+	require.NoError(t, a.addJSON("profile.json", dataexport.Profile{UserID: 3, Login: "alice", CreatedAt: modified}))
+
+	enc, err := a.addLines("messages.jsonl")
+	require.NoError(t, err)
+	require.NoError(t, enc.Encode(dataexport.Message{ID: 1, ChatID: 7, Text: "hello", Kind: "text", CreatedAt: modified}))
+	require.NoError(t, enc.Encode(dataexport.Message{ID: 5, ChatID: 7, Text: "waves", Kind: "action", CreatedAt: modified}))
+
+	require.NoError(t, a.addJSON("bans.json", []dataexport.Ban{}))
+	require.NoError(t, a.close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 3)
+
+	tests := []struct {
+		name  string
+		index int
+		file  string
+		want  string
+	}{
+		{
+			name:  "indented document",
+			index: 0,
+			file:  "profile.json",
+			want: `{
+  "userId": 3,
+  "login": "alice",
+  "displayName": "",
+  "avatarRef": "",
+  "bio": "",
+  "bot": false,
+  "createdAt": "2024-04-13T18:05:41Z",
+  "tokenVersion": 0
+}
+`,
+		},
+		{
+			name:  "one message per line",
+			index: 1,
+			file:  "messages.jsonl",
+			want: `{"id":1,"chatId":7,"text":"hello","kind":"text","createdAt":"2024-04-13T18:05:41Z"}
+{"id":5,"chatId":7,"text":"waves","kind":"action","createdAt":"2024-04-13T18:05:41Z"}
+`,
+		},
+		{
+			name:  "empty list",
+			index: 2,
+			file:  "bans.json",
+			want:  "[]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := zr.File[tt.index]
+			assert.Equal(t, tt.file, file.Name)
+			assert.True(t, modified.Equal(file.Modified))
+
+			rc, err := file.Open()
+			require.NoError(t, err)
+
+			defer rc.Close()
+
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package dataexport
+
+import (
+	"context"
+	"io"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+)
+
+type Service interface {
+	// Request queues an export of everything stored about the user.
+	Request(ctx context.Context, userID int64) (dataexport.Job, error)
+	GetJob(ctx context.Context, userID, jobID int64) (dataexport.Job, error)
+	// Download copies a ready archive to w.
+	Download(ctx context.Context, userID, jobID int64, w io.Writer) error
+	// ProcessNext builds the archive of one queued job and reports whether there was one.
+	ProcessNext(ctx context.Context) (bool, error)
+	// PurgeExpired deletes one batch of expired archives and returns how many it removed.
+	PurgeExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,337 @@
+package dataexport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+	dataExportRepo "github.com/monobearotaku/online-chat-api/internal/repository/dataexport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	messagePageSize = 500
+	purgeBatchSize  = 100
+	// failureReason is what users see for a failed job; the cause is only logged.
+	failureReason = "The archive could not be built, request a new export"
+)
+
+var jobsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "user_data_exports_total",
+	Help: "Number of user data export jobs processed, by result (ready, failed).",
+}, []string{"result"})
+
+type dataExportService struct {
+	exports dataExportRepo.Repo
+	dir     string
+	now     func() time.Time
+}
+
+// NewDataExportService stores finished archives under dir, which is created readable by the service only.
+func NewDataExportService(exports dataExportRepo.Repo, dir string) Service {
+	return &dataExportService{
+		exports: exports,
+		dir:     dir,
+		now:     time.Now,
+	}
+}
+
+func (d *dataExportService) Request(ctx context.Context, userID int64) (dataexport.Job, error) {
+	job, err := d.exports.CreateJob(ctx, userID)
+	if err != nil {
+		if errors.Is(err, dataexport.ErrInProgress) {
+			return dataexport.Job{}, err
+		}
+
+		return dataexport.Job{}, fmt.Errorf("DataExport.Service.Request creating job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (d *dataExportService) GetJob(ctx context.Context, userID, jobID int64) (dataexport.Job, error) {
+	job, err := d.exports.GetJob(ctx, userID, jobID)
+	if err != nil {
+		if errors.Is(err, dataexport.ErrNotFound) {
+			return dataexport.Job{}, err
+		}
+
+		return dataexport.Job{}, fmt.Errorf("DataExport.Service.GetJob: %w", err)
+	}
+
+	return job, nil
+}
+
+func (d *dataExportService) Download(ctx context.Context, userID, jobID int64, w io.Writer) error {
+	job, err := d.GetJob(ctx, userID, jobID)
+	if err != nil {
+		return err
+	}
+
+	if !job.Downloadable(d.now()) {
+		return dataexport.ErrNotReady
+	}
+
+	file, err := os.Open(job.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return dataexport.ErrNotReady
+		}
+
+		return fmt.Errorf("DataExport.Service.Download opening archive: %w", err)
+	}
+
+	defer file.Close()
+
+	_, err = io.Copy(w, file)
+	if err != nil {
+		return fmt.Errorf("DataExport.Service.Download sending archive: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessNext claims one job and builds its archive. A worker that dies mid-run leaves the job running
+// until it goes stale and another replica claims it again; each claim writes its own archive, so a slow
+// worker that lost its claim only removes the file it wrote.
+func (d *dataExportService) ProcessNext(ctx context.Context) (bool, error) {
+	job, ok, err := d.exports.ClaimJob(ctx)
+	if err != nil {
+		return false, fmt.Errorf("DataExport.Service.ProcessNext claiming job: %w", err)
+	}
+
+	if !ok {
+		return false, nil
+	}
+
+	path, size, err := d.writeArchive(ctx, job)
+	if err != nil {
+		jobsTotal.WithLabelValues("failed").Inc()
+
+		_, failErr := d.exports.FailJob(ctx, job, failureReason)
+		if failErr != nil {
+			err = errors.Join(err, failErr)
+		}
+
+		return true, fmt.Errorf("DataExport.Service.ProcessNext building job %d: %w", job.ID, err)
+	}
+
+	// The archive is left in place when completing fails, as the update may have been committed anyway.
+	completed, err := d.exports.CompleteJob(ctx, job, path, size)
+	if err != nil {
+		return true, fmt.Errorf("DataExport.Service.ProcessNext completing job %d: %w", job.ID, err)
+	}
+
+	if !completed {
+		_ = os.Remove(path)
+
+		return true, fmt.Errorf("DataExport.Service.ProcessNext completing job %d: claimed again by another worker", job.ID)
+	}
+
+	jobsTotal.WithLabelValues("ready").Inc()
+
+	return true, nil
+}
+
+// PurgeExpired deletes archives past their expiry and only then marks their jobs expired, so an archive
+// that could not be removed stays selected and is retried on the next pass. Downloads already refuse
+// archives past their expiry, so they never race a delete.
+func (d *dataExportService) PurgeExpired(ctx context.Context) (int, error) {
+	jobs, err := d.exports.GetExpired(ctx, purgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("DataExport.Service.PurgeExpired getting jobs: %w", err)
+	}
+
+	removed := make([]int64, 0, len(jobs))
+
+	var removeErr error
+
+	for _, job := range jobs {
+		err = os.Remove(job.Path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			removeErr = errors.Join(removeErr, fmt.Errorf("removing job %d archive: %w", job.ID, err))
+			continue
+		}
+
+		removed = append(removed, job.ID)
+	}
+
+	if len(removed) > 0 {
+		err = d.exports.ExpireJobs(ctx, removed)
+		if err != nil {
+			return 0, fmt.Errorf("DataExport.Service.PurgeExpired expiring jobs: %w", err)
+		}
+	}
+
+	if removeErr != nil {
+		return len(removed), fmt.Errorf("DataExport.Service.PurgeExpired: %w", removeErr)
+	}
+
+	return len(removed), nil
+}
+
+// writeArchive builds the ZIP in a temporary file and renames it into place once it is synced.
+func (d *dataExportService) writeArchive(ctx context.Context, job dataexport.Job) (path string, size int64, err error) {
+	err = os.MkdirAll(d.dir, 0o700)
+	if err != nil {
+		return "", 0, err
+	}
+
+	path = filepath.Join(d.dir, fmt.Sprintf("user-%d-export-%d-%d.zip", job.UserID, job.ID, job.StartedAt.UnixMicro()))
+
+	tmp, err := os.CreateTemp(d.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	err = d.writeEntries(ctx, newArchive(tmp, d.now()), job)
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		return "", 0, err
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return "", 0, err
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return path, info.Size(), nil
+}
+
+func (d *dataExportService) writeEntries(ctx context.Context, a *archive, job dataexport.Job) error {
+	profile, err := d.exports.GetProfile(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("getting profile: %w", err)
+	}
+
+	err = a.addJSON("profile.json", profile)
+	if err != nil {
+		return err
+	}
+
+	memberships, err := d.exports.GetMemberships(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("getting memberships: %w", err)
+	}
+
+	err = a.addJSON("memberships.json", memberships)
+	if err != nil {
+		return err
+	}
+
+	err = d.writeMessages(ctx, a, job.UserID)
+	if err != nil {
+		return err
+	}
+
+	votes, err := d.exports.GetPollVotes(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("getting poll votes: %w", err)
+	}
+
+	err = a.addJSON("poll_votes.json", votes)
+	if err != nil {
+		return err
+	}
+
+	reports, err := d.exports.GetReports(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("getting reports: %w", err)
+	}
+
+	err = a.addJSON("reports.json", reports)
+	if err != nil {
+		return err
+	}
+
+	scheduled, err := d.exports.GetScheduledMessages(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("getting scheduled messages: %w", err)
+	}
+
+	err = a.addJSON("scheduled_messages.json", scheduled)
+	if err != nil {
+		return err
+	}
+
+	bans, err := d.exports.GetBans(ctx, job.UserID)
+	if err != nil {
+		return fmt.Errorf("getting bans: %w", err)
+	}
+
+	err = a.addJSON("bans.json", bans)
+	if err != nil {
+		return err
+	}
+
+	security, err := d.exports.GetSecurity(ctx, job.UserID, lockout.LoginKey(domain.Login(profile.Login)).String())
+	if err != nil {
+		return fmt.Errorf("getting security state: %w", err)
+	}
+
+	err = a.addJSON("security.json", security)
+	if err != nil {
+		return err
+	}
+
+	return a.close()
+}
+
+// writeMessages pages through the user's messages so large histories are never held in memory.
+func (d *dataExportService) writeMessages(ctx context.Context, a *archive, userID int64) error {
+	enc, err := a.addLines("messages.jsonl")
+	if err != nil {
+		return err
+	}
+
+	var afterID int64
+
+	for {
+		messages, err := d.exports.GetMessagesAfter(ctx, userID, afterID, messagePageSize)
+		if err != nil {
+			return fmt.Errorf("getting messages: %w", err)
+		}
+
+		for _, msg := range messages {
+			err = enc.Encode(msg)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(messages) < messagePageSize {
+			return nil
+		}
+
+		afterID = messages[len(messages)-1].ID
+	}
+}
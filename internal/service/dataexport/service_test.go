@@ -0,0 +1,162 @@
+package dataexport
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/dataexport"
+	dataExportRepo "github.com/monobearotaku/online-chat-api/internal/repository/dataexport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExports serves one claimed job with an empty history and records how it was finished.
+type fakeExports struct {
+	dataExportRepo.Repo
+
+	job dataexport.Job
+	// reclaimed makes CompleteJob and FailJob report that another worker holds the job.
+	reclaimed bool
+
+	completedPath string
+
+	expired []dataexport.Job
+	// expiredIDs are the jobs marked expired.
+	expiredIDs []int64
+}
+
+func (f *fakeExports) ClaimJob(ctx context.Context) (dataexport.Job, bool, error) {
+	return f.job, true, nil
+}
+
+func (f *fakeExports) CompleteJob(ctx context.Context, job dataexport.Job, path string, size int64) (bool, error) {
+	if f.reclaimed || !job.StartedAt.Equal(f.job.StartedAt) {
+		return false, nil
+	}
+
+	f.completedPath = path
+
+	return true, nil
+}
+
+func (f *fakeExports) GetExpired(ctx context.Context, limit int) ([]dataexport.Job, error) {
+	return f.expired, nil
+}
+
+func (f *fakeExports) ExpireJobs(ctx context.Context, jobIDs []int64) error {
+	f.expiredIDs = append(f.expiredIDs, jobIDs...)
+
+	return nil
+}
+
+func (f *fakeExports) GetProfile(ctx context.Context, userID int64) (dataexport.Profile, error) {
+	return dataexport.Profile{UserID: userID, Login: "alice"}, nil
+}
+
+func (f *fakeExports) GetMemberships(ctx context.Context, userID int64) ([]dataexport.Membership, error) {
+	return nil, nil
+}
+
+func (f *fakeExports) GetMessagesAfter(ctx context.Context, userID, afterID int64, limit int) ([]dataexport.Message, error) {
+	return nil, nil
+}
+
+func (f *fakeExports) GetPollVotes(ctx context.Context, userID int64) ([]dataexport.PollVote, error) {
+	return nil, nil
+}
+
+func (f *fakeExports) GetReports(ctx context.Context, userID int64) ([]dataexport.Report, error) {
+	return nil, nil
+}
+
+func (f *fakeExports) GetScheduledMessages(ctx context.Context, userID int64) ([]dataexport.ScheduledMessage, error) {
+	return nil, nil
+}
+
+func (f *fakeExports) GetBans(ctx context.Context, userID int64) ([]dataexport.Ban, error) {
+	return nil, nil
+}
+
+func (f *fakeExports) GetSecurity(ctx context.Context, userID int64, lockoutKey string) (dataexport.Security, error) {
+	return dataexport.Security{}, nil
+}
+
+func Test_dataExportService_ProcessNext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		reclaimed bool
+		wantErr   bool
+		wantFiles int
+	}{
+		{name: "completes its claim", wantFiles: 1},
+		{name: "claim lost to another worker", reclaimed: true, wantErr: true, wantFiles: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			// Another worker's archive for an earlier claim of the same job must survive.
+			other := dir + "/user-3-export-9-1712962800000000.zip"
+			require.NoError(t, os.WriteFile(other, []byte("archive"), 0o600))
+
+			exports := &fakeExports{
+				job:       dataexport.Job{ID: 9, UserID: 3, Status: dataexport.StatusRunning, StartedAt: time.Unix(1712966400, 0)},
+				reclaimed: tt.reclaimed,
+			}
+
+			processed, err := NewDataExportService(exports, dir).ProcessNext(context.Background())
+			assert.True(t, processed)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.FileExists(t, exports.completedPath)
+			}
+
+			assert.FileExists(t, other)
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, tt.wantFiles+1)
+		})
+	}
+}
+
+func Test_dataExportService_PurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	archive := dir + "/user-3-export-1-1712966400000000.zip"
+	require.NoError(t, os.WriteFile(archive, []byte("archive"), 0o600))
+
+	// A non-empty directory cannot be removed, standing in for an archive the service fails to delete.
+	stuck := dir + "/user-3-export-2-1712966400000000.zip"
+	require.NoError(t, os.MkdirAll(stuck+"/entry", 0o700))
+
+	exports := &fakeExports{
+		expired: []dataexport.Job{
+			{ID: 1, UserID: 3, Status: dataexport.StatusReady, Path: archive},
+			{ID: 2, UserID: 3, Status: dataexport.StatusReady, Path: stuck},
+			{ID: 3, UserID: 3, Status: dataexport.StatusReady, Path: dir + "/already-removed.zip"},
+		},
+	}
+
+	purged, err := NewDataExportService(exports, dir).PurgeExpired(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 2, purged)
+
+	assert.NoFileExists(t, archive)
+	assert.DirExists(t, stuck)
+	assert.Equal(t, []int64{1, 3}, exports.expiredIDs, "the job whose archive is left stays ready for the next pass")
+}
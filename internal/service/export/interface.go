@@ -0,0 +1,13 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/export"
+)
+
+type Service interface {
+	// Export writes the chat, its members and its full history to w, oldest message first.
+	Export(ctx context.Context, userID, chatID int64, format export.Format, w io.Writer) error
+}
@@ -0,0 +1,257 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/export"
+)
+
+const timeLayout = "2006-01-02 15:04:05"
+
+type header struct {
+	Chat       chat.Chat
+	Members    []chat.MemberInfo
+	ExportedAt time.Time
+}
+
+// renderer writes one export incrementally: begin once, message for every message in order, then end.
+type renderer interface {
+	begin(h header) error
+	message(msg chat.Message) error
+	end() error
+}
+
+func newRenderer(format export.Format, w io.Writer) renderer {
+	switch format {
+	case export.FormatHTML:
+		return &htmlRenderer{w: w}
+	case export.FormatText:
+		return &textRenderer{w: w}
+	default:
+		return &jsonRenderer{w: w}
+	}
+}
+
+type jsonChat struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Topic string `json:"topic,omitempty"`
+}
+
+type jsonMember struct {
+	UserID   int64     `json:"userId"`
+	Login    string    `json:"login"`
+	Bot      bool      `json:"bot"`
+	Role     chat.Role `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+type jsonMessage struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"userId"`
+	Login     string     `json:"login"`
+	Bot       bool       `json:"bot"`
+	Kind      string     `json:"kind"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// jsonRenderer writes a single JSON document whose messages array is streamed one element per line.
+type jsonRenderer struct {
+	w     io.Writer
+	wrote bool
+}
+
+func (j *jsonRenderer) begin(h header) error {
+	members := make([]jsonMember, 0, len(h.Members))
+	for _, member := range h.Members {
+		members = append(members, jsonMember(member))
+	}
+
+	head, err := json.Marshal(struct {
+		ExportedAt time.Time    `json:"exportedAt"`
+		Chat       jsonChat     `json:"chat"`
+		Members    []jsonMember `json:"members"`
+	}{
+		ExportedAt: h.ExportedAt,
+		Chat: jsonChat{
+			ID:    h.Chat.ID,
+			Name:  h.Chat.Name,
+			Topic: h.Chat.Topic,
+		},
+		Members: members,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Reopen the object to append the messages array.
+	_, err = fmt.Fprintf(j.w, "%s,\"messages\":[", head[:len(head)-1])
+
+	return err
+}
+
+func (j *jsonRenderer) message(msg chat.Message) error {
+	item := jsonMessage{
+		ID:        msg.ID,
+		UserID:    msg.UserID,
+		Login:     msg.Login,
+		Bot:       msg.Bot,
+		Kind:      string(kindOf(msg)),
+		Text:      msg.Msg,
+		CreatedAt: msg.CreatedAt,
+	}
+
+	if !msg.ExpiresAt.IsZero() {
+		item.ExpiresAt = &msg.ExpiresAt
+	}
+
+	line, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if !j.wrote {
+		separator = "\n"
+		j.wrote = true
+	}
+
+	_, err = fmt.Fprintf(j.w, "%s%s", separator, line)
+
+	return err
+}
+
+func (j *jsonRenderer) end() error {
+	_, err := io.WriteString(j.w, "\n]}\n")
+
+	return err
+}
+
+type textRenderer struct {
+	w io.Writer
+}
+
+func (t *textRenderer) begin(h header) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Chat: %s (#%d)\n", h.Chat.Name, h.Chat.ID)
+
+	if h.Chat.Topic != "" {
+		fmt.Fprintf(&b, "Topic: %s\n", h.Chat.Topic)
+	}
+
+	fmt.Fprintf(&b, "Exported: %s UTC\n\nMembers (%d):\n", h.ExportedAt.Format(timeLayout), len(h.Members))
+
+	for _, member := range h.Members {
+		fmt.Fprintf(&b, "  %s (%s, joined %s)\n", displayName(member.Login, member.Bot), member.Role, member.JoinedAt.UTC().Format(timeLayout))
+	}
+
+	b.WriteString("\nMessages:\n")
+
+	_, err := io.WriteString(t.w, b.String())
+
+	return err
+}
+
+func (t *textRenderer) message(msg chat.Message) error {
+	text := strings.ReplaceAll(msg.Msg, "\n", "\n    ")
+	name := displayName(msg.Login, msg.Bot)
+	at := msg.CreatedAt.UTC().Format(timeLayout)
+
+	var err error
+
+	switch kindOf(msg) {
+	case chat.KindAction:
+		_, err = fmt.Fprintf(t.w, "[%s] * %s %s\n", at, name, text)
+	case chat.KindPoll:
+		_, err = fmt.Fprintf(t.w, "[%s] %s started a poll: %s\n", at, name, text)
+	default:
+		_, err = fmt.Fprintf(t.w, "[%s] %s: %s\n", at, name, text)
+	}
+
+	return err
+}
+
+func (t *textRenderer) end() error {
+	return nil
+}
+
+var (
+	htmlHead = template.Must(template.New("head").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Chat.Name}}</title>
+<style>
+body{font-family:system-ui,sans-serif;margin:2rem auto;max-width:50rem;color:#1f2328;}
+header{border-bottom:1px solid #d0d7de;margin-bottom:1rem;}
+.members{color:#59636e;font-size:.9rem;}
+.message{padding:.25rem 0;}
+.time{color:#59636e;font-size:.8rem;margin-right:.5rem;}
+.login{font-weight:600;}
+.bot{background:#ddf4ff;border-radius:.25rem;font-size:.7rem;margin-left:.25rem;padding:0 .25rem;}
+.text{white-space:pre-wrap;}
+.action .text,.poll .text{font-style:italic;}
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Chat.Name}}</h1>
+{{if .Chat.Topic}}<p>{{.Chat.Topic}}</p>{{end}}
+<p class="members">Exported {{.ExportedAt.Format "2006-01-02 15:04:05"}} UTC &middot; {{len .Members}} members:
+{{range $i, $m := .Members}}{{if $i}}, {{end}}{{$m.Login}}{{if eq $m.Role "owner"}} (owner){{end}}{{end}}</p>
+</header>
+<main>
+`))
+
+	htmlMessage = template.Must(template.New("message").Parse(`<div class="message {{.Kind}}" id="m{{.ID}}">` +
+		`<span class="time">{{.CreatedAt.UTC.Format "2006-01-02 15:04:05"}}</span>` +
+		`{{if eq .Kind "action"}}* {{end}}<span class="login">{{.Login}}</span>{{if .Bot}}<span class="bot">bot</span>{{end}}` +
+		`{{if eq .Kind "poll"}} started a poll: {{else if eq .Kind "action"}} {{else}}: {{end}}` +
+		`<span class="text">{{.Msg}}</span></div>
+`))
+)
+
+type htmlRenderer struct {
+	w io.Writer
+}
+
+func (h *htmlRenderer) begin(head header) error {
+	return htmlHead.Execute(h.w, head)
+}
+
+func (h *htmlRenderer) message(msg chat.Message) error {
+	msg.Kind = kindOf(msg)
+
+	return htmlMessage.Execute(h.w, msg)
+}
+
+func (h *htmlRenderer) end() error {
+	_, err := io.WriteString(h.w, "</main>\n</body>\n</html>\n")
+
+	return err
+}
+
+func kindOf(msg chat.Message) chat.MessageKind {
+	if msg.Kind == "" {
+		return chat.KindText
+	}
+
+	return msg.Kind
+}
+
+func displayName(login string, bot bool) string {
+	if bot {
+		return login + " [bot]"
+	}
+
+	return login
+}
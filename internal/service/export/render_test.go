@@ -0,0 +1,102 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Renderer(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 4, 13, 18, 5, 41, 0, time.UTC)
+
+	head := header{
+		Chat: chat.Chat{ID: 7, Name: "general", Topic: "Everything <else>"},
+		Members: []chat.MemberInfo{
+			{UserID: 1, Login: "alice", Role: chat.Owner, JoinedAt: at},
+			{UserID: 2, Login: "helper", Bot: true, Role: chat.Member, JoinedAt: at},
+		},
+		ExportedAt: at,
+	}
+
+	messages := []chat.Message{
+		{ID: 10, ChatID: 7, UserID: 1, Login: "alice", Msg: "hi <script>alert(1)</script>", Kind: chat.KindText, CreatedAt: at},
+		{ID: 11, ChatID: 7, UserID: 1, Login: "alice", Msg: "waves", Kind: chat.KindAction, CreatedAt: at},
+		{ID: 12, ChatID: 7, UserID: 2, Login: "helper", Bot: true, Msg: "line one\nline two", CreatedAt: at},
+	}
+
+	tests := []struct {
+		name   string
+		format export.Format
+		check  func(t *testing.T, out string)
+	}{
+		{
+			name:   "json",
+			format: export.FormatJSON,
+			check: func(t *testing.T, out string) {
+				doc := struct {
+					Chat     jsonChat      `json:"chat"`
+					Members  []jsonMember  `json:"members"`
+					Messages []jsonMessage `json:"messages"`
+				}{}
+
+				require.NoError(t, json.Unmarshal([]byte(out), &doc))
+				assert.Equal(t, "general", doc.Chat.Name)
+				assert.Len(t, doc.Members, 2)
+				require.Len(t, doc.Messages, 3)
+				assert.Equal(t, "action", doc.Messages[1].Kind)
+				assert.Equal(t, "text", doc.Messages[2].Kind)
+			},
+		},
+		{
+			name:   "text",
+			format: export.FormatText,
+			check: func(t *testing.T, out string) {
+				assert.Contains(t, out, "Chat: general (#7)\nTopic: Everything <else>\n")
+				assert.Contains(t, out, "  helper [bot] (member, joined 2024-04-13 18:05:41)\n")
+				assert.Contains(t, out, "[2024-04-13 18:05:41] * alice waves\n")
+				assert.Contains(t, out, "[2024-04-13 18:05:41] helper [bot]: line one\n    line two\n")
+			},
+		},
+		{
+			name:   "html",
+			format: export.FormatHTML,
+			check: func(t *testing.T, out string) {
+				assert.Contains(t, out, "<title>general</title>")
+				assert.Contains(t, out, "Everything &lt;else&gt;")
+				assert.Contains(t, out, "hi &lt;script&gt;alert(1)&lt;/script&gt;")
+				assert.NotContains(t, out, "<script>")
+				assert.Contains(t, out, `<div class="message action" id="m11">`)
+				assert.Contains(t, out, "</html>\n")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			r := newRenderer(tt.format, &buf)
+			require.NoError(t, r.begin(head))
+
+			for _, msg := range messages {
+				require.NoError(t, r.message(msg))
+			}
+
+			require.NoError(t, r.end())
+
+			tt.check(t, buf.String())
+		})
+	}
+}
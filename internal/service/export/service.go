@@ -0,0 +1,99 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/export"
+	chatRepo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+)
+
+const pageSize = 500
+
+type exportService struct {
+	chat chatRepo.Repo
+	now  func() time.Time
+}
+
+func NewExportService(chat chatRepo.Repo) Service {
+	return &exportService{
+		chat: chat,
+		now:  time.Now,
+	}
+}
+
+// Export streams the history in pages so memory stays flat for large chats; messages posted while
+// the export runs are included up to the last page read.
+func (e *exportService) Export(ctx context.Context, userID, chatID int64, format export.Format, w io.Writer) error {
+	chtUsers, err := e.chat.GetChatUsers(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("Export.Service.Export getting chat users: %w", err)
+	}
+
+	member, ok := chtUsers.Get(userID)
+	if !ok {
+		return chat.ErrChatHaveNoUser
+	}
+
+	if !member.CanExportChat() {
+		return chat.ErrExportDenied
+	}
+
+	cht, err := e.chat.GetById(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, chat.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Export.Service.Export getting chat: %w", err)
+	}
+
+	members, err := e.chat.GetMembers(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("Export.Service.Export getting members: %w", err)
+	}
+
+	r := newRenderer(format, w)
+
+	err = r.begin(header{
+		Chat:       cht,
+		Members:    members,
+		ExportedAt: e.now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("Export.Service.Export writing header: %w", err)
+	}
+
+	var afterID int64
+
+	for {
+		messages, err := e.chat.GetMessagesAfter(ctx, chatID, afterID, pageSize)
+		if err != nil {
+			return fmt.Errorf("Export.Service.Export getting messages: %w", err)
+		}
+
+		for _, msg := range messages {
+			err = r.message(msg)
+			if err != nil {
+				return fmt.Errorf("Export.Service.Export writing message %d: %w", msg.ID, err)
+			}
+		}
+
+		if len(messages) < pageSize {
+			break
+		}
+
+		afterID = messages[len(messages)-1].ID
+	}
+
+	err = r.end()
+	if err != nil {
+		return fmt.Errorf("Export.Service.Export writing footer: %w", err)
+	}
+
+	return nil
+}
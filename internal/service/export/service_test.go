@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/export"
+	chatRepo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChat serves one chat whose owner is user 1, with user 2 allowed to export and user 3 not.
+type fakeChat struct {
+	chatRepo.Repo
+}
+
+func (f fakeChat) GetChatUsers(ctx context.Context, chatID int64) (chat.ChatUsers, error) {
+	return chat.ChatUsers{ID: chatID, Users: []chat.ChatUser{
+		{UserID: 1, Role: chat.Owner},
+		{UserID: 2, Role: chat.Member, CanExport: true},
+		{UserID: 3, Role: chat.Member},
+	}}, nil
+}
+
+func (f fakeChat) GetById(ctx context.Context, chatID int64) (chat.Chat, error) {
+	return chat.Chat{ID: chatID, Name: "general"}, nil
+}
+
+func (f fakeChat) GetMembers(ctx context.Context, chatID int64) ([]chat.MemberInfo, error) {
+	return nil, nil
+}
+
+func (f fakeChat) GetMessagesAfter(ctx context.Context, chatID int64, afterID int64, limit int) ([]chat.Message, error) {
+	return []chat.Message{
+		{ID: 10, ChatID: chatID, UserID: 1, Login: "alice", Msg: "hello", Kind: chat.KindText, CreatedAt: time.Unix(1712966400, 0)},
+	}, nil
+}
+
+func Test_exportService_Export(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		userID  int64
+		wantErr error
+	}{
+		{name: "owner", userID: 1},
+		{name: "member with export permission", userID: 2},
+		{name: "member without export permission", userID: 3, wantErr: chat.ErrExportDenied},
+		{name: "not a member", userID: 4, wantErr: chat.ErrChatHaveNoUser},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+
+			err := NewExportService(fakeChat{}).Export(context.Background(), tt.userID, 7, export.FormatText, &out)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Zero(t, out.Len(), "nothing is written before the permission check")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "hello")
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+)
+
+// nonWord matches one rune that cannot be part of a word in any script; regexp's \b only knows ASCII words.
+const nonWord = `[^\p{L}\p{N}_]`
+
+type blocklistFilter struct {
+	// words captures a blocked word together with the rune on either side of it.
+	words    *regexp.Regexp
+	patterns *regexp.Regexp
+	action   filter.Action
+}
+
+// NewBlocklistFilter matches whole words case-insensitively; entries written as /pattern/ are regular expressions.
+func NewBlocklistFilter(entries []string, action filter.Action) (filter.Filter, error) {
+	words := make([]string, 0, len(entries))
+	patterns := make([]string, 0)
+
+	for _, entry := range entries {
+		if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+			expr := entry[1 : len(entry)-1]
+
+			_, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid blocklist pattern %q: %w", entry, err)
+			}
+
+			patterns = append(patterns, "(?:"+expr+")")
+
+			continue
+		}
+
+		if entry != "" {
+			words = append(words, regexp.QuoteMeta(entry))
+		}
+	}
+
+	b := &blocklistFilter{action: action}
+
+	if len(words) > 0 {
+		b.words = regexp.MustCompile("(?i)(?:^|" + nonWord + ")(" + strings.Join(words, "|") + ")(?:$|" + nonWord + ")")
+	}
+
+	if len(patterns) > 0 {
+		b.patterns = regexp.MustCompile("(?i)" + strings.Join(patterns, "|"))
+	}
+
+	return b, nil
+}
+
+func (b *blocklistFilter) Name() string {
+	return "blocklist"
+}
+
+func (b *blocklistFilter) Check(ctx context.Context, msg filter.Message) (filter.Verdict, error) {
+	wordsMatch := b.words != nil && b.words.MatchString(msg.Text)
+	patternsMatch := b.patterns != nil && b.patterns.MatchString(msg.Text)
+
+	if !wordsMatch && !patternsMatch {
+		return filter.Allow(), nil
+	}
+
+	if b.action == filter.ActionReject {
+		return filter.Reject("blocked word"), nil
+	}
+
+	masked := msg.Text
+	if patternsMatch {
+		masked = b.patterns.ReplaceAllStringFunc(masked, stars)
+	}
+
+	if wordsMatch {
+		masked = b.maskWords(masked)
+	}
+
+	return filter.Mask(masked, "blocked word"), nil
+}
+
+// maskWords masks every blocked word but not the runes around it. A match consumes the rune after its word,
+// which may be the rune before the next word, so the search resumes right after each word instead.
+func (b *blocklistFilter) maskWords(text string) string {
+	var masked strings.Builder
+
+	last, pos := 0, 0
+
+	for pos < len(text) {
+		loc := b.words.FindStringSubmatchIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+
+		start, end := pos+loc[2], pos+loc[3]
+
+		// ^ matches where the search resumed, which is only a word start if the rune before it is not a letter.
+		if loc[2] == 0 && pos > 0 {
+			if r, _ := utf8.DecodeLastRuneInString(text[:pos]); isWordRune(r) {
+				_, size := utf8.DecodeRuneInString(text[pos:])
+				pos += size
+
+				continue
+			}
+		}
+
+		masked.WriteString(text[last:start])
+		masked.WriteString(stars(text[start:end]))
+
+		last, pos = end, end
+	}
+
+	masked.WriteString(text[last:])
+
+	return masked.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_'
+}
+
+func stars(match string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(match))
+}
@@ -0,0 +1,13 @@
+package filter
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+)
+
+type Service interface {
+	// Check runs the message through every filter and returns the text to store.
+	Check(ctx context.Context, msg filter.Message) (string, error)
+	Use(f filter.Filter)
+}
@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+)
+
+const maskedLink = "[link removed]"
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]+`)
+
+type linkFilter struct {
+	allow  []string
+	deny   []string
+	action filter.Action
+}
+
+// NewLinkFilter blocks links to denied hosts and, when allow is not empty, to every host not on it.
+// Hosts match themselves and their subdomains.
+func NewLinkFilter(allow, deny []string, action filter.Action) filter.Filter {
+	return &linkFilter{
+		allow:  normalizeHosts(allow),
+		deny:   normalizeHosts(deny),
+		action: action,
+	}
+}
+
+func (l *linkFilter) Name() string {
+	return "links"
+}
+
+func (l *linkFilter) Check(ctx context.Context, msg filter.Message) (filter.Verdict, error) {
+	if len(l.allow) == 0 && len(l.deny) == 0 {
+		return filter.Allow(), nil
+	}
+
+	blocked := false
+
+	masked := linkPattern.ReplaceAllStringFunc(msg.Text, func(link string) string {
+		if l.permitted(linkHost(link)) {
+			return link
+		}
+
+		blocked = true
+
+		return maskedLink
+	})
+
+	if !blocked {
+		return filter.Allow(), nil
+	}
+
+	if l.action == filter.ActionReject {
+		return filter.Reject("blocked link"), nil
+	}
+
+	return filter.Mask(masked, "blocked link"), nil
+}
+
+func (l *linkFilter) permitted(host string) bool {
+	if matchHost(host, l.deny) {
+		return false
+	}
+
+	return len(l.allow) == 0 || matchHost(host, l.allow)
+}
+
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(parsed.Hostname())
+}
+
+func matchHost(host string, hosts []string) bool {
+	for _, item := range hosts {
+		if host == item || strings.HasSuffix(host, "."+item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeHosts(hosts []string) []string {
+	res := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		res = append(res, strings.TrimPrefix(strings.ToLower(host), "www."))
+	}
+
+	return res
+}
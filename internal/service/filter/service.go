@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var filteredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "chat_filtered_messages_total",
+	Help: "Number of messages masked or rejected by moderation filters, by filter and action.",
+}, []string{"filter", "action"})
+
+type filterService struct {
+	filters []filter.Filter
+	mu      *sync.RWMutex
+	logger  log.Logger
+}
+
+func NewFilterService(logger log.Logger, filters ...filter.Filter) Service {
+	return &filterService{
+		filters: filters,
+		mu:      &sync.RWMutex{},
+		logger:  logger,
+	}
+}
+
+// Use appends a filter to the end of the chain.
+func (f *filterService) Use(flt filter.Filter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.filters = append(f.filters, flt)
+}
+
+// Check fails open: a filter that errors is logged and skipped so a broken classifier cannot stop the chat.
+func (f *filterService) Check(ctx context.Context, msg filter.Message) (string, error) {
+	f.mu.RLock()
+	filters := f.filters
+	f.mu.RUnlock()
+
+	for _, flt := range filters {
+		verdict, err := flt.Check(ctx, msg)
+		if err != nil {
+			level.Error(f.logger).Log("error", fmt.Errorf("Filter.Service.Check running %s: %w", flt.Name(), err))
+			continue
+		}
+
+		switch verdict.Action {
+		case filter.ActionMask:
+			filteredTotal.WithLabelValues(flt.Name(), string(filter.ActionMask)).Inc()
+			msg.Text = verdict.Text
+		case filter.ActionReject:
+			filteredTotal.WithLabelValues(flt.Name(), string(filter.ActionReject)).Inc()
+			level.Info(f.logger).Log(
+				"msg", "message rejected",
+				"filter", flt.Name(),
+				"reason", verdict.Reason,
+				"chat_id", msg.ChatID,
+				"user_id", msg.UserID,
+			)
+
+			return "", filter.ErrRejected
+		}
+	}
+
+	return msg.Text, nil
+}
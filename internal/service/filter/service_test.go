@@ -0,0 +1,153 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingFilter struct{}
+
+func (failingFilter) Name() string {
+	return "failing"
+}
+
+func (failingFilter) Check(ctx context.Context, msg filter.Message) (filter.Verdict, error) {
+	return filter.Verdict{}, errors.New("classifier unavailable")
+}
+
+func Test_filterService_Check(t *testing.T) {
+	t.Parallel()
+
+	maskWords, err := NewBlocklistFilter([]string{"darn", "чёрт", "/h[e3]ck/"}, filter.ActionMask)
+	require.NoError(t, err)
+
+	rejectWords, err := NewBlocklistFilter([]string{"darn"}, filter.ActionReject)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		filters []filter.Filter
+		text    string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "no filters",
+			filters: nil,
+			text:    "hello",
+			want:    "hello",
+		},
+		{
+			name:    "blocklist masks whole words and patterns",
+			filters: []filter.Filter{maskWords},
+			text:    "Darn it, what the h3ck, darned",
+			want:    "**** it, what the ****, darned",
+		},
+		{
+			name:    "blocklist masks repeated words",
+			filters: []filter.Filter{maskWords},
+			text:    "darn darn,darn",
+			want:    "**** ****,****",
+		},
+		{
+			name:    "blocklist word boundaries are not ASCII only",
+			filters: []filter.Filter{maskWords},
+			text:    "Чёрт! чёртов darné ädarn darn_it «darn»",
+			want:    "****! чёртов darné ädarn darn_it «****»",
+		},
+		{
+			name:    "blocklist rejects",
+			filters: []filter.Filter{rejectWords},
+			text:    "oh darn",
+			wantErr: filter.ErrRejected,
+		},
+		{
+			name:    "denied link is masked",
+			filters: []filter.Filter{NewLinkFilter(nil, []string{"spam.example"}, filter.ActionMask)},
+			text:    "see https://cdn.spam.example/x and https://ok.example",
+			want:    "see [link removed] and https://ok.example",
+		},
+		{
+			name:    "link outside the allow list is rejected",
+			filters: []filter.Filter{NewLinkFilter([]string{"docs.example"}, nil, filter.ActionReject)},
+			text:    "go to www.other.example/page",
+			wantErr: filter.ErrRejected,
+		},
+		{
+			name:    "allowed link passes",
+			filters: []filter.Filter{NewLinkFilter([]string{"docs.example"}, nil, filter.ActionReject)},
+			text:    "go to https://docs.example/page",
+			want:    "go to https://docs.example/page",
+		},
+		{
+			name:    "too long",
+			filters: []filter.Filter{NewLengthFilter(5)},
+			text:    "toolong",
+			wantErr: filter.ErrRejected,
+		},
+		{
+			name:    "character runs are collapsed",
+			filters: []filter.Filter{NewRepetitionFilter(3)},
+			text:    "nooooooooooooooo",
+			want:    "noooooooooo",
+		},
+		{
+			name:    "masks carry over to later filters",
+			filters: []filter.Filter{maskWords, rejectWords},
+			text:    "darn",
+			want:    "****",
+		},
+		{
+			name:    "failing filter is skipped",
+			filters: []filter.Filter{failingFilter{}, maskWords},
+			text:    "darn",
+			want:    "****",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewFilterService(log.NewNopLogger(), tt.filters...)
+
+			got, err := s.Check(context.Background(), filter.Message{ChatID: 1, UserID: 2, Text: tt.text})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_repetitionFilter_repeatedMessages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := NewFilterService(log.NewNopLogger(), NewRepetitionFilter(2))
+	msg := filter.Message{ChatID: 1, UserID: 2, Text: "buy now"}
+
+	for i := 0; i < 2; i++ {
+		_, err := s.Check(ctx, msg)
+		assert.NoError(t, err)
+	}
+
+	_, err := s.Check(ctx, msg)
+	assert.ErrorIs(t, err, filter.ErrRejected)
+
+	_, err = s.Check(ctx, filter.Message{ChatID: 1, UserID: 3, Text: "buy now"})
+	assert.NoError(t, err)
+
+	_, err = s.Check(ctx, filter.Message{ChatID: 1, UserID: 2, Text: "something else"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,136 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/filter"
+)
+
+const (
+	maxCharRun        = 10
+	repetitionWindow  = time.Minute
+	repetitionEntries = 10000
+)
+
+type lengthFilter struct {
+	max int
+}
+
+// NewLengthFilter rejects messages longer than max characters, a stricter limit than the storage one.
+func NewLengthFilter(max int) filter.Filter {
+	return &lengthFilter{
+		max: max,
+	}
+}
+
+func (l *lengthFilter) Name() string {
+	return "length"
+}
+
+func (l *lengthFilter) Check(ctx context.Context, msg filter.Message) (filter.Verdict, error) {
+	if l.max > 0 && utf8.RuneCountInString(msg.Text) > l.max {
+		return filter.Reject(fmt.Sprintf("longer than %d characters", l.max)), nil
+	}
+
+	return filter.Allow(), nil
+}
+
+type lastMessage struct {
+	text    string
+	repeats int
+	at      time.Time
+}
+
+// repetitionFilter collapses long runs of one character and rejects a member sending the same text
+// more than maxRepeats times in a row within a minute. History is kept per replica.
+type repetitionFilter struct {
+	maxRepeats int
+	last       map[string]lastMessage
+	mu         *sync.Mutex
+	now        func() time.Time
+}
+
+func NewRepetitionFilter(maxRepeats int) filter.Filter {
+	return &repetitionFilter{
+		maxRepeats: maxRepeats,
+		last:       make(map[string]lastMessage),
+		mu:         &sync.Mutex{},
+		now:        time.Now,
+	}
+}
+
+func (r *repetitionFilter) Name() string {
+	return "repetition"
+}
+
+func (r *repetitionFilter) Check(ctx context.Context, msg filter.Message) (filter.Verdict, error) {
+	if r.maxRepeats > 0 && r.repeated(msg) {
+		return filter.Reject("repeated message"), nil
+	}
+
+	collapsed := collapseRuns(msg.Text, maxCharRun)
+	if collapsed != msg.Text {
+		return filter.Mask(collapsed, "repeated characters"), nil
+	}
+
+	return filter.Allow(), nil
+}
+
+func (r *repetitionFilter) repeated(msg filter.Message) bool {
+	key := fmt.Sprintf("%d:%d", msg.ChatID, msg.UserID)
+	text := strings.ToLower(strings.TrimSpace(msg.Text))
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.last) >= repetitionEntries {
+		for k, item := range r.last {
+			if now.Sub(item.at) > repetitionWindow {
+				delete(r.last, k)
+			}
+		}
+	}
+
+	last, ok := r.last[key]
+	if ok && last.text == text && now.Sub(last.at) <= repetitionWindow {
+		last.repeats++
+	} else {
+		last = lastMessage{text: text, repeats: 1}
+	}
+
+	last.at = now
+	r.last[key] = last
+
+	return last.repeats > r.maxRepeats
+}
+
+// collapseRuns shortens any run of the same character to max characters.
+func collapseRuns(text string, max int) string {
+	var (
+		sb   strings.Builder
+		prev rune
+		run  int
+	)
+
+	for i, ch := range text {
+		if i > 0 && ch == prev {
+			run++
+		} else {
+			run = 1
+		}
+
+		prev = ch
+
+		if run <= max {
+			sb.WriteRune(ch)
+		}
+	}
+
+	return sb.String()
+}
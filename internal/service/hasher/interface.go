@@ -0,0 +1,8 @@
+package hasher
+
+import "github.com/monobearotaku/online-chat-api/internal/domain"
+
+type PasswordHasher interface {
+	Hash(password domain.Password) (domain.Password, error)
+	Verify(password, hash domain.Password) (ok bool, rehash bool)
+}
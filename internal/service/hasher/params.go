@@ -0,0 +1,99 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmBcrypt   Algorithm = "bcrypt"
+	AlgorithmArgon2id Algorithm = "argon2id"
+)
+
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+type Params struct {
+	Algorithm  Algorithm
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+var DefaultParams = Params{
+	Algorithm:  AlgorithmArgon2id,
+	BcryptCost: 12,
+	Argon2: Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	},
+}
+
+// ParseParams reads hasher settings from their textual form, keeping defaults for empty values.
+func ParseParams(algorithm, bcryptCost, memory, iterations, parallelism string) (Params, error) {
+	params := DefaultParams
+
+	switch Algorithm(algorithm) {
+	case "":
+	case AlgorithmBcrypt, AlgorithmArgon2id:
+		params.Algorithm = Algorithm(algorithm)
+	default:
+		return DefaultParams, fmt.Errorf("unknown password hash algorithm %q", algorithm)
+	}
+
+	var errs []error
+
+	if bcryptCost != "" {
+		cost, err := strconv.Atoi(bcryptCost)
+		if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			errs = append(errs, fmt.Errorf("invalid bcrypt cost %q", bcryptCost))
+		} else {
+			params.BcryptCost = cost
+		}
+	}
+
+	if memory != "" {
+		value, err := strconv.ParseUint(memory, 10, 32)
+		if err != nil || value == 0 {
+			errs = append(errs, fmt.Errorf("invalid argon2 memory %q", memory))
+		} else {
+			params.Argon2.Memory = uint32(value)
+		}
+	}
+
+	if iterations != "" {
+		value, err := strconv.ParseUint(iterations, 10, 32)
+		if err != nil || value == 0 {
+			errs = append(errs, fmt.Errorf("invalid argon2 iterations %q", iterations))
+		} else {
+			params.Argon2.Iterations = uint32(value)
+		}
+	}
+
+	if parallelism != "" {
+		value, err := strconv.ParseUint(parallelism, 10, 8)
+		if err != nil || value == 0 {
+			errs = append(errs, fmt.Errorf("invalid argon2 parallelism %q", parallelism))
+		} else {
+			params.Argon2.Parallelism = uint8(value)
+		}
+	}
+
+	if len(errs) > 0 {
+		return DefaultParams, errors.Join(errs...)
+	}
+
+	return params, nil
+}
@@ -0,0 +1,58 @@
+package hasher
+
+import (
+	"strings"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type passwordHasher struct {
+	params Params
+}
+
+func NewPasswordHasher(params Params) PasswordHasher {
+	return &passwordHasher{
+		params: params,
+	}
+}
+
+func (h *passwordHasher) Hash(password domain.Password) (domain.Password, error) {
+	switch h.params.Algorithm {
+	case AlgorithmBcrypt:
+		bytes, err := bcrypt.GenerateFromPassword(password.Bytes(), h.params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+
+		return domain.Password(bytes), nil
+	default:
+		hash, err := argon2Hash(password.Bytes(), h.params.Argon2)
+		if err != nil {
+			return "", err
+		}
+
+		return domain.Password(hash), nil
+	}
+}
+
+// Verify reports whether the password matches and whether the stored hash should be replaced
+// because it was produced by another algorithm or with outdated parameters.
+func (h *passwordHasher) Verify(password, hash domain.Password) (bool, bool) {
+	if strings.HasPrefix(hash.String(), argon2Prefix) {
+		ok, params, err := argon2Verify(password.Bytes(), hash.String())
+		if err != nil || !ok {
+			return false, false
+		}
+
+		return true, h.params.Algorithm != AlgorithmArgon2id || params != h.params.Argon2
+	}
+
+	if bcrypt.CompareHashAndPassword(hash.Bytes(), password.Bytes()) != nil {
+		return false, false
+	}
+
+	cost, err := bcrypt.Cost(hash.Bytes())
+
+	return true, err != nil || h.params.Algorithm != AlgorithmBcrypt || cost != h.params.BcryptCost
+}
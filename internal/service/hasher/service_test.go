@@ -0,0 +1,147 @@
+package hasher
+
+import (
+	"testing"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testParams = Params{
+	Algorithm:  AlgorithmArgon2id,
+	BcryptCost: bcrypt.MinCost,
+	Argon2: Argon2Params{
+		Memory:      1024,
+		Iterations:  1,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	},
+}
+
+func Test_PasswordHasher_Verify(t *testing.T) {
+	t.Parallel()
+
+	password := domain.Password("password123")
+
+	bcryptParams := testParams
+	bcryptParams.Algorithm = AlgorithmBcrypt
+
+	strongerArgon2 := testParams
+	strongerArgon2.Argon2.Iterations = 2
+
+	strongerBcrypt := bcryptParams
+	strongerBcrypt.BcryptCost = bcrypt.MinCost + 1
+
+	tests := []struct {
+		name     string
+		stored   Params
+		current  Params
+		password domain.Password
+		ok       bool
+		rehash   bool
+	}{
+		{
+			name:     "Argon2id Up To Date",
+			stored:   testParams,
+			current:  testParams,
+			password: password,
+			ok:       true,
+		},
+		{
+			name:     "Argon2id Wrong Password",
+			stored:   testParams,
+			current:  testParams,
+			password: "wrong-password",
+		},
+		{
+			name:     "Argon2id Outdated Parameters",
+			stored:   testParams,
+			current:  strongerArgon2,
+			password: password,
+			ok:       true,
+			rehash:   true,
+		},
+		{
+			name:     "Bcrypt Up To Date",
+			stored:   bcryptParams,
+			current:  bcryptParams,
+			password: password,
+			ok:       true,
+		},
+		{
+			name:     "Bcrypt Outdated Cost",
+			stored:   bcryptParams,
+			current:  strongerBcrypt,
+			password: password,
+			ok:       true,
+			rehash:   true,
+		},
+		{
+			name:     "Bcrypt Upgraded To Argon2id",
+			stored:   bcryptParams,
+			current:  testParams,
+			password: password,
+			ok:       true,
+			rehash:   true,
+		},
+		{
+			name:     "Bcrypt Wrong Password",
+			stored:   bcryptParams,
+			current:  testParams,
+			password: "wrong-password",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := NewPasswordHasher(tt.stored).Hash(password)
+			require.NoError(t, err)
+
+			ok, rehash := NewPasswordHasher(tt.current).Verify(tt.password, hash)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.rehash, rehash)
+		})
+	}
+}
+
+func Test_PasswordHasher_VerifyMalformed(t *testing.T) {
+	t.Parallel()
+
+	h := NewPasswordHasher(testParams)
+
+	for _, hash := range []domain.Password{"", "$argon2id$v=19$m=1024$abc", "$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5", "plain"} {
+		ok, rehash := h.Verify("password123", hash)
+
+		assert.False(t, ok, hash)
+		assert.False(t, rehash, hash)
+	}
+}
+
+func Test_ParseParams(t *testing.T) {
+	t.Parallel()
+
+	params, err := ParseParams("bcrypt", "11", "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmBcrypt, params.Algorithm)
+	assert.Equal(t, 11, params.BcryptCost)
+	assert.Equal(t, DefaultParams.Argon2, params.Argon2)
+
+	params, err = ParseParams("", "", "32768", "2", "4")
+	require.NoError(t, err)
+	assert.Equal(t, Argon2Params{Memory: 32768, Iterations: 2, Parallelism: 4, SaltLength: 16, KeyLength: 32}, params.Argon2)
+
+	_, err = ParseParams("md5", "", "", "", "")
+	assert.Error(t, err)
+
+	params, err = ParseParams("", "100", "", "", "")
+	assert.Error(t, err)
+	assert.Equal(t, DefaultParams, params)
+}
@@ -0,0 +1,16 @@
+package incoming
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+)
+
+type Service interface {
+	CreateIncoming(ctx context.Context, ownerID, chatID int64, name domain.Login, rateLimit int) (incoming.Incoming, string, error)
+	GetIncoming(ctx context.Context, ownerID, chatID int64) ([]incoming.Incoming, error)
+	RevokeIncoming(ctx context.Context, ownerID, chatID, hookID int64) error
+	Post(ctx context.Context, hookID int64, token, text string) (chat.Message, error)
+}
@@ -0,0 +1,248 @@
+package incoming
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/incoming"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	"github.com/monobearotaku/online-chat-api/internal/repository/bot"
+	"github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	incomingRepo "github.com/monobearotaku/online-chat-api/internal/repository/incoming"
+	chatService "github.com/monobearotaku/online-chat-api/internal/service/chat"
+)
+
+const tokenBytes = 32
+
+type incomingService struct {
+	hooks incomingRepo.Repo
+	bots  bot.Repo
+	chat  chat.Repo
+
+	chatService chatService.Service
+	txBeginner  postgres.TxBeginner
+
+	now func() time.Time
+}
+
+func NewIncomingService(hooks incomingRepo.Repo, bots bot.Repo, chat chat.Repo, chatService chatService.Service, txBeginner postgres.TxBeginner) Service {
+	return &incomingService{
+		hooks:       hooks,
+		bots:        bots,
+		chat:        chat,
+		chatService: chatService,
+		txBeginner:  txBeginner,
+		now:         time.Now,
+	}
+}
+
+// CreateIncoming registers the integration as a bot user owned by the chat owner and adds it to the chat,
+// so its messages go through the same path as any member's.
+func (i *incomingService) CreateIncoming(ctx context.Context, ownerID, chatID int64, name domain.Login, rateLimit int) (hook incoming.Incoming, token string, err error) {
+	err = name.Validate()
+	if err != nil {
+		return incoming.Incoming{}, "", err
+	}
+
+	rateLimit, err = incoming.ParseRateLimit(rateLimit)
+	if err != nil {
+		return incoming.Incoming{}, "", err
+	}
+
+	err = i.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return incoming.Incoming{}, "", err
+	}
+
+	raw := make([]byte, tokenBytes)
+
+	_, err = rand.Read(raw)
+	if err != nil {
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming generating token: %w", err)
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(raw)
+
+	tx, err := i.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	// The chat's row lock makes concurrent requests count webhooks one at a time.
+	err = i.chat.WithTx(tx).LockChat(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return incoming.Incoming{}, "", err
+		}
+
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming locking chat: %w", err)
+	}
+
+	count, err := i.hooks.WithTx(tx).CountActive(ctx, chatID)
+	if err != nil {
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming counting webhooks: %w", err)
+	}
+
+	if count >= incoming.MaxPerChat {
+		return incoming.Incoming{}, "", incoming.ErrTooMany
+	}
+
+	identity, err := i.bots.WithTx(tx).CreateBot(ctx, ownerID, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			return incoming.Incoming{}, "", err
+		}
+
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming creating identity: %w", err)
+	}
+
+	err = i.chat.WithTx(tx).AddUserToChat(ctx, chatID, identity.ID, chatDomain.Member)
+	if err != nil {
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming adding identity to chat: %w", err)
+	}
+
+	hook, err = i.hooks.WithTx(tx).Create(ctx, incoming.Incoming{
+		ChatID:    chatID,
+		UserID:    identity.ID,
+		Name:      name,
+		CreatedBy: ownerID,
+		TokenHash: hashToken(token),
+		RateLimit: rateLimit,
+	})
+	if err != nil {
+		return incoming.Incoming{}, "", fmt.Errorf("Incoming.Service.CreateIncoming saving webhook: %w", err)
+	}
+
+	return hook, token, nil
+}
+
+func (i *incomingService) GetIncoming(ctx context.Context, ownerID, chatID int64) ([]incoming.Incoming, error) {
+	err := i.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := i.hooks.GetByChat(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("Incoming.Service.GetIncoming getting webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+// RevokeIncoming also removes the webhook's bot user from the chat, so it no longer shows up as a member.
+func (i *incomingService) RevokeIncoming(ctx context.Context, ownerID, chatID, hookID int64) (err error) {
+	err = i.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := i.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("Incoming.Service.RevokeIncoming begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	userID, err := i.hooks.WithTx(tx).Revoke(ctx, chatID, hookID)
+	if err != nil {
+		if errors.Is(err, incoming.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Incoming.Service.RevokeIncoming revoking webhook: %w", err)
+	}
+
+	err = i.chat.WithTx(tx).RemoveUserFromChat(ctx, chatID, userID)
+	if err != nil {
+		return fmt.Errorf("Incoming.Service.RevokeIncoming removing identity from chat: %w", err)
+	}
+
+	return nil
+}
+
+func (i *incomingService) Post(ctx context.Context, hookID int64, token, text string) (chatDomain.Message, error) {
+	hook, err := i.hooks.Get(ctx, hookID)
+	if err != nil {
+		if errors.Is(err, incoming.ErrNotFound) {
+			return chatDomain.Message{}, incoming.ErrInvalidToken
+		}
+
+		return chatDomain.Message{}, fmt.Errorf("Incoming.Service.Post getting webhook: %w", err)
+	}
+
+	if hook.Revoked() || subtle.ConstantTimeCompare([]byte(hook.TokenHash), []byte(hashToken(token))) != 1 {
+		return chatDomain.Message{}, incoming.ErrInvalidToken
+	}
+
+	err = chatDomain.Message{Msg: text}.Validate()
+	if err != nil {
+		return chatDomain.Message{}, err
+	}
+
+	now := i.now()
+
+	usage, err := i.hooks.Consume(ctx, hook.ID, now, now.Add(-incoming.RateWindow))
+	if err != nil {
+		return chatDomain.Message{}, fmt.Errorf("Incoming.Service.Post consuming rate limit: %w", err)
+	}
+
+	if usage.Count > hook.RateLimit {
+		return chatDomain.Message{}, incoming.ErrRateLimited.WithRetryAfter(usage.RetryAfter(now))
+	}
+
+	msg, err := i.chatService.PostMessage(ctx, hook.UserID, hook.ChatID, text, 0)
+	if err != nil {
+		return chatDomain.Message{}, fmt.Errorf("Incoming.Service.Post: %w", err)
+	}
+
+	return msg, nil
+}
+
+func (i *incomingService) checkOwner(ctx context.Context, ownerID, chatID int64) error {
+	chtUsers, err := i.chat.GetChatUsers(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Incoming.Service getting chat users: %w", err)
+	}
+
+	if !chtUsers.IsOwner(ownerID) {
+		return chatDomain.ErrUserNotOwner
+	}
+
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,13 @@
+package lockout
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+)
+
+type Service interface {
+	Reserve(ctx context.Context, keys ...lockout.Key) error
+	Release(ctx context.Context, keys ...lockout.Key) error
+	RegisterSuccess(ctx context.Context, key lockout.Key) error
+}
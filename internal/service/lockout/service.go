@@ -0,0 +1,110 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+	lockoutRepo "github.com/monobearotaku/online-chat-api/internal/repository/lockout"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	lockoutsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_sign_in_lockouts_total",
+		Help: "Number of sign in lockouts started, by scope.",
+	}, []string{"scope"})
+
+	rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_sign_in_locked_rejections_total",
+		Help: "Number of sign in attempts rejected because of an active lockout, by scope.",
+	}, []string{"scope"})
+)
+
+type lockoutService struct {
+	lockout  lockoutRepo.Repo
+	policies map[lockout.Scope]lockout.Policy
+	now      func() time.Time
+}
+
+func NewLockoutService(lockout lockoutRepo.Repo, policies map[lockout.Scope]lockout.Policy) Service {
+	return &lockoutService{
+		lockout:  lockout,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// Reserve counts an attempt against every key before the credentials are verified, so parallel
+// attempts cannot all pass a lockout check that only the first failure would have tripped.
+func (l *lockoutService) Reserve(ctx context.Context, keys ...lockout.Key) error {
+	now := l.now()
+
+	reserved := make([]lockout.Key, 0, len(keys))
+
+	for _, key := range keys {
+		policy, ok := l.policies[key.Scope]
+		if !ok {
+			continue
+		}
+
+		state, ok, err := l.lockout.Reserve(ctx, key, policy, now)
+		if err != nil {
+			return fmt.Errorf("Lockout.Service.Reserve reserving attempt: %w", err)
+		}
+
+		if !ok {
+			err = l.Release(ctx, reserved...)
+			if err != nil {
+				return fmt.Errorf("Lockout.Service.Reserve: %w", err)
+			}
+
+			rejectedTotal.WithLabelValues(key.Scope.String()).Inc()
+			return lockout.ErrLocked.WithRetryAfter(state.RetryAfter(now).Round(time.Second))
+		}
+
+		reserved = append(reserved, key)
+
+		delay := policy.Delay(state.Failures)
+		if delay == 0 {
+			continue
+		}
+
+		err = l.lockout.Lock(ctx, key, now.Add(delay))
+		if err != nil {
+			return fmt.Errorf("Lockout.Service.Reserve locking: %w", err)
+		}
+
+		lockoutsTotal.WithLabelValues(key.Scope.String()).Inc()
+	}
+
+	return nil
+}
+
+// Release gives back attempts reserved for credentials that turned out to be right.
+func (l *lockoutService) Release(ctx context.Context, keys ...lockout.Key) error {
+	for _, key := range keys {
+		policy, ok := l.policies[key.Scope]
+		if !ok {
+			continue
+		}
+
+		err := l.lockout.Release(ctx, key, policy)
+		if err != nil {
+			return fmt.Errorf("Lockout.Service.Release releasing attempt: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (l *lockoutService) RegisterSuccess(ctx context.Context, key lockout.Key) error {
+	err := l.lockout.Reset(ctx, key)
+	if err != nil {
+		return fmt.Errorf("Lockout.Service.RegisterSuccess resetting state: %w", err)
+	}
+
+	return nil
+}
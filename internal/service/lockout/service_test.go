@@ -0,0 +1,141 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/lockout"
+	lockoutRepo "github.com/monobearotaku/online-chat-api/internal/repository/lockout"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(now *time.Time) *lockoutService {
+	return &lockoutService{
+		lockout: lockoutRepo.NewMemoryLockoutRepo(),
+		policies: map[lockout.Scope]lockout.Policy{
+			lockout.ScopeLogin: {
+				FreeAttempts: 2,
+				BaseDelay:    time.Second,
+				MaxDelay:     4 * time.Second,
+				Window:       time.Minute,
+			},
+		},
+		now: func() time.Time {
+			return *now
+		},
+	}
+}
+
+func Test_lockoutService_progressiveLockout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	key := lockout.LoginKey("someone")
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, s.Reserve(ctx, key))
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}
+
+	for _, delay := range expected {
+		assert.NoError(t, s.Reserve(ctx, key))
+
+		err := s.Reserve(ctx, key)
+		assert.ErrorIs(t, err, lockout.ErrLocked)
+
+		domainErr, ok := err.(interface{ RetryAfter() time.Duration })
+		if assert.True(t, ok) {
+			assert.Equal(t, delay, domainErr.RetryAfter())
+		}
+
+		now = now.Add(delay)
+	}
+
+	assert.NoError(t, s.RegisterSuccess(ctx, key))
+	assert.NoError(t, s.Reserve(ctx, key))
+	assert.NoError(t, s.Reserve(ctx, key))
+}
+
+func Test_lockoutService_windowExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	key := lockout.LoginKey("someone")
+
+	assert.NoError(t, s.Reserve(ctx, key))
+	assert.NoError(t, s.Reserve(ctx, key))
+
+	now = now.Add(2 * time.Minute)
+
+	assert.NoError(t, s.Reserve(ctx, key))
+	assert.NoError(t, s.Reserve(ctx, key))
+}
+
+func Test_lockoutService_concurrentReservations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	key := lockout.LoginKey("someone")
+
+	var reserved atomic.Int32
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if s.Reserve(ctx, key) == nil {
+				reserved.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(3), reserved.Load(), "the free attempts and the one that locks the key")
+}
+
+func Test_lockoutService_release(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	key := lockout.LoginKey("someone")
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.Reserve(ctx, key))
+	}
+
+	assert.ErrorIs(t, s.Reserve(ctx, key), lockout.ErrLocked)
+
+	// The attempt that locked the key had the right credentials, so it no longer counts.
+	assert.NoError(t, s.Release(ctx, key))
+	assert.NoError(t, s.Reserve(ctx, key))
+	assert.ErrorIs(t, s.Reserve(ctx, key), lockout.ErrLocked)
+}
+
+func Test_lockoutService_unknownScopeIsIgnored(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	key := lockout.AddressKey("127.0.0.1")
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, s.Reserve(ctx, key))
+	}
+}
@@ -0,0 +1,11 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+)
+
+type Service interface {
+	Take(ctx context.Context, keys ...ratelimit.Key) error
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+	rateLimitRepo "github.com/monobearotaku/online-chat-api/internal/repository/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "chat_rate_limited_messages_total",
+	Help: "Number of messages rejected by rate limits, by scope.",
+}, []string{"scope"})
+
+type rateLimitService struct {
+	buckets  rateLimitRepo.Repo
+	policies map[ratelimit.Scope]ratelimit.Bucket
+	now      func() time.Time
+}
+
+func NewRateLimitService(buckets rateLimitRepo.Repo, policies map[ratelimit.Scope]ratelimit.Bucket) Service {
+	return &rateLimitService{
+		buckets:  buckets,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// Take spends a token from every key's bucket, stopping at the first one that is empty.
+func (r *rateLimitService) Take(ctx context.Context, keys ...ratelimit.Key) error {
+	now := r.now()
+
+	for _, key := range keys {
+		bucket, ok := r.policies[key.Scope]
+		if !ok {
+			continue
+		}
+
+		allowed, tokens, err := r.buckets.Take(ctx, key, bucket, now)
+		if err != nil {
+			return fmt.Errorf("RateLimit.Service.Take taking token: %w", err)
+		}
+
+		if !allowed {
+			rejectedTotal.WithLabelValues(key.Scope.String()).Inc()
+
+			retryAfter := bucket.RetryAfter(tokens).Round(time.Millisecond)
+			if retryAfter < time.Millisecond {
+				retryAfter = time.Millisecond
+			}
+
+			return ratelimit.ErrRateLimited.WithRetryAfter(retryAfter)
+		}
+	}
+
+	return nil
+}
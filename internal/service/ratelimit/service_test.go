@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/ratelimit"
+	rateLimitRepo "github.com/monobearotaku/online-chat-api/internal/repository/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestService(now *time.Time) *rateLimitService {
+	return &rateLimitService{
+		buckets: rateLimitRepo.NewMemoryRateLimitRepo(),
+		policies: map[ratelimit.Scope]ratelimit.Bucket{
+			ratelimit.ScopeUser: {Capacity: 3, PerSecond: 1},
+			ratelimit.ScopeChat: {Capacity: 4, PerSecond: 2},
+		},
+		now: func() time.Time {
+			return *now
+		},
+	}
+}
+
+func Test_rateLimitService_Take(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	key := ratelimit.UserKey(1)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.Take(ctx, key))
+	}
+
+	err := s.Take(ctx, key)
+	assert.ErrorIs(t, err, ratelimit.ErrRateLimited)
+
+	domainErr, ok := err.(interface{ RetryAfter() time.Duration })
+	if assert.True(t, ok) {
+		assert.Equal(t, time.Second, domainErr.RetryAfter())
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	assert.ErrorIs(t, s.Take(ctx, key), ratelimit.ErrRateLimited)
+
+	now = now.Add(500 * time.Millisecond)
+	assert.NoError(t, s.Take(ctx, key))
+	assert.ErrorIs(t, s.Take(ctx, key), ratelimit.ErrRateLimited)
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.Take(ctx, key))
+	}
+}
+
+func Test_rateLimitService_sharedChatBucket(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now()
+	s := newTestService(&now)
+	chat := ratelimit.ChatKey(7)
+
+	for userID := int64(1); userID <= 4; userID++ {
+		assert.NoError(t, s.Take(ctx, ratelimit.UserKey(userID), chat))
+	}
+
+	assert.ErrorIs(t, s.Take(ctx, ratelimit.UserKey(5), chat), ratelimit.ErrRateLimited)
+	assert.NoError(t, s.Take(ctx, ratelimit.UserKey(5)))
+}
@@ -0,0 +1,117 @@
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+)
+
+type archivedMessage struct {
+	ID        int64     `json:"id"`
+	ChatID    int64     `json:"chatId"`
+	UserID    int64     `json:"userId"`
+	Login     string    `json:"login"`
+	Text      string    `json:"text"`
+	Kind      string    `json:"kind"`
+	Bot       bool      `json:"bot"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type fileArchiver struct {
+	dir string
+}
+
+// NewFileArchiver writes gzip-compressed JSONL files under dir, one directory per chat.
+func NewFileArchiver(dir string) Archiver {
+	return &fileArchiver{
+		dir: dir,
+	}
+}
+
+// Archive writes one <firstID>-<lastID>.jsonl.gz file per chat in the batch. Files are synced and renamed
+// into place, so a crash never leaves a partial archive; a batch retried after a failed delete rewrites its file.
+func (f *fileArchiver) Archive(ctx context.Context, messages []chat.Message) error {
+	byChat := make(map[int64][]chat.Message)
+	for _, msg := range messages {
+		byChat[msg.ChatID] = append(byChat[msg.ChatID], msg)
+	}
+
+	for chatID, chatMessages := range byChat {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := f.writeFile(chatID, chatMessages)
+		if err != nil {
+			return fmt.Errorf("archiving chat %d: %w", chatID, err)
+		}
+	}
+
+	return nil
+}
+
+func (f *fileArchiver) writeFile(chatID int64, messages []chat.Message) (err error) {
+	dir := filepath.Join(f.dir, strconv.FormatInt(chatID, 10))
+
+	err = os.MkdirAll(dir, 0o750)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%d.jsonl.gz", messages[0].ID, messages[len(messages)-1].ID)
+
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	zw := gzip.NewWriter(tmp)
+	enc := json.NewEncoder(zw)
+
+	for _, msg := range messages {
+		err = enc.Encode(archivedMessage{
+			ID:        msg.ID,
+			ChatID:    msg.ChatID,
+			UserID:    msg.UserID,
+			Login:     msg.Login,
+			Text:      msg.Msg,
+			Kind:      string(msg.Kind),
+			Bot:       msg.Bot,
+			CreatedAt: msg.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
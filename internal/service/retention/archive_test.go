@@ -0,0 +1,83 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FileArchiver_Archive(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	createdAt := time.Date(2024, 4, 13, 18, 5, 41, 0, time.UTC)
+
+	err := NewFileArchiver(dir).Archive(context.Background(), []chat.Message{
+		{ID: 1, ChatID: 7, UserID: 3, Login: "alice", Msg: "hello", Kind: chat.KindText, CreatedAt: createdAt},
+		{ID: 2, ChatID: 8, UserID: 4, Login: "bob", Msg: "hi", Kind: chat.KindText, CreatedAt: createdAt},
+		{ID: 5, ChatID: 7, UserID: 3, Login: "alice", Msg: "waves", Kind: chat.KindAction, CreatedAt: createdAt},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		path string
+		want []archivedMessage
+	}{
+		{
+			name: "chat with two messages",
+			path: filepath.Join("7", "1-5.jsonl.gz"),
+			want: []archivedMessage{
+				{ID: 1, ChatID: 7, UserID: 3, Login: "alice", Text: "hello", Kind: "text", CreatedAt: createdAt},
+				{ID: 5, ChatID: 7, UserID: 3, Login: "alice", Text: "waves", Kind: "action", CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "chat with one message",
+			path: filepath.Join("8", "2-2.jsonl.gz"),
+			want: []archivedMessage{
+				{ID: 2, ChatID: 8, UserID: 4, Login: "bob", Text: "hi", Kind: "text", CreatedAt: createdAt},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := os.Open(filepath.Join(dir, tt.path))
+			require.NoError(t, err)
+			defer file.Close()
+
+			zr, err := gzip.NewReader(file)
+			require.NoError(t, err)
+
+			got := make([]archivedMessage, 0)
+
+			scanner := bufio.NewScanner(zr)
+			for scanner.Scan() {
+				msg := archivedMessage{}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+				got = append(got, msg)
+			}
+
+			require.NoError(t, scanner.Err())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*", "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
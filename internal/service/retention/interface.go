@@ -0,0 +1,17 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+)
+
+type Service interface {
+	// Enforce deletes the messages outside their retention policy in batches and returns how many it removed.
+	Enforce(ctx context.Context) (int, error)
+}
+
+// Archiver keeps a copy of messages before retention deletes them.
+type Archiver interface {
+	Archive(ctx context.Context, messages []chat.Message) error
+}
@@ -0,0 +1,121 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	chatRepo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const batchSize = 500
+
+var deletedTotal = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "chat_retention_deleted_messages_total",
+	Help: "Number of messages deleted by retention policies.",
+})
+
+type retentionService struct {
+	chat       chatRepo.Repo
+	archiver   Archiver
+	global     retention.Policy
+	txBeginner postgres.TxBeginner
+}
+
+// NewRetentionService enforces the global policy and per-chat overrides; a nil archiver deletes without a copy.
+func NewRetentionService(chat chatRepo.Repo, archiver Archiver, global retention.Policy, txBeginner postgres.TxBeginner) Service {
+	return &retentionService{
+		chat:       chat,
+		archiver:   archiver,
+		global:     global,
+		txBeginner: txBeginner,
+	}
+}
+
+// Enforce computes every chat's cutoff once and then claims, archives and deletes up to it in batches, each in
+// a short transaction whose row locks other replicas skip. Messages are deleted only after the archive is on
+// disk; live streams are not notified because retention removes history rather than recent conversation.
+func (r *retentionService) Enforce(ctx context.Context) (int, error) {
+	cutoffs, err := r.chat.GetRetentionCutoffs(ctx, r.global)
+	if err != nil {
+		return 0, fmt.Errorf("Retention.Service.Enforce getting cutoffs: %w", err)
+	}
+
+	processed := 0
+
+	for _, cutoff := range cutoffs {
+		for {
+			if ctx.Err() != nil {
+				return processed, fmt.Errorf("Retention.Service.Enforce: %w", ctx.Err())
+			}
+
+			deleted, err := r.enforceBatch(ctx, cutoff)
+			processed += deleted
+
+			if err != nil {
+				return processed, fmt.Errorf("Retention.Service.Enforce chat %d: %w", cutoff.ChatID, err)
+			}
+
+			if deleted < batchSize {
+				break
+			}
+		}
+	}
+
+	return processed, nil
+}
+
+func (r *retentionService) enforceBatch(ctx context.Context, cutoff retention.Cutoff) (processed int, err error) {
+	tx, err := r.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			processed = 0
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			processed = 0
+			return
+		}
+
+		deletedTotal.Add(float64(processed))
+	}()
+
+	messages, err := r.chat.WithTx(tx).ClaimRetained(ctx, cutoff, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claiming messages: %w", err)
+	}
+
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	if r.archiver != nil {
+		err = r.archiver.Archive(ctx, messages)
+		if err != nil {
+			return 0, fmt.Errorf("archiving messages: %w", err)
+		}
+	}
+
+	ids := make([]int64, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+
+	err = r.chat.WithTx(tx).DeleteMessages(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("deleting messages: %w", err)
+	}
+
+	return len(messages), nil
+}
@@ -0,0 +1,181 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/retention"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	chatRepo "github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTx struct {
+	postgres.QueryExecer
+
+	committed  *int
+	rolledBack *int
+}
+
+func (f fakeTx) Commit(ctx context.Context) error {
+	*f.committed++
+
+	return nil
+}
+
+func (f fakeTx) Rollback(ctx context.Context) error {
+	*f.rolledBack++
+
+	return nil
+}
+
+type fakeTxBeginner struct {
+	committed  int
+	rolledBack int
+}
+
+func (f *fakeTxBeginner) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (postgres.Tx, error) {
+	return fakeTx{committed: &f.committed, rolledBack: &f.rolledBack}, nil
+}
+
+// fakeChats holds messages in memory and serves fixed cutoffs.
+type fakeChats struct {
+	chatRepo.Repo
+
+	cutoffs     []retention.Cutoff
+	cutoffCalls int
+
+	messages []chat.Message
+}
+
+func (f *fakeChats) WithTx(tx postgres.Tx) chatRepo.Repo {
+	return f
+}
+
+func (f *fakeChats) GetRetentionCutoffs(ctx context.Context, global retention.Policy) ([]retention.Cutoff, error) {
+	f.cutoffCalls++
+
+	return f.cutoffs, nil
+}
+
+func (f *fakeChats) ClaimRetained(ctx context.Context, cutoff retention.Cutoff, limit int) ([]chat.Message, error) {
+	claimed := make([]chat.Message, 0)
+
+	for _, msg := range f.messages {
+		if len(claimed) == limit {
+			break
+		}
+
+		if msg.ChatID == cutoff.ChatID && (msg.CreatedAt.Before(cutoff.At) || msg.ID <= cutoff.ID) {
+			claimed = append(claimed, msg)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (f *fakeChats) DeleteMessages(ctx context.Context, messageIDs []int64) error {
+	deleted := make(map[int64]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		deleted[id] = true
+	}
+
+	kept := f.messages[:0]
+	for _, msg := range f.messages {
+		if !deleted[msg.ID] {
+			kept = append(kept, msg)
+		}
+	}
+
+	f.messages = kept
+
+	return nil
+}
+
+type fakeArchiver struct {
+	archived int
+	err      error
+}
+
+func (f *fakeArchiver) Archive(ctx context.Context, messages []chat.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.archived += len(messages)
+
+	return nil
+}
+
+func Test_retentionService_Enforce(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 4, 13, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		archiveErr    error
+		wantErr       bool
+		wantProcessed int
+		wantLeft      []int64
+	}{
+		{name: "deletes every chat up to its cutoff", wantProcessed: batchSize + 3, wantLeft: []int64{batchSize + 3, 2000}},
+		{name: "archive failure keeps messages", archiveErr: errors.New("disk full"), wantErr: true, wantProcessed: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Chat 1 keeps messages after ID batchSize+2, which takes two batches; chat 2 keeps the last day.
+			messages := make([]chat.Message, 0, batchSize+5)
+			for id := int64(1); id <= batchSize+3; id++ {
+				messages = append(messages, chat.Message{ID: id, ChatID: 1, CreatedAt: now})
+			}
+
+			messages = append(messages,
+				chat.Message{ID: 1000, ChatID: 2, CreatedAt: now.AddDate(0, 0, -2)},
+				chat.Message{ID: 2000, ChatID: 2, CreatedAt: now},
+			)
+
+			chats := &fakeChats{
+				cutoffs: []retention.Cutoff{
+					{ChatID: 1, ID: batchSize + 2},
+					{ChatID: 2, At: now.AddDate(0, 0, -1)},
+				},
+				messages: messages,
+			}
+			archiver := &fakeArchiver{err: tt.archiveErr}
+			txBeginner := &fakeTxBeginner{}
+
+			processed, err := NewRetentionService(chats, archiver, retention.Policy{}, txBeginner).Enforce(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, 1, txBeginner.rolledBack)
+				assert.Len(t, chats.messages, len(messages))
+			} else {
+				require.NoError(t, err)
+
+				left := make([]int64, 0, len(chats.messages))
+				for _, msg := range chats.messages {
+					left = append(left, msg.ID)
+				}
+
+				assert.Equal(t, tt.wantLeft, left)
+				assert.Equal(t, tt.wantProcessed, archiver.archived)
+				assert.Equal(t, 3, txBeginner.committed, "two batches for chat 1 and one for chat 2")
+				assert.Zero(t, txBeginner.rolledBack)
+			}
+
+			assert.Equal(t, tt.wantProcessed, processed)
+			assert.Equal(t, 1, chats.cutoffCalls, "cutoffs are computed once per pass")
+		})
+	}
+}
@@ -8,8 +8,8 @@ import (
 )
 
 type Tokenizer interface {
-	CreateToken(context.Context, data.TokenData) (token.Token, error)
-	ValidateToken(context.Context, token.Token) error
-	extractTokenData(context.Context, token.Token) (data.TokenData, error)
-	ValidateAndExtractData(context.Context, token.Token) (data.TokenData, error)
+	CreateToken(ctx context.Context, audience token.Audience, subject string, tokenData data.TokenData) (token.Token, error)
+	ValidateToken(context.Context, token.Token, token.Audience) error
+	extractTokenData(context.Context, token.Token, token.Audience) (data.TokenData, error)
+	ValidateAndExtractData(context.Context, token.Token, token.Audience) (data.TokenData, error)
 }
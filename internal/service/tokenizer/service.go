@@ -7,15 +7,14 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/monobearotaku/online-chat-api/internal/domain/token"
 	"github.com/monobearotaku/online-chat-api/internal/domain/token/data"
-	"github.com/monobearotaku/online-chat-api/internal/pkg/slices/timeconv"
 )
 
 const (
-	hmacSampleSecret  = "HelloWorld"
-	validUntilKey     = "validUntil"
-	tokenValidityTime = 72 * time.Hour
+	hmacSampleSecret = "HelloWorld"
+	issuer           = "online-chat-api"
 )
 
 type tokenizer struct{}
@@ -24,10 +23,19 @@ func NewTokenizer() Tokenizer {
 	return &tokenizer{}
 }
 
-func (t *tokenizer) CreateToken(ctx context.Context, tokenData data.TokenData) (token.Token, error) {
-	tokenData[validUntilKey] = timeconv.TimeToString(time.Now().Add(tokenValidityTime))
+func (t *tokenizer) CreateToken(ctx context.Context, audience token.Audience, subject string, tokenData data.TokenData) (token.Token, error) {
+	now := time.Now()
 
-	tkn := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenData.ToMap())
+	claims := tokenData.ToMap()
+	claims["iss"] = issuer
+	claims["sub"] = subject
+	claims["aud"] = jwt.ClaimStrings{audience.String()}
+	claims["iat"] = jwt.NewNumericDate(now)
+	claims["nbf"] = jwt.NewNumericDate(now)
+	claims["exp"] = jwt.NewNumericDate(now.Add(audience.ValidityTime()))
+	claims["jti"] = uuid.NewString()
+
+	tkn := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := tkn.SignedString([]byte(hmacSampleSecret))
 	if err != nil {
@@ -37,48 +45,46 @@ func (t *tokenizer) CreateToken(ctx context.Context, tokenData data.TokenData) (
 	return token.Token(tokenString), nil
 }
 
-func (t *tokenizer) ValidateToken(ctx context.Context, strToken token.Token) error {
-	jwtClaims := jwt.MapClaims{}
-
-	_, err := jwt.ParseWithClaims(strToken.String(), &jwtClaims, func(t *jwt.Token) (interface{}, error) {
-		return []byte(hmacSampleSecret), nil
-	})
-	if err != nil {
-		return token.ErrInvalidToken
-	}
-
-	mapClaims := data.TokenDataFromMap(jwtClaims)
-	valid, ok := mapClaims[validUntilKey]
-
-	if !ok || timeconv.StringToTime(valid).Before(time.Now()) {
-		return token.ErrTokenExpired
-	}
-
-	return nil
+func (t *tokenizer) ValidateToken(ctx context.Context, strToken token.Token, audience token.Audience) error {
+	_, err := t.extractTokenData(ctx, strToken, audience)
+	return err
 }
 
-func (t *tokenizer) extractTokenData(ctx context.Context, strToken token.Token) (data.TokenData, error) {
+func (t *tokenizer) extractTokenData(ctx context.Context, strToken token.Token, audience token.Audience) (data.TokenData, error) {
 	jwtClaims := jwt.MapClaims{}
 
 	_, err := jwt.ParseWithClaims(strToken.String(), &jwtClaims, func(t *jwt.Token) (interface{}, error) {
 		return []byte(hmacSampleSecret), nil
-	})
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience.String()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	if err != nil {
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			return data.TokenData{}, token.ErrTokenExpired
+		case errors.Is(err, jwt.ErrTokenInvalidAudience):
+			return data.TokenData{}, token.ErrWrongAudience
+		default:
+			return data.TokenData{}, token.ErrInvalidToken
+		}
+	}
+
+	tokenData := data.TokenDataFromMap(jwtClaims)
+	if tokenData.Subject() == "" {
 		return data.TokenData{}, token.ErrInvalidToken
 	}
 
-	return data.TokenDataFromMap(jwtClaims), nil
+	return tokenData, nil
 }
 
-func (t *tokenizer) ValidateAndExtractData(ctx context.Context, tokenStr token.Token) (data.TokenData, error) {
-	err := t.ValidateToken(ctx, tokenStr)
-	if err != nil {
-		return data.TokenData{}, err
-	}
-
-	tokenData, err := t.extractTokenData(ctx, tokenStr)
+func (t *tokenizer) ValidateAndExtractData(ctx context.Context, tokenStr token.Token, audience token.Audience) (data.TokenData, error) {
+	tokenData, err := t.extractTokenData(ctx, tokenStr, audience)
 	if err != nil {
-		if errors.Is(err, token.ErrInvalidToken) {
+		if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, token.ErrTokenExpired) || errors.Is(err, token.ErrWrongAudience) {
 			return data.TokenData{}, err
 		}
 
@@ -14,22 +14,51 @@ func Test_tokenizer_ValidateToken(t *testing.T) {
 
 	tr := NewTokenizer()
 	ctx := context.Background()
-	tkn, _ := tr.CreateToken(ctx, data.TokenData{})
+	authTkn, _ := tr.CreateToken(ctx, token.AudienceAuth, "1", data.TokenData{})
+	sessionTkn, _ := tr.CreateToken(ctx, token.AudienceSession, "1", data.TokenData{})
+	noSubjectTkn, _ := tr.CreateToken(ctx, token.AudienceAuth, "", data.TokenData{})
 
 	tests := []struct {
-		name  string
-		token token.Token
-		err   error
+		name     string
+		token    token.Token
+		audience token.Audience
+		err      error
 	}{
 		{
-			name:  "Valid Token",
-			token: tkn,
-			err:   nil,
+			name:     "Valid Token",
+			token:    authTkn,
+			audience: token.AudienceAuth,
+			err:      nil,
 		},
 		{
-			name:  "Invalid Token",
-			token: "",
-			err:   token.ErrInvalidToken,
+			name:     "Valid Session Token",
+			token:    sessionTkn,
+			audience: token.AudienceSession,
+			err:      nil,
+		},
+		{
+			name:     "Session Token Used As Auth",
+			token:    sessionTkn,
+			audience: token.AudienceAuth,
+			err:      token.ErrWrongAudience,
+		},
+		{
+			name:     "Auth Token Used As Session",
+			token:    authTkn,
+			audience: token.AudienceSession,
+			err:      token.ErrWrongAudience,
+		},
+		{
+			name:     "Token Without Subject",
+			token:    noSubjectTkn,
+			audience: token.AudienceAuth,
+			err:      token.ErrInvalidToken,
+		},
+		{
+			name:     "Invalid Token",
+			token:    "",
+			audience: token.AudienceAuth,
+			err:      token.ErrInvalidToken,
 		},
 	}
 
@@ -39,8 +68,30 @@ func Test_tokenizer_ValidateToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			err := tr.ValidateToken(ctx, tt.token)
+			err := tr.ValidateToken(ctx, tt.token, tt.audience)
 			assert.Equal(t, tt.err, err)
 		})
 	}
 }
+
+func Test_tokenizer_ValidateAndExtractData(t *testing.T) {
+	t.Parallel()
+
+	tr := NewTokenizer()
+	ctx := context.Background()
+
+	tkn, err := tr.CreateToken(ctx, token.AudienceSession, "42", data.TokenData{
+		data.ChatIDKey: "7",
+	})
+	assert.NoError(t, err)
+
+	tokenData, err := tr.ValidateAndExtractData(ctx, tkn, token.AudienceSession)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "42", tokenData.Subject())
+	assert.Equal(t, "7", tokenData[data.ChatIDKey])
+	assert.Equal(t, issuer, tokenData["iss"])
+	assert.NotEmpty(t, tokenData["jti"])
+	assert.NotEmpty(t, tokenData["exp"])
+	assert.NotEmpty(t, tokenData["iat"])
+}
@@ -0,0 +1,14 @@
+package user
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+)
+
+type Service interface {
+	GetUser(ctx context.Context, userID int64) (user.Profile, error)
+	GetUsers(ctx context.Context, userIDs []int64) ([]user.Profile, error)
+	SearchUsers(ctx context.Context, loginPrefix string, limit int) ([]user.Profile, error)
+	UpdateProfile(ctx context.Context, profile user.Profile) (user.Profile, error)
+}
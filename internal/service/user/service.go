@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain"
+	"github.com/monobearotaku/online-chat-api/internal/domain/user"
+	userRepo "github.com/monobearotaku/online-chat-api/internal/repository/user"
+)
+
+type userService struct {
+	user userRepo.Repo
+}
+
+func NewUserService(user userRepo.Repo) Service {
+	return &userService{
+		user: user,
+	}
+}
+
+func (s *userService) GetUser(ctx context.Context, userID int64) (user.Profile, error) {
+	profile, err := s.user.GetProfile(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return user.Profile{}, err
+		}
+
+		return user.Profile{}, fmt.Errorf("User.Service.GetUser getting profile: %w", err)
+	}
+
+	return profile, nil
+}
+
+func (s *userService) GetUsers(ctx context.Context, userIDs []int64) ([]user.Profile, error) {
+	if len(userIDs) > user.MaxBatchSize {
+		return nil, user.ErrTooManyUsers
+	}
+
+	if len(userIDs) == 0 {
+		return []user.Profile{}, nil
+	}
+
+	profiles, err := s.user.GetProfiles(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("User.Service.GetUsers getting profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (s *userService) SearchUsers(ctx context.Context, loginPrefix string, limit int) ([]user.Profile, error) {
+	if loginPrefix == "" {
+		return nil, user.ErrEmptySearchPrefix
+	}
+
+	limit, err := user.SearchLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := s.user.SearchByLoginPrefix(ctx, loginPrefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("User.Service.SearchUsers searching profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, profile user.Profile) (user.Profile, error) {
+	err := profile.Validate()
+	if err != nil {
+		return user.Profile{}, err
+	}
+
+	profile, err = s.user.UpdateProfile(ctx, profile)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return user.Profile{}, err
+		}
+
+		return user.Profile{}, fmt.Errorf("User.Service.UpdateProfile updating profile: %w", err)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,18 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
+)
+
+type Service interface {
+	CreateWebhook(ctx context.Context, ownerID, chatID int64, url string, events []event.Type) (webhook.Webhook, error)
+	GetWebhooks(ctx context.Context, ownerID, chatID int64) ([]webhook.Webhook, error)
+	DeleteWebhook(ctx context.Context, ownerID, chatID, webhookID int64) error
+	EnableWebhook(ctx context.Context, ownerID, chatID, webhookID int64) error
+	GetDeliveries(ctx context.Context, ownerID, chatID, webhookID int64, limit int) ([]webhook.Delivery, error)
+	Enqueue(ctx context.Context, evt event.Event) error
+	DeliverDue(ctx context.Context) (int, error)
+}
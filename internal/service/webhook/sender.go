@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	userAgent      = "online-chat-api-webhooks/1"
+)
+
+var errForbiddenAddress = errors.New("webhook address is not publicly routable")
+
+// NewHTTPClient builds the client used for deliveries. Unless allowPrivate is set it refuses to
+// connect to loopback, private and link-local addresses so webhooks cannot reach internal services.
+func NewHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+	}
+
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			addr := addrPort.Addr().Unmap()
+			if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+				return errForbiddenAddress
+			}
+
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// send posts a delivery and returns the response status code; any non-2xx answer is an error.
+func send(ctx context.Context, client *http.Client, delivery webhook.Delivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, delivery.EventID)
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(delivery.Secret, now, delivery.Payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
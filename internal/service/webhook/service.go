@@ -0,0 +1,317 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	chatDomain "github.com/monobearotaku/online-chat-api/internal/domain/chat"
+	"github.com/monobearotaku/online-chat-api/internal/domain/event"
+	"github.com/monobearotaku/online-chat-api/internal/domain/webhook"
+	"github.com/monobearotaku/online-chat-api/internal/postgres"
+	"github.com/monobearotaku/online-chat-api/internal/repository/chat"
+	webhookRepo "github.com/monobearotaku/online-chat-api/internal/repository/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	claimBatch   = 20
+	claimLease   = time.Minute
+	maxErrorText = 512
+	defaultLimit = 50
+	maxLimit     = 200
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+)
+
+var (
+	attemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_delivery_attempts_total",
+		Help: "Number of webhook delivery attempts, by result (delivered, retry, failed once retries are exhausted).",
+	}, []string{"result"})
+)
+
+type webhookService struct {
+	webhooks   webhookRepo.Repo
+	chat       chat.Repo
+	client     *http.Client
+	policy     webhook.Policy
+	txBeginner postgres.TxBeginner
+	now        func() time.Time
+}
+
+func NewWebhookService(webhooks webhookRepo.Repo, chat chat.Repo, client *http.Client, policy webhook.Policy, txBeginner postgres.TxBeginner) Service {
+	return &webhookService{
+		webhooks:   webhooks,
+		chat:       chat,
+		client:     client,
+		policy:     policy,
+		txBeginner: txBeginner,
+		now:        time.Now,
+	}
+}
+
+func (w *webhookService) CreateWebhook(ctx context.Context, ownerID, chatID int64, url string, events []event.Type) (hook webhook.Webhook, err error) {
+	err = webhook.ValidateURL(url)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	if len(events) == 0 {
+		return webhook.Webhook{}, webhook.ErrNoEvents
+	}
+
+	err = w.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	raw := make([]byte, secretBytes)
+
+	_, err = rand.Read(raw)
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("Webhook.Service.CreateWebhook generating secret: %w", err)
+	}
+
+	tx, err := w.txBeginner.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("Webhook.Service.CreateWebhook begin tx: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+
+		err = tx.Commit(ctx)
+	}()
+
+	// The chat's row lock makes concurrent requests count webhooks one at a time.
+	err = w.chat.WithTx(tx).LockChat(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return webhook.Webhook{}, err
+		}
+
+		return webhook.Webhook{}, fmt.Errorf("Webhook.Service.CreateWebhook locking chat: %w", err)
+	}
+
+	count, err := w.webhooks.WithTx(tx).Count(ctx, chatID)
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("Webhook.Service.CreateWebhook counting webhooks: %w", err)
+	}
+
+	if count >= webhook.MaxWebhooksPerChat {
+		return webhook.Webhook{}, webhook.ErrTooMany
+	}
+
+	hook, err = w.webhooks.WithTx(tx).Create(ctx, webhook.Webhook{
+		ChatID:    chatID,
+		URL:       url,
+		Secret:    secretPrefix + hex.EncodeToString(raw),
+		Events:    events,
+		CreatedBy: ownerID,
+	})
+	if err != nil {
+		return webhook.Webhook{}, fmt.Errorf("Webhook.Service.CreateWebhook saving webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+func (w *webhookService) GetWebhooks(ctx context.Context, ownerID, chatID int64) ([]webhook.Webhook, error) {
+	err := w.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks, err := w.webhooks.GetByChat(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook.Service.GetWebhooks getting webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+func (w *webhookService) DeleteWebhook(ctx context.Context, ownerID, chatID, webhookID int64) error {
+	err := w.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = w.webhooks.Delete(ctx, chatID, webhookID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Webhook.Service.DeleteWebhook deleting webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (w *webhookService) EnableWebhook(ctx context.Context, ownerID, chatID, webhookID int64) error {
+	err := w.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return err
+	}
+
+	err = w.webhooks.Enable(ctx, chatID, webhookID)
+	if err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Webhook.Service.EnableWebhook enabling webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (w *webhookService) GetDeliveries(ctx context.Context, ownerID, chatID, webhookID int64, limit int) ([]webhook.Delivery, error) {
+	err := w.checkOwner(ctx, ownerID, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	deliveries, err := w.webhooks.GetDeliveries(ctx, chatID, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Webhook.Service.GetDeliveries getting deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// Enqueue records a pending delivery for every enabled webhook subscribed to the event;
+// redelivered events are ignored thanks to the (webhook, event) uniqueness.
+func (w *webhookService) Enqueue(ctx context.Context, evt event.Event) error {
+	hooks, err := w.webhooks.GetSubscribed(ctx, evt.ChatID, evt.Type)
+	if err != nil {
+		return fmt.Errorf("Webhook.Service.Enqueue getting webhooks: %w", err)
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := webhook.Payload(evt)
+	if err != nil {
+		return fmt.Errorf("Webhook.Service.Enqueue building payload: %w", err)
+	}
+
+	deliveries := make([]webhook.Delivery, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveries = append(deliveries, webhook.Delivery{
+			WebhookID: hook.ID,
+			EventID:   evt.ID,
+			EventType: string(evt.Type),
+			Payload:   payload,
+		})
+	}
+
+	err = w.webhooks.Enqueue(ctx, deliveries)
+	if err != nil {
+		return fmt.Errorf("Webhook.Service.Enqueue saving deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverDue sends one batch of due deliveries and reports how many were attempted.
+func (w *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := w.webhooks.ClaimDue(ctx, w.now(), claimBatch, claimLease)
+	if err != nil {
+		return 0, fmt.Errorf("Webhook.Service.DeliverDue claiming deliveries: %w", err)
+	}
+
+	errs := make([]error, len(deliveries))
+
+	wg := sync.WaitGroup{}
+	for i, delivery := range deliveries {
+		wg.Add(1)
+
+		go func(i int, delivery webhook.Delivery) {
+			defer wg.Done()
+			errs[i] = w.deliver(ctx, delivery)
+		}(i, delivery)
+	}
+
+	wg.Wait()
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return len(deliveries), fmt.Errorf("Webhook.Service.DeliverDue recording results: %w", err)
+	}
+
+	return len(deliveries), nil
+}
+
+func (w *webhookService) deliver(ctx context.Context, delivery webhook.Delivery) error {
+	statusCode, sendErr := send(ctx, w.client, delivery, w.now())
+	if sendErr == nil {
+		attemptsTotal.WithLabelValues("delivered").Inc()
+
+		err := w.webhooks.MarkDelivered(ctx, delivery.ID, statusCode)
+		if err != nil {
+			return err
+		}
+
+		return w.webhooks.ResetFailures(ctx, delivery.WebhookID)
+	}
+
+	attempts := delivery.Attempts + 1
+	final := attempts >= w.policy.MaxAttempts
+
+	reason := sendErr.Error()
+	if len(reason) > maxErrorText {
+		reason = reason[:maxErrorText]
+	}
+
+	err := w.webhooks.MarkFailed(ctx, delivery.ID, statusCode, reason, w.now().Add(w.policy.Backoff(attempts)), final)
+	if err != nil {
+		return err
+	}
+
+	if !final {
+		attemptsTotal.WithLabelValues("retry").Inc()
+		return nil
+	}
+
+	attemptsTotal.WithLabelValues("failed").Inc()
+
+	return w.webhooks.RegisterFailure(ctx, delivery.WebhookID, w.policy.DisableAfter)
+}
+
+func (w *webhookService) checkOwner(ctx context.Context, ownerID, chatID int64) error {
+	chtUsers, err := w.chat.GetChatUsers(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, chatDomain.ErrChatNotFound) {
+			return err
+		}
+
+		return fmt.Errorf("Webhook.Service getting chat users: %w", err)
+	}
+
+	if !chtUsers.IsOwner(ownerID) {
+		return chatDomain.ErrUserNotOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS owner_id BIGINT REFERENCES users(id);
+
+CREATE TABLE IF NOT EXISTS api_keys(
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    prefix TEXT NOT NULL UNIQUE,
+    key_hash TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS owner_id,
+    DROP COLUMN IF EXISTS is_bot;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- +goose StatementBegin
+CREATE INDEX CONCURRENTLY IF NOT EXISTS users_owner_id_idx ON users(owner_id) WHERE owner_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_owner_id_idx;
+-- +goose StatementEnd
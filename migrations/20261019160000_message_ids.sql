@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing messages get IDs below the sequence's start, so new messages stay after them in ID order while
+-- the backfill runs. Writes wait for the count; reads do not. The column has no default when added, so
+-- adding it does not rewrite the table.
+LOCK TABLE messages IN SHARE MODE;
+
+CREATE SEQUENCE IF NOT EXISTS messages_id_seq;
+SELECT setval('messages_id_seq', (SELECT count(*) FROM messages) + 1, false);
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS id BIGINT;
+ALTER SEQUENCE messages_id_seq OWNED BY messages.id;
+ALTER TABLE messages ALTER COLUMN id SET DEFAULT nextval('messages_id_seq');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE messages DROP COLUMN IF EXISTS id;
+DROP SEQUENCE IF EXISTS messages_id_seq;
+-- +goose StatementEnd
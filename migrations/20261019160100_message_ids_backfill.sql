@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- +goose StatementBegin
+-- Numbers existing messages oldest first in batches, committing each one so row locks stay short.
+-- Messages without a creation time are numbered last.
+DO $$
+DECLARE
+    next_id BIGINT := 0;
+    last_at TIMESTAMPTZ := '-infinity';
+    updated BIGINT;
+BEGIN
+    LOOP
+        WITH batch AS (
+            SELECT ctid, row_number() OVER (ORDER BY created_at) AS n
+            FROM messages
+            WHERE id IS NULL AND created_at > last_at
+            ORDER BY created_at
+            LIMIT 10000
+        ), numbered AS (
+            UPDATE messages m
+            SET id = next_id + b.n
+            FROM batch b
+            WHERE m.ctid = b.ctid
+            RETURNING m.created_at
+        )
+        SELECT count(*), max(created_at) INTO updated, last_at FROM numbered;
+
+        EXIT WHEN updated = 0;
+
+        next_id := next_id + updated;
+        COMMIT;
+    END LOOP;
+
+    LOOP
+        WITH batch AS (
+            SELECT ctid, row_number() OVER () AS n
+            FROM messages
+            WHERE id IS NULL
+            LIMIT 10000
+        )
+        UPDATE messages m
+        SET id = next_id + b.n
+        FROM batch b
+        WHERE m.ctid = b.ctid;
+
+        GET DIAGNOSTICS updated = ROW_COUNT;
+        EXIT WHEN updated = 0;
+
+        next_id := next_id + updated;
+        COMMIT;
+    END LOOP;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 1;
+-- +goose StatementEnd
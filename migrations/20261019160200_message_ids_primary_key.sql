@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- +goose StatementBegin
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS messages_pkey ON messages(id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- A validated check lets SET NOT NULL skip its table scan; validating only blocks schema changes.
+ALTER TABLE messages ADD CONSTRAINT messages_id_not_null CHECK (id IS NOT NULL) NOT VALID;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE messages VALIDATE CONSTRAINT messages_id_not_null;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE messages ALTER COLUMN id SET NOT NULL;
+ALTER TABLE messages DROP CONSTRAINT messages_id_not_null;
+ALTER TABLE messages ADD CONSTRAINT messages_pkey PRIMARY KEY USING INDEX messages_pkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_pkey;
+ALTER TABLE messages ALTER COLUMN id DROP NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS incoming_webhooks(
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    token_hash TEXT NOT NULL,
+    rate_limit INT NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL DEFAULT now(),
+    window_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS incoming_webhooks_chat_id_idx ON incoming_webhooks(chat_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS incoming_webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS topic TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE users_to_chats ADD COLUMN IF NOT EXISTS muted_until TIMESTAMPTZ;
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'text';
+
+CREATE TABLE IF NOT EXISTS chat_commands(
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    bot_id BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (chat_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS chat_commands;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE users_to_chats DROP COLUMN IF EXISTS muted_until;
+
+ALTER TABLE chats DROP COLUMN IF EXISTS topic;
+-- +goose StatementEnd
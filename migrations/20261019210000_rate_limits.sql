@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS rate_limit_buckets(
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE chats ADD COLUMN IF NOT EXISTS slow_mode_seconds INT NOT NULL DEFAULT 0;
+ALTER TABLE users_to_chats ADD COLUMN IF NOT EXISTS last_message_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users_to_chats DROP COLUMN IF EXISTS last_message_at;
+ALTER TABLE chats DROP COLUMN IF EXISTS slow_mode_seconds;
+DROP TABLE IF EXISTS rate_limit_buckets;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS message_reports(
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    message_id BIGINT NOT NULL,
+    author_id BIGINT NOT NULL REFERENCES users(id),
+    reporter_id BIGINT NOT NULL REFERENCES users(id),
+    message_text TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    resolved_by BIGINT REFERENCES users(id),
+    resolution TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (message_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS message_reports_open_idx ON message_reports(chat_id, message_id) WHERE resolved_at IS NULL;
+
+ALTER TABLE moderation_log ADD COLUMN IF NOT EXISTS message_id BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE moderation_log DROP COLUMN IF EXISTS message_id;
+DROP TABLE IF EXISTS message_reports;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS polls(
+    message_id BIGINT PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    creator_id BIGINT NOT NULL REFERENCES users(id),
+    question TEXT NOT NULL,
+    multiple BOOLEAN NOT NULL DEFAULT false,
+    anonymous BOOLEAN NOT NULL DEFAULT false,
+    closes_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS poll_options(
+    poll_id BIGINT NOT NULL REFERENCES polls(message_id) ON DELETE CASCADE,
+    idx INT NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (poll_id, idx)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes(
+    poll_id BIGINT NOT NULL,
+    idx INT NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (poll_id, user_id, idx),
+    FOREIGN KEY (poll_id, idx) REFERENCES poll_options(poll_id, idx) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users_to_chats ADD COLUMN IF NOT EXISTS can_export BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users_to_chats DROP COLUMN IF EXISTS can_export;
+-- +goose StatementEnd
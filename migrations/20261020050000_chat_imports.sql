@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS imported_chats(
+    source TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (source, external_id)
+);
+
+CREATE TABLE IF NOT EXISTS imported_users(
+    source TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    placeholder BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (source, external_id)
+);
+
+-- Rows outlive their messages so a re-run does not bring back messages removed by retention or moderation.
+CREATE TABLE IF NOT EXISTS imported_messages(
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    external_id TEXT NOT NULL,
+    message_id BIGINT REFERENCES messages(id) ON DELETE SET NULL,
+    PRIMARY KEY (chat_id, external_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS imported_messages;
+DROP TABLE IF EXISTS imported_users;
+DROP TABLE IF EXISTS imported_chats;
+-- +goose StatementEnd
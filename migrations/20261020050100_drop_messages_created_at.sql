@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- +goose StatementBegin
+-- The unique index stood in for a key before messages had IDs. Imported history keeps its own timestamps,
+-- and two messages sent in the same instant are valid, so it has to go. No query orders messages by
+-- creation time; the message ID does that.
+DROP INDEX CONCURRENTLY IF EXISTS messages_created_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS messages_created_at ON messages(created_at DESC);
+-- +goose StatementEnd
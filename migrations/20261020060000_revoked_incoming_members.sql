@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Webhooks revoked before revoking removed their bot users from the chat.
+DELETE FROM users_to_chats u
+USING incoming_webhooks w
+WHERE u.chat_id = w.chat_id AND u.user_id = w.user_id AND w.revoked_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 1;
+-- +goose StatementEnd
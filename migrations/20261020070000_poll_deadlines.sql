@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS polls_closes_at_idx ON polls(closes_at) WHERE closed_at IS NULL AND closes_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS polls_closes_at_idx;
+-- +goose StatementEnd